@@ -0,0 +1,191 @@
+// Package baseline implements baseline files, that are used to accept the findings that already exist when
+// kube-score is introduced in a project, so that only new findings affect the exit code.
+package baseline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/zegl/kube-score/config"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// FileVersion is the latest version of the baseline file format
+const FileVersion = 1
+
+// DefaultFileName is the name of the baseline file, if no other name is given
+const DefaultFileName = ".kube-score-baseline.yaml"
+
+// File is the on-disk representation of a baseline
+type File struct {
+	Version  int       `yaml:"version"`
+	Findings []Finding `yaml:"findings"`
+}
+
+// Finding is a failing check on an object. Check, Object, Path and Summary are only used to make the
+// file reviewable, findings are matched by the Fingerprint.
+type Finding struct {
+	Fingerprint string `yaml:"fingerprint"`
+	Check       string `yaml:"check"`
+	Object      string `yaml:"object"`
+	Path        string `yaml:"path,omitempty"`
+	Summary     string `yaml:"summary,omitempty"`
+}
+
+// Fingerprint identifies a finding, from the check ID, the ResourceRefKey of the object, and the path and
+// summary of the comment
+func Fingerprint(checkID, object, path, summary string) string {
+	h := sha256.New()
+	for _, s := range []string{checkID, object, path, summary} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// findings returns the findings of a failing check, one for each comment
+func findings(so *scorecard.ScoredObject, ts scorecard.TestScore) []Finding {
+	if ts.Skipped || ts.Grade > scorecard.GradeWarning {
+		return nil
+	}
+
+	object := so.ResourceRefKey()
+	newFinding := func(path, summary string) Finding {
+		return Finding{
+			Fingerprint: Fingerprint(ts.Check.ID, object, path, summary),
+			Check:       ts.Check.ID,
+			Object:      object,
+			Path:        path,
+			Summary:     summary,
+		}
+	}
+
+	if len(ts.Comments) == 0 {
+		return []Finding{newFinding("", "")}
+	}
+	res := make([]Finding, 0, len(ts.Comments))
+	for _, c := range ts.Comments {
+		res = append(res, newFinding(c.Path, c.Summary))
+	}
+	return res
+}
+
+// New creates a baseline with all warnings and criticals in the scorecard
+func New(card *scorecard.Scorecard) *File {
+	f := &File{Version: FileVersion, Findings: []Finding{}}
+	seen := make(map[string]struct{})
+	for _, so := range *card {
+		for _, ts := range so.Checks {
+			for _, finding := range findings(so, ts) {
+				if _, ok := seen[finding.Fingerprint]; ok {
+					continue
+				}
+				seen[finding.Fingerprint] = struct{}{}
+				f.Findings = append(f.Findings, finding)
+			}
+		}
+	}
+
+	sort.Slice(f.Findings, func(i, j int) bool {
+		a, b := f.Findings[i], f.Findings[j]
+		if a.Object != b.Object {
+			return a.Object < b.Object
+		}
+		if a.Check != b.Check {
+			return a.Check < b.Check
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Summary < b.Summary
+	})
+
+	return f
+}
+
+// Apply marks all failing checks where all findings are in the baseline as suppressed.
+// The findings in the baseline that didn't match any failing check are returned as stale.
+func (f *File) Apply(card *scorecard.Scorecard) (stale []Finding) {
+	matched := make(map[string]bool, len(f.Findings))
+	for _, finding := range f.Findings {
+		matched[finding.Fingerprint] = false
+	}
+
+	for _, so := range *card {
+		for i, ts := range so.Checks {
+			found := findings(so, ts)
+			if len(found) == 0 {
+				continue
+			}
+			suppressed := true
+			for _, finding := range found {
+				if _, ok := matched[finding.Fingerprint]; ok {
+					matched[finding.Fingerprint] = true
+				} else {
+					suppressed = false
+				}
+			}
+			so.Checks[i].Suppressed = suppressed
+		}
+	}
+
+	for _, finding := range f.Findings {
+		if !matched[finding.Fingerprint] {
+			stale = append(stale, finding)
+		}
+	}
+	return stale
+}
+
+// Write writes the baseline as YAML to w
+func (f *File) Write(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(f); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// LoadFile reads the baseline file at path
+func LoadFile(path string) (*File, error) {
+	fp, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+	return ParseFile(path, fp)
+}
+
+// ParseFile reads a baseline file from r, name is used in error messages
+func ParseFile(name string, r io.Reader) (*File, error) {
+	var doc yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, &config.FileError{Path: name, Err: errors.New("the file is empty")}
+		}
+		return nil, &config.FileError{Path: name, Err: err}
+	}
+
+	var f File
+	if err := config.DecodeNode(name, doc.Content[0], &f); err != nil {
+		return nil, err
+	}
+	if f.Version != FileVersion {
+		return nil, &config.FileError{Path: name, Line: doc.Content[0].Line, Err: fmt.Errorf("unsupported version %d, the latest supported version is %d", f.Version, FileVersion)}
+	}
+	for i, finding := range f.Findings {
+		if finding.Fingerprint == "" {
+			return nil, &config.FileError{Path: name, Line: doc.Content[0].Line, Err: fmt.Errorf("finding %d has no fingerprint", i)}
+		}
+	}
+
+	return &f, nil
+}
@@ -0,0 +1,124 @@
+package baseline
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+type location struct{}
+
+func (location) FileLocation() ks.FileLocation {
+	return ks.FileLocation{Name: "test.yaml", Line: 1}
+}
+
+func newCard(tests map[string][]scorecard.TestScore) *scorecard.Scorecard {
+	card := scorecard.New()
+	for name, scores := range tests {
+		o := card.NewObject(metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"}, metav1.ObjectMeta{Name: name, Namespace: "default"}, nil)
+		for _, ts := range scores {
+			o.Add(ts, ts.Check, location{})
+		}
+	}
+	return &card
+}
+
+func critical(id string, comments ...scorecard.TestScoreComment) scorecard.TestScore {
+	return scorecard.TestScore{Check: ks.Check{ID: id}, Grade: scorecard.GradeCritical, Comments: comments}
+}
+
+func TestNew(t *testing.T) {
+	card := newCard(map[string][]scorecard.TestScore{
+		"b": {critical("container-resources", scorecard.TestScoreComment{Path: "app", Summary: "CPU limit is not set"})},
+		"a": {
+			critical("pod-probes"),
+			{Check: ks.Check{ID: "container-image-tag"}, Grade: scorecard.GradeAllOK},
+			{Check: ks.Check{ID: "pod-networkpolicy"}, Grade: scorecard.GradeCritical, Skipped: true},
+		},
+	})
+
+	f := New(card)
+	assert.Equal(t, &File{
+		Version: 1,
+		Findings: []Finding{
+			{
+				Fingerprint: Fingerprint("pod-probes", "Deployment/apps/v1/default/a", "", ""),
+				Check:       "pod-probes",
+				Object:      "Deployment/apps/v1/default/a",
+			},
+			{
+				Fingerprint: Fingerprint("container-resources", "Deployment/apps/v1/default/b", "app", "CPU limit is not set"),
+				Check:       "container-resources",
+				Object:      "Deployment/apps/v1/default/b",
+				Path:        "app",
+				Summary:     "CPU limit is not set",
+			},
+		},
+	}, f)
+
+	// Round trip
+	var buf bytes.Buffer
+	assert.NoError(t, f.Write(&buf))
+	parsed, err := ParseFile("baseline.yaml", &buf)
+	assert.NoError(t, err)
+	assert.Equal(t, f, parsed)
+}
+
+func TestApply(t *testing.T) {
+	memory := scorecard.TestScoreComment{Path: "app", Summary: "Memory limit is not set"}
+	cpu := scorecard.TestScoreComment{Path: "app", Summary: "CPU limit is not set"}
+
+	f := New(newCard(map[string][]scorecard.TestScore{
+		"a": {critical("container-resources", memory), critical("pod-probes")},
+		"b": {critical("container-resources", memory)},
+	}))
+
+	card := newCard(map[string][]scorecard.TestScore{
+		// A new finding in the same check is not suppressed
+		"a": {critical("container-resources", memory, cpu), critical("pod-probes")},
+		"c": {critical("container-resources", memory)},
+	})
+	stale := f.Apply(card)
+
+	a := (*card)["Deployment/apps/v1/default/a"]
+	assert.False(t, a.Checks[0].Suppressed)
+	assert.True(t, a.Checks[1].Suppressed)
+	assert.False(t, (*card)["Deployment/apps/v1/default/c"].Checks[0].Suppressed)
+
+	if assert.Len(t, stale, 1) {
+		assert.Equal(t, "Deployment/apps/v1/default/b", stale[0].Object)
+	}
+
+	// Only the suppressed findings are accepted
+	assert.True(t, card.AnyBelowOrEqualToGrade(scorecard.GradeCritical))
+	card = newCard(map[string][]scorecard.TestScore{
+		"a": {critical("pod-probes")},
+	})
+	f.Apply(card)
+	assert.False(t, card.AnyBelowOrEqualToGrade(scorecard.GradeCritical))
+}
+
+func TestParseFileErrors(t *testing.T) {
+	tc := []struct {
+		input    string
+		expected string
+	}{
+		{"", "baseline.yaml: the file is empty"},
+		{"version: 2\n", "baseline.yaml:1: unsupported version 2, the latest supported version is 1"},
+		{"version: 1\nfindings:\n  - check: a\n", "baseline.yaml:1: finding 0 has no fingerprint"},
+		{"version: 1\nfinding: []\n", "baseline.yaml:2: unknown field \"finding\""},
+	}
+
+	for _, tc := range tc {
+		_, err := ParseFile("baseline.yaml", strings.NewReader(tc.input))
+		if assert.Error(t, err, tc.input) {
+			assert.Equal(t, tc.expected, err.Error())
+		}
+	}
+}
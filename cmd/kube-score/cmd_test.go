@@ -1,9 +1,15 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/input"
 )
 
 func TestParseCli(t *testing.T) {
@@ -38,3 +44,22 @@ func TestParseCli(t *testing.T) {
 	assert.Equal(t, 2, offset)
 	assert.Nil(t, err)
 }
+
+func TestCheckFixable(t *testing.T) {
+	files := []ks.NamedReader{namedReader{Reader: strings.NewReader(""), name: "app.yaml"}}
+	assert.NoError(t, checkFixable([]inputGroup{{files: files}}))
+
+	rendered := append(files, input.NewRendered(nil, "base/deployment.yaml", 1))
+	assert.EqualError(t, checkFixable([]inputGroup{{files: rendered}}),
+		"base/deployment.yaml is rendered with kustomize and can not be fixed, use --kustomize=false to fix the files as they are")
+
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "app.yaml")
+	assert.NoError(t, os.WriteFile(plain, []byte("apiVersion: v1\nkind: Service\nmetadata:\n  name: app\n"), 0o600))
+	helmRendered := filepath.Join(dir, "rendered.yaml")
+	assert.NoError(t, os.WriteFile(helmRendered, []byte("---\n# Source: app/templates/service.yaml\napiVersion: v1\nkind: Service\nmetadata:\n  name: app\n"), 0o600))
+
+	assert.NoError(t, checkFixable([]inputGroup{{files: []ks.NamedReader{input.NewFile(plain, plain)}}}))
+	assert.EqualError(t, checkFixable([]inputGroup{{files: []ks.NamedReader{input.NewFile(plain, plain), input.NewFile(helmRendered, helmRendered)}}}),
+		helmRendered+" is rendered with Helm and can not be fixed, fix the templates of the chart instead")
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	flag "github.com/spf13/pflag"
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+)
+
+// loadConfigFile loads the configuration file at path. If path is empty, the
+// configuration file is discovered by walking up from the working directory.
+// A nil file is returned if no path is given and no file could be found.
+func loadConfigFile(path string) (*config.File, string, error) {
+	if path == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, "", err
+		}
+		path, err = config.FindFile(wd)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to discover config file: %w", err)
+		}
+		if path == "" {
+			return nil, "", nil
+		}
+	}
+
+	f, err := config.LoadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load config file: %w", err)
+	}
+	return f, path, nil
+}
+
+// applyConfigFile sets the flags that have a value in the configuration file, unless the flag
+// has been set on the command line. The command line always takes precedence over the file.
+func applyConfigFile(fs *flag.FlagSet, f *config.File) error {
+	set := func(name string, values ...string) error {
+		if fs.Changed(name) {
+			return nil
+		}
+		for _, v := range values {
+			if err := fs.Set(name, v); err != nil {
+				return fmt.Errorf("invalid value %q for %s in config file: %w", v, name, err)
+			}
+		}
+		return nil
+	}
+	setBool := func(name string, v *bool) error {
+		if v == nil {
+			return nil
+		}
+		return set(name, strconv.FormatBool(*v))
+	}
+	setInt := func(name string, v *int) error {
+		if v == nil {
+			return nil
+		}
+		return set(name, strconv.Itoa(*v))
+	}
+	setString := func(name string, v *string) error {
+		if v == nil {
+			return nil
+		}
+		return set(name, *v)
+	}
+	// Maps are merged, keys set on the command line takes precedence over the same key in the file
+	setMap := func(name string, m map[string]string) error {
+		if len(m) == 0 {
+			return nil
+		}
+		current, err := fs.GetStringToString(name)
+		if err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if _, ok := current[k]; ok && fs.Changed(name) {
+				continue
+			}
+			if err := fs.Set(name, k+"="+m[k]); err != nil {
+				return fmt.Errorf("invalid value %q for %s in config file: %w", k+"="+m[k], name, err)
+			}
+		}
+		return nil
+	}
+
+	for _, err := range []error{
+		setString("kubernetes-version", f.KubernetesVersion),
+		set("ignore-test", f.IgnoreTests...),
+		set("enable-optional-test", f.EnableOptionalTests...),
+		setBool("all-default-optional", f.AllDefaultOptional),
+		setBool("ignore-container-cpu-limit", f.IgnoreContainerCpuLimit),
+		setBool("ignore-container-memory-limit", f.IgnoreContainerMemoryLimit),
+		setInt("min-replicas-deployment", f.MinReplicasDeployment),
+		setInt("min-replicas-hpa", f.MinReplicasHPA),
+		setBool("disable-ignore-checks-annotations", f.DisableIgnoreChecksAnnotations),
+		setBool("disable-optional-checks-annotations", f.DisableOptionalChecksAnnotations),
+		setBool("require-ignore-reason", f.RequireIgnoreReason),
+		setBool("exit-one-on-warning", f.ExitOneOnWarning),
+		setString("output-format", f.OutputFormat),
+		setString("output-version", f.OutputVersion),
+		setString("color", f.Color),
+		setMap("severity", f.Severity),
+		setMap("param", f.Params),
+		set("custom-checks", f.CustomChecks...),
+		set("policy-dir", f.PolicyDirs...),
+		setString("baseline", f.Baseline),
+		setBool("fail-on-stale-baseline", f.FailOnStaleBaseline),
+		set("plugin", f.Plugins...),
+		setInt("plugin-memory-limit", f.PluginMemoryLimit),
+		setString("plugin-timeout", f.PluginTimeout),
+		set("include", f.Include...),
+		set("exclude", f.Exclude...),
+		setBool("kustomize", f.Kustomize),
+		setString("helm-chart", f.HelmChart),
+		set("helm-values", f.HelmValues...),
+		setString("helm-release-name", f.HelmReleaseName),
+		setString("helm-namespace", f.HelmNamespace),
+		setBool("continue-on-parse-error", f.ContinueOnParseError),
+		setBool("strict", f.Strict),
+		setBool("fail-on-unknown-kind", f.FailOnUnknownKind),
+		set("external-config-ref", f.ExternalConfigRefs...),
+		setPodTemplateKinds(fs, f.PodTemplateKinds),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// effectiveConfigFile returns the merged configuration from the configuration file and the
+// command line flags, in the same format as the configuration file.
+func effectiveConfigFile(fs *flag.FlagSet) (*config.File, error) {
+	var errs []error
+	getBool := func(name string) *bool {
+		v, err := fs.GetBool(name)
+		errs = append(errs, err)
+		return &v
+	}
+	getInt := func(name string) *int {
+		v, err := fs.GetInt(name)
+		errs = append(errs, err)
+		return &v
+	}
+	getString := func(name string) *string {
+		v, err := fs.GetString(name)
+		errs = append(errs, err)
+		return &v
+	}
+	getStringSlice := func(name string) []string {
+		v, err := fs.GetStringSlice(name)
+		errs = append(errs, err)
+		return v
+	}
+	getStringArray := func(name string) []string {
+		v, err := fs.GetStringArray(name)
+		errs = append(errs, err)
+		return v
+	}
+	getDuration := func(name string) *string {
+		v, err := fs.GetDuration(name)
+		errs = append(errs, err)
+		s := v.String()
+		return &s
+	}
+	getStringToString := func(name string) map[string]string {
+		v, err := fs.GetStringToString(name)
+		errs = append(errs, err)
+		return v
+	}
+
+	f := &config.File{
+		Version:                          config.FileVersion,
+		KubernetesVersion:                getString("kubernetes-version"),
+		IgnoreTests:                      getStringSlice("ignore-test"),
+		EnableOptionalTests:              getStringSlice("enable-optional-test"),
+		AllDefaultOptional:               getBool("all-default-optional"),
+		IgnoreContainerCpuLimit:          getBool("ignore-container-cpu-limit"),
+		IgnoreContainerMemoryLimit:       getBool("ignore-container-memory-limit"),
+		MinReplicasDeployment:            getInt("min-replicas-deployment"),
+		MinReplicasHPA:                   getInt("min-replicas-hpa"),
+		DisableIgnoreChecksAnnotations:   getBool("disable-ignore-checks-annotations"),
+		DisableOptionalChecksAnnotations: getBool("disable-optional-checks-annotations"),
+		RequireIgnoreReason:              getBool("require-ignore-reason"),
+		ExitOneOnWarning:                 getBool("exit-one-on-warning"),
+		OutputFormat:                     getString("output-format"),
+		OutputVersion:                    getString("output-version"),
+		Color:                            getString("color"),
+		Severity:                         getStringToString("severity"),
+		Params:                           getStringToString("param"),
+		CustomChecks:                     getStringSlice("custom-checks"),
+		PolicyDirs:                       getStringSlice("policy-dir"),
+		Baseline:                         getString("baseline"),
+		FailOnStaleBaseline:              getBool("fail-on-stale-baseline"),
+		Plugins:                          getStringSlice("plugin"),
+		PluginMemoryLimit:                getInt("plugin-memory-limit"),
+		PluginTimeout:                    getDuration("plugin-timeout"),
+		Include:                          getStringSlice("include"),
+		Exclude:                          getStringSlice("exclude"),
+		Kustomize:                        getBool("kustomize"),
+		HelmChart:                        getString("helm-chart"),
+		HelmValues:                       getStringArray("helm-values"),
+		HelmReleaseName:                  getString("helm-release-name"),
+		HelmNamespace:                    getString("helm-namespace"),
+		ContinueOnParseError:             getBool("continue-on-parse-error"),
+		Strict:                           getBool("strict"),
+		FailOnUnknownKind:                getBool("fail-on-unknown-kind"),
+		ExternalConfigRefs:               getStringSlice("external-config-ref"),
+	}
+
+	podTemplateKinds, err := parsePodTemplateKinds(getStringArray("pod-template-kind"))
+	errs = append(errs, err)
+	f.PodTemplateKinds = podTemplateKinds
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// parseSeverityOverrides validates the --severity flag values against the registered checks
+func parseSeverityOverrides(overrides map[string]string, allChecks []ks.Check) (map[string]config.Severity, error) {
+	knownIDs := make(map[string]struct{}, len(allChecks))
+	for _, c := range allChecks {
+		knownIDs[c.ID] = struct{}{}
+	}
+
+	res := make(map[string]config.Severity, len(overrides))
+	for id, value := range overrides {
+		if _, ok := knownIDs[id]; !ok {
+			return nil, fmt.Errorf("invalid --severity %s=%s: unknown check ID %q", id, value, id)
+		}
+		severity, err := config.ParseSeverity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --severity %s=%s: %w", id, value, err)
+		}
+		res[id] = severity
+	}
+	return res, nil
+}
+
+// setPodTemplateKinds sets the pod-template-kind flag to the kinds in the config file, if it's not set on the command
+// line
+func setPodTemplateKinds(fs *flag.FlagSet, kinds []config.PodTemplateKind) error {
+	if fs.Changed("pod-template-kind") {
+		return nil
+	}
+	for _, kind := range kinds {
+		if err := fs.Set("pod-template-kind", kind.String()); err != nil {
+			return fmt.Errorf("invalid value %q for pod-template-kind in config file: %w", kind.String(), err)
+		}
+	}
+	return nil
+}
+
+func parsePodTemplateKinds(values []string) ([]config.PodTemplateKind, error) {
+	var res []config.PodTemplateKind
+	for _, v := range values {
+		kind, err := config.ParsePodTemplateKind(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q for --pod-template-kind: %w", v, err)
+		}
+		res = append(res, kind)
+	}
+	return res, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+
+	flag "github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"k8s.io/utils/ptr"
+)
+
+func TestApplyConfigFileFlagsTakePrecedence(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	kubernetesVersion := fs.String("kubernetes-version", "v1.18", "")
+	ignoreTests := fs.StringSlice("ignore-test", []string{}, "")
+	minReplicasDeployment := fs.Int("min-replicas-deployment", 2, "")
+	exitOneOnWarning := fs.Bool("exit-one-on-warning", false, "")
+	severity := fs.StringToString("severity", map[string]string{}, "")
+	assert.NoError(t, fs.Parse([]string{"--min-replicas-deployment", "5", "--severity", "a=critical"}))
+
+	err := applyConfigFile(fs, &config.File{
+		Version:               1,
+		KubernetesVersion:     ptr.To("v1.29"),
+		IgnoreTests:           []string{"a", "b"},
+		MinReplicasDeployment: ptr.To(3),
+		Severity:              map[string]string{"a": "warning", "b": "info"},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "v1.29", *kubernetesVersion)
+	assert.Equal(t, []string{"a", "b"}, *ignoreTests)
+	assert.Equal(t, 5, *minReplicasDeployment)
+	assert.False(t, *exitOneOnWarning)
+	assert.Equal(t, map[string]string{"a": "critical", "b": "info"}, *severity)
+}
+
+func TestParseSeverityOverrides(t *testing.T) {
+	allChecks := []ks.Check{{ID: "a"}, {ID: "b"}}
+
+	res, err := parseSeverityOverrides(map[string]string{"a": "Warning", "b": "ok"}, allChecks)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]config.Severity{"a": config.SeverityWarning, "b": config.SeverityOK}, res)
+
+	_, err = parseSeverityOverrides(map[string]string{"c": "warning"}, allChecks)
+	assert.EqualError(t, err, `invalid --severity c=warning: unknown check ID "c"`)
+
+	_, err = parseSeverityOverrides(map[string]string{"a": "fatal"}, allChecks)
+	assert.Error(t, err)
+}
+
+func TestApplyConfigFilePodTemplateKinds(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	podTemplateKinds := fs.StringArray("pod-template-kind", []string{}, "")
+	assert.NoError(t, fs.Parse(nil))
+
+	err := applyConfigFile(fs, &config.File{
+		Version: 1,
+		PodTemplateKinds: []config.PodTemplateKind{
+			{APIVersion: "example.com/v1", Kind: "Worker", PodTemplatePath: "spec.pod", SelectorPath: "spec.selector"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"example.com/v1/Worker=spec.pod,,spec.selector"}, *podTemplateKinds)
+
+	kinds, err := parsePodTemplateKinds(*podTemplateKinds)
+	assert.NoError(t, err)
+	assert.Equal(t, []config.PodTemplateKind{
+		{APIVersion: "example.com/v1", Kind: "Worker", PodTemplatePath: "spec.pod", SelectorPath: "spec.selector"},
+	}, kinds)
+}
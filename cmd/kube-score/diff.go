@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/zegl/kube-score/baseline"
+	"github.com/zegl/kube-score/diff"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/input"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// diffInputs returns the files of the new and the old revision. Without baseRef, args is the old and the new
+// file or directory. With baseRef, args are the files of the new revision, and the old revision is read from git.
+// The files in the directories in args that only exist in the old revision are included, so that the findings
+// of deleted files are resolved.
+func diffInputs(binName string, args []string, baseRef string, opts input.Options) (newFiles, oldFiles []ks.NamedReader, err error) {
+	usage := fmt.Errorf(`Error: Invalid arguments.
+
+Usage: %s diff [--flag1 --flag2] old new
+       %s diff --base-ref REF [--flag1 --flag2] file1 file2 ...
+
+old and new can be files or directories. With --base-ref, the files and directories are compared with
+the same paths in the git revision REF, including the files in the directories that have been deleted since.`, execName(binName), execName(binName))
+
+	if baseRef == "" {
+		if len(args) != 2 {
+			return nil, nil, usage
+		}
+		if oldFiles, err = openFiles(args[:1], opts); err != nil {
+			return nil, nil, err
+		}
+		if newFiles, err = openFiles(args[1:], opts); err != nil {
+			return nil, nil, err
+		}
+		return newFiles, oldFiles, nil
+	}
+
+	if len(args) == 0 {
+		return nil, nil, usage
+	}
+	// Kustomizations can't be rendered from a git revision, so the files in them are compared instead
+	opts.Kustomize = false
+	paths, err := input.Expand(args, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if newFiles, err = openFiles(paths, opts); err != nil {
+		return nil, nil, err
+	}
+
+	if out, err := exec.Command("git", "rev-parse", "--verify", "--quiet", baseRef+"^{commit}").Output(); err != nil || len(out) == 0 {
+		return nil, nil, fmt.Errorf("invalid --base-ref %q: the revision does not exist", baseRef)
+	}
+	seen := make(map[string]bool)
+	addOld := func(dir, rel string) {
+		abs := filepath.Join(dir, filepath.FromSlash(rel))
+		if seen[abs] {
+			return
+		}
+		seen[abs] = true
+		// Files that don't exist in the old revision are new
+		if content, ok := gitShow(baseRef, dir, rel); ok {
+			oldFiles = append(oldFiles, namedReader{Reader: bytes.NewReader(content), name: abs + "@" + baseRef})
+		}
+	}
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		addOld(filepath.Dir(abs), filepath.Base(abs))
+	}
+
+	// The files that have been deleted from the directories
+	for _, arg := range args {
+		if info, err := os.Stat(arg); err != nil || !info.IsDir() {
+			continue
+		}
+		dir, err := filepath.Abs(arg)
+		if err != nil {
+			return nil, nil, err
+		}
+		files, err := gitListFiles(baseRef, dir)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, rel := range files {
+			walked, err := input.Walked(rel, opts)
+			if err != nil {
+				return nil, nil, err
+			}
+			if walked {
+				addOld(dir, rel)
+			}
+		}
+	}
+	return newFiles, oldFiles, nil
+}
+
+// gitShow returns the content of the file at rel, a slash separated path relative to the directory dir, in the git
+// revision ref, ok is false if the file doesn't exist in the revision
+func gitShow(ref, dir, rel string) (content []byte, ok bool) {
+	cmd := exec.Command("git", "show", ref+":./"+rel)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+// gitListFiles returns the files in the directory dir in the git revision ref, as slash separated paths relative to dir
+func gitListFiles(ref, dir string) ([]string, error) {
+	cmd := exec.Command("git", "ls-tree", "-r", "-z", "--name-only", ref, "--", ".")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the files in %s at %s: %w", dir, ref, err)
+	}
+	var files []string
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name != "" {
+			files = append(files, name)
+		}
+	}
+	return files, nil
+}
+
+// diffScores prints the difference between the scores, and returns the exit code 1 if the new revision introduces criticals
+func diffScores(oldCard, newCard *scorecard.Scorecard, baselinePath, outputFormat string, exitOneOnWarning, useColors bool) (int, error) {
+	if baselinePath != "" {
+		b, err := baseline.LoadFile(baselinePath)
+		if err != nil {
+			return 0, fmt.Errorf("failed to load baseline: %w", err)
+		}
+		b.Apply(oldCard)
+		b.Apply(newCard)
+	}
+
+	res := diff.Compare(oldCard, newCard)
+
+	var r io.Reader
+	switch outputFormat {
+	case "json":
+		r = diff.JSON(res)
+	default:
+		r = diff.Human(res, useColors)
+	}
+	output, _ := io.ReadAll(r)
+	fmt.Print(string(output))
+
+	switch {
+	case res.AnyIntroducedBelowOrEqualToGrade(scorecard.GradeCritical):
+		return 1, nil
+	case exitOneOnWarning && res.AnyIntroducedBelowOrEqualToGrade(scorecard.GradeWarning):
+		return 1, nil
+	}
+	return 0, nil
+}
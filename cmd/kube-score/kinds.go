@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+// kindSummary counts the objects of each kind that are seen in the input, and the objects that are scored
+type kindSummary struct {
+	seen   map[string]int
+	scored map[string]int
+}
+
+func newKindSummary() *kindSummary {
+	return &kindSummary{seen: make(map[string]int), scored: make(map[string]int)}
+}
+
+// add counts the objects in allObjects, all scored objects have a meta. Objects of kinds that are not supported, and documents that could not be parsed,
+// are seen but not scored.
+func (k *kindSummary) add(allObjects ks.AllTypes) {
+	for _, meta := range allObjects.Metas() {
+		k.seen[kindName(meta.TypeMeta)]++
+		k.scored[kindName(meta.TypeMeta)]++
+	}
+	for _, unknown := range allObjects.UnknownKinds() {
+		k.seen[kindName(unknown.TypeMeta)]++
+	}
+	for _, parseErr := range allObjects.ParseErrors() {
+		k.seen[kindName(parseErr.TypeMeta)]++
+	}
+}
+
+// unscored returns true if any object is not scored
+func (k *kindSummary) unscored() bool {
+	for kind, n := range k.seen {
+		if k.scored[kind] < n {
+			return true
+		}
+	}
+	return false
+}
+
+// write writes the number of seen and scored objects of each kind to w
+func (k *kindSummary) write(w io.Writer) {
+	kinds := make([]string, 0, len(k.seen))
+	for kind := range k.seen {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	_, _ = fmt.Fprintf(w, "Kinds seen: %d, kinds scored: %d\n", len(k.seen), len(k.scored))
+	for _, kind := range kinds {
+		_, _ = fmt.Fprintf(w, "    %s: %d seen, %d scored\n", kind, k.seen[kind], k.scored[kind])
+	}
+}
+
+func kindName(typeMeta metav1.TypeMeta) string {
+	if typeMeta.APIVersion == "" && typeMeta.Kind == "" {
+		return "(no apiVersion and kind)"
+	}
+	return typeMeta.APIVersion + " " + typeMeta.Kind
+}
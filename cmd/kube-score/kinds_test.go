@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/parser"
+)
+
+func TestKindSummary(t *testing.T) {
+	doc := `apiVersion: v1
+kind: Service
+metadata:
+  name: a
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: b
+---
+apiVersion: apps/v1beat1
+kind: Deployment
+metadata:
+  name: c
+`
+	p, err := parser.New(nil)
+	assert.NoError(t, err)
+	parsed, err := p.ParseFiles([]ks.NamedReader{namedReader{Reader: strings.NewReader(doc), name: "a.yaml"}})
+	assert.NoError(t, err)
+
+	kinds := newKindSummary()
+	kinds.add(parsed)
+	assert.True(t, kinds.unscored())
+
+	var buf bytes.Buffer
+	kinds.write(&buf)
+	assert.Equal(t, `Kinds seen: 2, kinds scored: 1
+    apps/v1beat1 Deployment: 1 seen, 0 scored
+    v1 Service: 2 seen, 2 scored
+`, buf.String())
+}
@@ -7,13 +7,22 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/mattn/go-isatty"
 	flag "github.com/spf13/pflag"
+	"github.com/zegl/kube-score/baseline"
 	"github.com/zegl/kube-score/config"
 	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/fix"
+	"github.com/zegl/kube-score/input"
+	"github.com/zegl/kube-score/input/helm"
+	"github.com/zegl/kube-score/input/kustomize"
 	"github.com/zegl/kube-score/parser"
 	"github.com/zegl/kube-score/renderer/ci"
 	"github.com/zegl/kube-score/renderer/human"
@@ -22,8 +31,12 @@ import (
 	"github.com/zegl/kube-score/renderer/sarif"
 	"github.com/zegl/kube-score/score"
 	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/score/custom"
+	"github.com/zegl/kube-score/score/plugin"
+	"github.com/zegl/kube-score/score/policy"
 	"github.com/zegl/kube-score/scorecard"
 	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
 )
 
 func main() {
@@ -34,10 +47,39 @@ func main() {
 
 	cmds := map[string]cmdFunc{
 		"score": func(helpName string, args []string) {
-			if err := scoreFiles(helpName, args); err != nil {
+			exitCode, err := scoreFiles(helpName, args, actionScore)
+			if err != nil {
 				_, _ = fmt.Fprintf(os.Stderr, "Failed to score files: %v\n", err)
 				os.Exit(1)
 			}
+			os.Exit(exitCode)
+		},
+
+		"baseline": func(helpName string, args []string) {
+			if len(args) == 0 || args[0] != "create" {
+				_, _ = fmt.Fprintf(os.Stderr, "Usage: %s baseline create [--flag1 --flag2] file1 file2 ...\n", helpName)
+				os.Exit(1)
+			}
+			if _, err := scoreFiles(helpName, args[1:], actionBaselineCreate); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "Failed to create baseline: %v\n", err)
+				os.Exit(1)
+			}
+		},
+
+		"fix": func(helpName string, args []string) {
+			if _, err := scoreFiles(helpName, args, actionFix); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "Failed to fix files: %v\n", err)
+				os.Exit(1)
+			}
+		},
+
+		"diff": func(helpName string, args []string) {
+			exitCode, err := scoreFiles(helpName, args, actionDiff)
+			if err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "Failed to diff files: %v\n", err)
+				os.Exit(1)
+			}
+			os.Exit(exitCode)
 		},
 
 		"list": func(helpName string, args []string) {
@@ -78,6 +120,9 @@ func setDefault(fs *flag.FlagSet, binName, actionName string, displayForMoreInfo
 
 Actions:
 	score	Checks all files in the input, and gives them a score and recommendations
+	baseline create	Writes all current findings to a baseline file, that can be used with "score --baseline"
+	fix	Applies the suggested fixes of the failing checks to the files
+	diff	Compares the score of two revisions of the files, and reports new, resolved and changed findings
 	list	Prints a CSV list of all available score checks
 	version	Print the version of kube-score
 	help	Print this message`+"\n\n", binName, binName)
@@ -98,7 +143,23 @@ Actions:
 	}
 }
 
-func scoreFiles(binName string, args []string) error {
+// scoreAction is what scoreFiles does with the score
+type scoreAction int
+
+const (
+	// actionScore prints the score
+	actionScore scoreAction = iota
+	// actionBaselineCreate writes a baseline file with the findings
+	actionBaselineCreate
+	// actionFix applies the fixes of the failing checks to the files
+	actionFix
+	// actionDiff compares the score of two revisions of the files
+	actionDiff
+)
+
+// scoreFiles scores all files in args, and prints the score, creates a baseline, or fixes the files depending on action.
+// It returns the exit code of the command.
+func scoreFiles(binName string, args []string, action scoreAction) (int, error) {
 	fs := flag.NewFlagSet(binName, flag.ExitOnError)
 	exitOneOnWarning := fs.Bool("exit-one-on-warning", false, "Exit with code 1 in case of warnings")
 	ignoreContainerCpuLimit := fs.Bool("ignore-container-cpu-limit", false, "Disables the requirement of setting a container CPU limit")
@@ -112,25 +173,88 @@ func scoreFiles(binName string, args []string) error {
 	ignoreTests := fs.StringSlice("ignore-test", []string{}, "Disable a test, can be set multiple times")
 	disableIgnoreChecksAnnotation := fs.Bool("disable-ignore-checks-annotations", false, "Set to true to disable the effect of the 'kube-score/ignore' annotations")
 	disableOptionalChecksAnnotation := fs.Bool("disable-optional-checks-annotations", false, "Set to true to disable the effect of the 'kube-score/enable' annotations")
+	requireIgnoreReason := fs.Bool("require-ignore-reason", false, "Ignore annotations without a reason are not honored, use kube-score/ignore.<check-id>: \"reason=...\" to ignore a check with a reason")
 	allDefaultOptional := fs.Bool("all-default-optional", false, "Set to true to enable all tests")
 	kubernetesVersion := fs.String("kubernetes-version", "v1.18", "Setting the kubernetes-version will affect the checks ran against the manifests. Set this to the version of Kubernetes that you're using in production for the best results.")
 	minReplicasDeployment := fs.Int("min-replicas-deployment", 2, "Minimum required number of replicas for a deployment")
 	minReplicasHPA := fs.Int("min-replicas-hpa", 2, "Minimum required number of replicas for a horizontal pod autoscaler")
-	setDefault(fs, binName, "score", false)
+	severityOverrides := fs.StringToString("severity", map[string]string{}, "Override the grade of a failing check, on the format check-id=severity. The severity can be 'critical', 'warning', 'info' or 'ok'. Can be set multiple times")
+	params := fs.StringToString("param", map[string]string{}, "Set the value of a check parameter, on the format check-id.param=value. Run \"list --params\" to see all available parameters. Can be set multiple times")
+	customChecks := fs.StringSlice("custom-checks", []string{}, "Path to a YAML file with custom checks written in CEL, can be set multiple times")
+	policyDirs := fs.StringSlice("policy-dir", []string{}, "Path to a directory with Rego policies, every package with deny or warn rules becomes a check. Can be set multiple times")
+	plugins := fs.StringSlice("plugin", []string{}, "Path to a WebAssembly plugin with custom checks, can be set multiple times")
+	pluginMemoryLimit := fs.Int("plugin-memory-limit", plugin.DefaultMemoryLimitMiB, "The maximum memory of a plugin, in MiB")
+	pluginTimeout := fs.Duration("plugin-timeout", plugin.DefaultTimeout, "The maximum time that a plugin can spend on checking a single object")
+	baselinePath := fs.String("baseline", "", "Path to a baseline file. Findings that are in the baseline are suppressed, and do not affect the exit code. With \"baseline create\", the baseline is written to this file (default \""+baseline.DefaultFileName+"\")")
+	failOnStaleBaseline := fs.Bool("fail-on-stale-baseline", false, "Exit with code 1 if the baseline has findings that no longer exist")
+	configPath := fs.String("config", "", "Path to a kube-score configuration file. If not set, kube-score looks for a .kube-score.yaml file in the current directory and its parents. Flags set on the command line take precedence over values from the configuration file.")
+	include := fs.StringSlice("include", []string{}, "Only score the files that match this pattern when walking directories, in the gitignore syntax. Can be set multiple times")
+	exclude := fs.StringSlice("exclude", []string{}, "Skip the files and directories that match this pattern when walking directories, in the gitignore syntax. Can be set multiple times")
+	kustomize := fs.Bool("kustomize", true, "Render directories with a kustomization file with kustomize, and score the rendered objects instead of the files in the directory")
+	helmChart := fs.String("helm-chart", "", "Path to a Helm chart to render and score, as a directory or a packaged chart")
+	helmValues := fs.StringArrayP("helm-values", "f", []string{}, "Values file for --helm-chart. The chart is rendered and scored once for each time the flag is set, and a comma separated list of files are merged in order")
+	helmReleaseName := fs.String("helm-release-name", "release-name", "The name of the release when rendering --helm-chart")
+	helmNamespace := fs.String("helm-namespace", "default", "The namespace of the release when rendering --helm-chart")
+	continueOnParseError := fs.Bool("continue-on-parse-error", false, "Score the documents that can be parsed, and report each document that can not be parsed as a critical \"Parse error\" check, instead of failing")
+	strict := fs.Bool("strict", false, "Validate the objects strictly against the schema of their kind, and report unknown and duplicate fields as a critical \"Unknown field\" check")
+	failOnUnknownKind := fs.Bool("fail-on-unknown-kind", false, "Report the documents of kinds that are not supported by kube-score as a critical \"Unsupported kind\" check, instead of an informational check")
+	externalConfigRefs := fs.StringSlice("external-config-ref", []string{}, "Name of a ConfigMap or Secret that is created outside of the manifests, and is not reported as missing by the \"Pod ConfigMap and Secret references\" check, on the format [namespace/]name. Can be set multiple times")
+	podTemplateKinds := fs.StringArray("pod-template-kind", []string{}, "Score the objects of a kind with a pod template with the pod checks, on the format <apiVersion>/<kind>=<podTemplatePath>[,<replicasPath>[,<selectorPath>]], such as example.com/v1/Worker=spec.template,spec.replicas,spec.selector. Can be set multiple times")
+	printConfig := fs.Bool("print-config", false, "Print the effective configuration, after merging the configuration file and the flags, and exit")
+	var dryRun *bool
+	var baseRef *string
+	switch action {
+	case actionBaselineCreate:
+		setDefault(fs, binName, "baseline create", false)
+	case actionFix:
+		dryRun = fs.Bool("dry-run", false, "Print a diff of the fixes instead of changing the files")
+		setDefault(fs, binName, "fix", false)
+	case actionDiff:
+		baseRef = fs.String("base-ref", "", "Compare the files with the same files in this git revision, instead of comparing two files or directories")
+		setDefault(fs, binName, "diff", false)
+	default:
+		setDefault(fs, binName, "score", false)
+	}
 
 	err := fs.Parse(args)
 	if err != nil {
-		return fmt.Errorf("failed to parse files: %w", err)
+		return 0, fmt.Errorf("failed to parse files: %w", err)
 	}
 
 	if *printHelp {
 		fs.Usage()
-		return nil
+		return 0, nil
+	}
+
+	configFile, configFilePath, err := loadConfigFile(*configPath)
+	if err != nil {
+		return 0, err
+	}
+	if configFile != nil {
+		if *verboseOutput > 1 {
+			log.Printf("Using configuration file: %s", configFilePath)
+		}
+		if err := applyConfigFile(fs, configFile); err != nil {
+			return 0, err
+		}
+	}
+
+	if *printConfig {
+		effective, err := effectiveConfigFile(fs)
+		if err != nil {
+			return 0, err
+		}
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(effective); err != nil {
+			return 0, fmt.Errorf("failed to print config: %w", err)
+		}
+		return 0, enc.Close()
 	}
 
 	if *outputFormat != "human" && *outputFormat != "ci" && *outputFormat != "json" && *outputFormat != "sarif" && *outputFormat != "junit" {
 		fs.Usage()
-		return fmt.Errorf("Error: --output-format must be set to: 'human', 'json', 'sarif', 'junit' or 'ci'")
+		return 0, fmt.Errorf("Error: --output-format must be set to: 'human', 'json', 'sarif', 'junit' or 'ci'")
 	}
 
 	acceptedColors := map[string]bool{
@@ -140,45 +264,78 @@ func scoreFiles(binName string, args []string) error {
 	}
 	if !acceptedColors[*color] {
 		fs.Usage()
-		return fmt.Errorf("Error: --color must be set to: 'auto', 'always' or 'never'")
+		return 0, fmt.Errorf("Error: --color must be set to: 'auto', 'always' or 'never'")
 	}
 
-	filesToRead := fs.Args()
-	if len(filesToRead) == 0 {
-		return fmt.Errorf(`Error: No files given as arguments.
+	if action == actionDiff && *outputFormat != "human" && *outputFormat != "json" {
+		fs.Usage()
+		return 0, fmt.Errorf("Error: --output-format must be set to: 'human' or 'json'")
+	}
 
-Usage: %s score [--flag1 --flag2] file1 file2 ...
+	var inputGroups []inputGroup
+	var baseFilePointers []ks.NamedReader
 
-Use "-" as filename to read from STDIN.`, execName(binName))
-	}
+	inputOptions := input.Options{Include: *include, Exclude: *exclude, Kustomize: *kustomize}
+	filesToRead := fs.Args()
+	if action == actionDiff {
+		if *helmChart != "" {
+			return 0, errors.New("--helm-chart can not be used with diff")
+		}
+		var files []ks.NamedReader
+		files, baseFilePointers, err = diffInputs(binName, filesToRead, *baseRef, inputOptions)
+		inputGroups = []inputGroup{{files: files}}
+	} else {
+		if len(filesToRead) == 0 && *helmChart == "" {
+			return 0, fmt.Errorf(`Error: No files given as arguments.
 
-	var allFilePointers []ks.NamedReader
+Usage: %s score [--flag1 --flag2] file1 file2 ...
+       %s score --helm-chart CHART [-f values.yaml] [--flag1 --flag2] [file1 file2 ...]
 
-	for _, file := range filesToRead {
-		var fp io.Reader
-		var filename string
+Directories are scored recursively, and globs such as "deploy/**/*.yaml" are supported.
+Use "-" as filename to read from STDIN.`, execName(binName), execName(binName))
+		}
+		if action == actionFix && slices.Contains(filesToRead, "-") {
+			return 0, errors.New("files read from STDIN can not be fixed")
+		}
+		if action == actionFix && *helmChart != "" {
+			return 0, errors.New("Helm charts can not be fixed")
+		}
 
-		if file == "-" {
-			fp = os.Stdin
-			filename = "STDIN"
-		} else {
-			var err error
-			fp, err = os.Open(file)
-			if err != nil {
-				return err
-			}
-			filename, _ = filepath.Abs(file)
+		helmOptions := helm.Options{ReleaseName: *helmReleaseName, Namespace: *helmNamespace}
+		// The default Kubernetes version of Helm is used, unless the version is set explicitly
+		if fs.Changed("kubernetes-version") {
+			helmOptions.KubernetesVersion = *kubernetesVersion
+		}
+		inputGroups, err = openInputGroups(filesToRead, inputOptions, *helmChart, *helmValues, helmOptions)
+		if err == nil && action == actionFix {
+			err = checkFixable(inputGroups)
 		}
-		allFilePointers = append(allFilePointers, namedReader{Reader: fp, name: filename})
+	}
+	if err != nil {
+		return 0, err
 	}
 
 	if len(*ignoreTests) > 0 && *allDefaultOptional {
-		return errors.New("Invalid argument combination. --all-default-optional and --ignore-tests cannot be used together")
+		return 0, errors.New("Invalid argument combination. --all-default-optional and --ignore-tests cannot be used together")
 	}
 
+	pluginOptions := plugin.Options{MemoryLimitMiB: *pluginMemoryLimit, Timeout: *pluginTimeout}
+	if *verboseOutput > 0 {
+		pluginOptions.Stderr = os.Stderr
+	}
+	external, closeExternal, err := loadExternalChecks(*customChecks, *policyDirs, *plugins, pluginOptions)
+	if err != nil {
+		return 0, err
+	}
+	defer closeExternal()
+
 	if *allDefaultOptional {
+		allChecks, err := registerChecks(parser.Empty(), nil, nil, external)
+		if err != nil {
+			return 0, err
+		}
 		var addOptionalChecks []string
-		for _, c := range score.RegisterAllChecks(parser.Empty(), nil, nil).All() {
+		for _, c := range allChecks.All() {
 			if c.Optional {
 				addOptionalChecks = append(addOptionalChecks, c.ID)
 			}
@@ -191,7 +348,7 @@ Use "-" as filename to read from STDIN.`, execName(binName))
 
 	kubeVer, err := config.ParseSemver(*kubernetesVersion)
 	if err != nil {
-		return errors.New("Invalid --kubernetes-version. Use on format \"vN.NN\"")
+		return 0, errors.New("Invalid --kubernetes-version. Use on format \"vN.NN\"")
 	}
 
 	runConfig := &config.RunConfiguration{
@@ -200,28 +357,105 @@ Use "-" as filename to read from STDIN.`, execName(binName))
 		EnabledOptionalTests:                  enabledOptionalTests,
 		UseIgnoreChecksAnnotation:             !*disableIgnoreChecksAnnotation,
 		UseOptionalChecksAnnotation:           !*disableOptionalChecksAnnotation,
+		RequireIgnoreReason:                   *requireIgnoreReason,
 		KubernetesVersion:                     kubeVer,
 		MinReplicasDeployment:                 *minReplicasDeployment,
 		MinReplicasHPA:                        *minReplicasHPA,
+		FailOnUnknownKind:                     *failOnUnknownKind,
+		ExternalConfigRefs:                    *externalConfigRefs,
+	}
+
+	parsedPodTemplateKinds, err := parsePodTemplateKinds(*podTemplateKinds)
+	if err != nil {
+		return 0, err
 	}
 
 	p, err := parser.New(&parser.Config{
-		VerboseOutput: *verboseOutput,
+		VerboseOutput:        *verboseOutput,
+		ContinueOnParseError: *continueOnParseError,
+		Strict:               *strict,
+		PodTemplateKinds:     parsedPodTemplateKinds,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to initializer parser: %w", err)
+		return 0, fmt.Errorf("failed to initializer parser: %w", err)
+	}
+
+	kinds := newKindSummary()
+	scoreInputs := func(inputs []ks.NamedReader) (*scorecard.Scorecard, error) {
+		parsedFiles, err := p.ParseFiles(inputs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse files: %w", err)
+		}
+		kinds.add(parsedFiles)
+
+		checks, err := registerChecks(parsedFiles, &checks.Config{IgnoredTests: ignoredTests, Params: *params}, runConfig, external)
+		if err != nil {
+			return nil, err
+		}
+		if err := checks.ParamsErr(); err != nil {
+			return nil, fmt.Errorf("invalid check parameters: %w", err)
+		}
+
+		runConfig.SeverityOverrides, err = parseSeverityOverrides(*severityOverrides, checks.All())
+		if err != nil {
+			return nil, err
+		}
+
+		return score.Score(parsedFiles, checks, runConfig)
 	}
 
-	parsedFiles, err := p.ParseFiles(allFilePointers)
+	scoreCard, err := scoreInputs(inputGroups[0].files)
 	if err != nil {
-		return fmt.Errorf("failed to parse files: %w", err)
+		return 0, err
+	}
+	if len(inputGroups) > 1 {
+		combined := scorecard.New()
+		combined.AddGroup(inputGroups[0].name, *scoreCard)
+		for _, group := range inputGroups[1:] {
+			groupCard, err := scoreInputs(group.files)
+			if err != nil {
+				return 0, err
+			}
+			combined.AddGroup(group.name, *groupCard)
+		}
+		scoreCard = &combined
+	}
+
+	if action == actionDiff {
+		baseScoreCard, err := scoreInputs(baseFilePointers)
+		if err != nil {
+			return 0, err
+		}
+		return diffScores(baseScoreCard, scoreCard, *baselinePath, *outputFormat, *exitOneOnWarning, useColor(*color))
 	}
 
-	checks := score.RegisterAllChecks(parsedFiles, &checks.Config{IgnoredTests: ignoredTests}, runConfig)
+	// The kinds are summarized if any object is not scored, so that objects of unsupported kinds are not missed
+	if kinds.unscored() || *verboseOutput > 0 {
+		kinds.write(os.Stderr)
+	}
 
-	scoreCard, err := score.Score(parsedFiles, checks, runConfig)
-	if err != nil {
-		return err
+	if action == actionBaselineCreate {
+		return 0, writeBaseline(*baselinePath, scoreCard)
+	}
+
+	var staleBaseline bool
+	if *baselinePath != "" {
+		b, err := baseline.LoadFile(*baselinePath)
+		if err != nil {
+			return 0, fmt.Errorf("failed to load baseline: %w", err)
+		}
+		stale := b.Apply(scoreCard)
+		if len(stale) > 0 {
+			staleBaseline = true
+			_, _ = fmt.Fprintf(os.Stderr, "%d findings in the baseline %s no longer exist:\n", len(stale), *baselinePath)
+			for _, f := range stale {
+				_, _ = fmt.Fprintf(os.Stderr, "    %s %s %s\n", f.Object, f.Check, formatFinding(f))
+			}
+		}
+	}
+
+	if action == actionFix {
+		return 0, fixFiles(scoreCard, *dryRun)
 	}
 
 	var exitCode int
@@ -230,6 +464,8 @@ Use "-" as filename to read from STDIN.`, execName(binName))
 		exitCode = 1
 	case *exitOneOnWarning && scoreCard.AnyBelowOrEqualToGrade(scorecard.GradeWarning):
 		exitCode = 1
+	case *failOnStaleBaseline && staleBaseline:
+		exitCode = 1
 	default:
 		exitCode = 0
 	}
@@ -254,7 +490,7 @@ Use "-" as filename to read from STDIN.`, execName(binName))
 		}
 		r, err = human.Human(scoreCard, *verboseOutput, termWidth, useColor(*color))
 		if err != nil {
-			return err
+			return 0, err
 		}
 	case *outputFormat == "ci" && version == "v1":
 		r = ci.CI(scoreCard)
@@ -263,15 +499,218 @@ Use "-" as filename to read from STDIN.`, execName(binName))
 	case *outputFormat == "junit":
 		r = junit.JUnit(scoreCard)
 	default:
-		return fmt.Errorf("error: Unknown --output-format or --output-version")
+		return 0, fmt.Errorf("error: Unknown --output-format or --output-version")
 	}
 
 	output, _ := io.ReadAll(r)
 	fmt.Print(string(output))
-	os.Exit(exitCode)
+	return exitCode, nil
+}
+
+// inputGroup is a set of files that are scored together, independently of the other groups
+type inputGroup struct {
+	name  string
+	files []ks.NamedReader
+}
+
+// checkFixable returns an error if any of the files is rendered, such as by kustomize or Helm. The rendered objects can
+// have another name and namespace than the object in the file that they are rendered from, and can not be fixed.
+func checkFixable(groups []inputGroup) error {
+	for _, group := range groups {
+		for _, file := range group.files {
+			if _, ok := file.(ks.RenderedReader); ok {
+				return fmt.Errorf("%s is rendered with kustomize and can not be fixed, use --kustomize=false to fix the files as they are", file.Name())
+			}
+			if _, ok := file.(*input.File); !ok {
+				continue
+			}
+			// The file is read separately, so that it's still unread when it's parsed
+			fp, err := os.Open(file.Name())
+			if err != nil {
+				return err
+			}
+			docs, _, err := parser.ReadDocumentsTolerant(fp)
+			_ = fp.Close()
+			if err != nil {
+				return err
+			}
+			for _, doc := range docs {
+				if _, ok := doc.HelmTemplate(); ok {
+					return fmt.Errorf("%s is rendered with Helm and can not be fixed, fix the templates of the chart instead", file.Name())
+				}
+			}
+		}
+	}
 	return nil
 }
 
+// openInputGroups returns the files to score. Without a Helm chart, all files are scored in a single group. With a
+// chart, the chart is rendered once for each entry in helmValues, which is a comma separated list of values files,
+// and each rendering is scored together with the files in a group named after the values files.
+func openInputGroups(paths []string, opts input.Options, chart string, helmValues []string, helmOptions helm.Options) ([]inputGroup, error) {
+	if chart == "" {
+		files, err := openFiles(paths, opts)
+		if err != nil {
+			return nil, err
+		}
+		return []inputGroup{{files: files}}, nil
+	}
+
+	if len(helmValues) == 0 {
+		helmValues = []string{""}
+	}
+	var groups []inputGroup
+	for _, values := range helmValues {
+		var group inputGroup
+		// The files are opened for each group, as the readers can only be read once
+		if len(paths) > 0 {
+			files, err := openFiles(paths, opts)
+			if err != nil {
+				return nil, err
+			}
+			group.files = files
+		}
+
+		helmOptions.ValuesFiles = nil
+		if values != "" {
+			helmOptions.ValuesFiles = strings.Split(values, ",")
+		}
+		objects, err := helm.Render(chart, helmOptions)
+		if err != nil {
+			return nil, err
+		}
+		group.files = append(group.files, objects...)
+
+		// Objects are only grouped if the chart is scored with different values
+		if len(helmValues) > 1 {
+			group.name = values
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// openFiles opens all files, directories and globs are expanded, and "-" is read from STDIN
+func openFiles(paths []string, opts input.Options) ([]ks.NamedReader, error) {
+	files, err := input.Expand(paths, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no files to score were found")
+	}
+
+	var res []ks.NamedReader
+	for _, file := range files {
+		if opts.Kustomize && input.IsKustomization(file) {
+			objects, err := kustomize.Render(file)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, objects...)
+			continue
+		}
+
+		if file == "-" {
+			res = append(res, namedReader{Reader: os.Stdin, name: "STDIN"})
+			continue
+		}
+
+		// The files are opened when they are parsed, see input.File
+		filename, _ := filepath.Abs(file)
+		res = append(res, input.NewFile(file, filename))
+	}
+	return res, nil
+}
+
+func writeBaseline(path string, scoreCard *scorecard.Scorecard) error {
+	if path == "" {
+		path = baseline.DefaultFileName
+	}
+
+	b := baseline.New(scoreCard)
+	var buf bytes.Buffer
+	if err := b.Write(&buf); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "Wrote %d findings to %s\n", len(b.Findings), path)
+	return nil
+}
+
+// fixFiles applies the fixes of all failing checks to the files, or prints a diff of the changes if dryRun is true
+func fixFiles(scoreCard *scorecard.Scorecard, dryRun bool) error {
+	changes := fix.Changes(scoreCard)
+	files := make([]string, 0, len(changes))
+	for file := range changes {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	var applied, changedFiles, failed int
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		res, errs := fix.ApplyChanges(src, changes[file])
+		for _, err := range errs {
+			_, _ = fmt.Fprintf(os.Stderr, "Failed to fix %s: %v\n", file, err)
+		}
+		failed += len(errs)
+		applied += len(changes[file]) - len(errs)
+		if bytes.Equal(src, res) {
+			continue
+		}
+		changedFiles++
+
+		if dryRun {
+			name := file
+			if wd, err := os.Getwd(); err == nil {
+				if rel, err := filepath.Rel(wd, file); err == nil {
+					name = rel
+				}
+			}
+			diff, err := fix.Diff(name, src, res)
+			if err != nil {
+				return err
+			}
+			fmt.Print(diff)
+			continue
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(file, res, info.Mode()); err != nil {
+			return err
+		}
+	}
+
+	if dryRun {
+		_, _ = fmt.Fprintf(os.Stderr, "Would apply %d fixes to %d files\n", applied, changedFiles)
+	} else {
+		_, _ = fmt.Fprintf(os.Stderr, "Applied %d fixes to %d files\n", applied, changedFiles)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d fixes could not be applied", failed)
+	}
+	return nil
+}
+
+func formatFinding(f baseline.Finding) string {
+	if f.Path != "" {
+		return "(" + f.Path + ") " + f.Summary
+	}
+	return f.Summary
+}
+
 func getOutputVersion(flagValue, format string) string {
 	if len(flagValue) > 0 {
 		return flagValue
@@ -288,6 +727,11 @@ func getOutputVersion(flagValue, format string) string {
 func listChecks(binName string, args []string) error {
 	fs := flag.NewFlagSet(binName, flag.ExitOnError)
 	printHelp := fs.Bool("help", false, "Print help")
+	printParams := fs.Bool("params", false, "Print a CSV list of the parameters of all checks, instead of the checks")
+	customChecks := fs.StringSlice("custom-checks", []string{}, "Path to a YAML file with custom checks written in CEL, can be set multiple times")
+	policyDirs := fs.StringSlice("policy-dir", []string{}, "Path to a directory with Rego policies, can be set multiple times")
+	plugins := fs.StringSlice("plugin", []string{}, "Path to a WebAssembly plugin with custom checks, can be set multiple times")
+	configPath := fs.String("config", "", "Path to a kube-score configuration file. If not set, kube-score looks for a .kube-score.yaml file in the current directory and its parents.")
 	setDefault(fs, binName, "list", false)
 	err := fs.Parse(args)
 	if err != nil {
@@ -299,9 +743,44 @@ func listChecks(binName string, args []string) error {
 		return nil
 	}
 
-	allChecks := score.RegisterAllChecks(parser.Empty(), nil, nil)
+	configFile, _, err := loadConfigFile(*configPath)
+	if err != nil {
+		return err
+	}
+	if configFile != nil && !fs.Changed("custom-checks") {
+		*customChecks = configFile.CustomChecks
+	}
+	if configFile != nil && !fs.Changed("policy-dir") {
+		*policyDirs = configFile.PolicyDirs
+	}
+	if configFile != nil && !fs.Changed("plugin") {
+		*plugins = configFile.Plugins
+	}
+
+	external, closeExternal, err := loadExternalChecks(*customChecks, *policyDirs, *plugins, plugin.Options{})
+	if err != nil {
+		return err
+	}
+	defer closeExternal()
+
+	allChecks, err := registerChecks(parser.Empty(), nil, nil, external)
+	if err != nil {
+		return err
+	}
 
 	output := csv.NewWriter(os.Stdout)
+
+	if *printParams {
+		for _, p := range allChecks.Params() {
+			err := output.Write([]string{p.CheckID, p.Name, p.Type, p.Default, p.Description})
+			if err != nil {
+				return nil
+			}
+		}
+		output.Flush()
+		return nil
+	}
+
 	for _, c := range allChecks.All() {
 		optionalString := "default"
 		if c.Optional {
@@ -317,6 +796,66 @@ func listChecks(binName string, args []string) error {
 	return nil
 }
 
+// externalChecks are the checks that are loaded from files, and registered after the built in checks
+type externalChecks struct {
+	customRules []*custom.Rule
+	policies    []*policy.Policy
+	plugins     []*plugin.Plugin
+}
+
+// registerChecks registers all built in checks, followed by the external checks
+func registerChecks(allObjects ks.AllTypes, checksConfig *checks.Config, runConfig *config.RunConfiguration, external externalChecks) (*checks.Checks, error) {
+	allChecks := score.RegisterAllChecks(allObjects, checksConfig, runConfig)
+	if err := custom.Register(allChecks, external.customRules); err != nil {
+		return nil, fmt.Errorf("failed to register custom checks: %w", err)
+	}
+	if err := policy.Register(allChecks, external.policies); err != nil {
+		return nil, fmt.Errorf("failed to register policies: %w", err)
+	}
+	if err := plugin.Register(allChecks, external.plugins); err != nil {
+		return nil, fmt.Errorf("failed to register plugins: %w", err)
+	}
+	return allChecks, nil
+}
+
+// loadExternalChecks loads the custom checks, policies and plugins. The returned function closes the plugins, and must
+// be called when the checks are no longer used.
+func loadExternalChecks(customChecks, policyDirs, plugins []string, pluginOptions plugin.Options) (externalChecks, func(), error) {
+	var res externalChecks
+	closePlugins := func() {
+		for _, p := range res.plugins {
+			_ = p.Close()
+		}
+	}
+
+	for _, path := range customChecks {
+		r, err := custom.LoadFile(path)
+		if err != nil {
+			return res, nil, fmt.Errorf("failed to load custom checks: %w", err)
+		}
+		res.customRules = append(res.customRules, r...)
+	}
+
+	if len(policyDirs) > 0 {
+		policies, err := policy.Load(policyDirs...)
+		if err != nil {
+			return res, nil, err
+		}
+		res.policies = policies
+	}
+
+	for _, path := range plugins {
+		p, err := plugin.Load(path, pluginOptions)
+		if err != nil {
+			closePlugins()
+			return res, nil, err
+		}
+		res.plugins = append(res.plugins, p)
+	}
+
+	return res, closePlugins, nil
+}
+
 func listToStructMap(items *[]string) map[string]struct{} {
 	structMap := make(map[string]struct{})
 	for _, testID := range *items {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type RunConfiguration struct {
@@ -16,6 +17,44 @@ type RunConfiguration struct {
 	KubernetesVersion                     Semver
 	MinReplicasDeployment                 int
 	MinReplicasHPA                        int
+
+	// SeverityOverrides changes the grade of failing checks, keyed by check ID
+	SeverityOverrides map[string]Severity
+
+	// RequireIgnoreReason makes ignore annotations without a reason invalid, the checks are not ignored
+	RequireIgnoreReason bool
+
+	// FailOnUnknownKind makes the documents of kinds that are not supported a critical failure, instead of
+	// informational
+	FailOnUnknownKind bool
+
+	// ExternalConfigRefs are the names of ConfigMaps and Secrets that are provided outside of the manifests, on the
+	// format [namespace/]name
+	ExternalConfigRefs []string
+
+	// CurrentTime is used to check if ignore annotations have expired, time.Now() is used if zero
+	CurrentTime time.Time
+}
+
+// Severity is the user facing name of a grade, used when overriding the grade of a check
+type Severity string
+
+const (
+	SeverityCritical Severity = "critical"
+	SeverityWarning  Severity = "warning"
+	SeverityInfo     Severity = "info"
+	SeverityOK       Severity = "ok"
+)
+
+var errInvalidSeverity = errors.New("invalid severity, must be one of 'critical', 'warning', 'info' or 'ok'")
+
+func ParseSeverity(s string) (Severity, error) {
+	switch sev := Severity(strings.ToLower(strings.TrimSpace(s))); sev {
+	case SeverityCritical, SeverityWarning, SeverityInfo, SeverityOK:
+		return sev, nil
+	default:
+		return "", errInvalidSeverity
+	}
 }
 
 type Semver struct {
@@ -69,3 +108,68 @@ func (s Semver) LessThan(other Semver) bool {
 func (s Semver) String() string {
 	return fmt.Sprintf("v%d.%d", s.Major, s.Minor)
 }
+
+// PodTemplateKind is a kind with a pod template, such as a custom resource of an operator. The objects of the kind
+// are scored with the pod checks. The paths are field paths from the root of the object, such as "spec.template".
+// ReplicasPath and SelectorPath are optional.
+type PodTemplateKind struct {
+	APIVersion      string `yaml:"apiVersion"`
+	Kind            string `yaml:"kind"`
+	PodTemplatePath string `yaml:"podTemplatePath"`
+	ReplicasPath    string `yaml:"replicasPath,omitempty"`
+	SelectorPath    string `yaml:"selectorPath,omitempty"`
+}
+
+var errInvalidPodTemplateKind = errors.New("invalid pod template kind, use the format '<apiVersion>/<kind>=<podTemplatePath>[,<replicasPath>[,<selectorPath>]]'")
+
+// ParsePodTemplateKind parses a pod template kind on the format
+// "<apiVersion>/<kind>=<podTemplatePath>[,<replicasPath>[,<selectorPath>]]", such as
+// "argoproj.io/v1alpha1/Rollout=spec.template,spec.replicas,spec.selector"
+func ParsePodTemplateKind(s string) (PodTemplateKind, error) {
+	gvk, paths, ok := strings.Cut(strings.TrimSpace(s), "=")
+	if !ok {
+		return PodTemplateKind{}, errInvalidPodTemplateKind
+	}
+	i := strings.LastIndex(gvk, "/")
+	if i < 0 {
+		return PodTemplateKind{}, errInvalidPodTemplateKind
+	}
+
+	parts := strings.Split(paths, ",")
+	if len(parts) > 3 {
+		return PodTemplateKind{}, errInvalidPodTemplateKind
+	}
+	parts = append(parts, "", "")
+
+	res := PodTemplateKind{
+		APIVersion:      gvk[:i],
+		Kind:            gvk[i+1:],
+		PodTemplatePath: parts[0],
+		ReplicasPath:    parts[1],
+		SelectorPath:    parts[2],
+	}
+	if err := res.Validate(); err != nil {
+		return PodTemplateKind{}, err
+	}
+	return res, nil
+}
+
+// Validate returns an error if a required field is not set
+func (k PodTemplateKind) Validate() error {
+	if k.APIVersion == "" || k.Kind == "" || k.PodTemplatePath == "" {
+		return errInvalidPodTemplateKind
+	}
+	return nil
+}
+
+// String returns the pod template kind on the format of ParsePodTemplateKind
+func (k PodTemplateKind) String() string {
+	res := k.APIVersion + "/" + k.Kind + "=" + k.PodTemplatePath
+	switch {
+	case k.SelectorPath != "":
+		res += "," + k.ReplicasPath + "," + k.SelectorPath
+	case k.ReplicasPath != "":
+		res += "," + k.ReplicasPath
+	}
+	return res
+}
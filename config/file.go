@@ -0,0 +1,352 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileVersion is the latest version of the configuration file format
+const FileVersion = 1
+
+// DefaultFileNames are the names of the configuration files that are discovered automatically, in order of preference
+var DefaultFileNames = []string{".kube-score.yaml", ".kube-score.yml"}
+
+// File is the on-disk representation of a kube-score configuration file.
+// All fields are optional except Version. Fields that are not set in the file
+// are left as nil, so that callers can distinguish them from zero values.
+type File struct {
+	Version int `yaml:"version"`
+
+	KubernetesVersion          *string  `yaml:"kubernetesVersion,omitempty"`
+	IgnoreTests                []string `yaml:"ignoreTests,omitempty"`
+	EnableOptionalTests        []string `yaml:"enableOptionalTests,omitempty"`
+	AllDefaultOptional         *bool    `yaml:"allDefaultOptional,omitempty"`
+	IgnoreContainerCpuLimit    *bool    `yaml:"ignoreContainerCpuLimit,omitempty"`
+	IgnoreContainerMemoryLimit *bool    `yaml:"ignoreContainerMemoryLimit,omitempty"`
+	MinReplicasDeployment      *int     `yaml:"minReplicasDeployment,omitempty"`
+	MinReplicasHPA             *int     `yaml:"minReplicasHPA,omitempty"`
+
+	// Severity overrides the grade of failing checks, keyed by check ID
+	Severity map[string]string `yaml:"severity,omitempty"`
+
+	// Params sets the values of check parameters, keyed by "<check-id>.<param-name>"
+	Params map[string]string `yaml:"params,omitempty"`
+
+	// CustomChecks is a list of files with custom checks. Relative paths are relative to the configuration file.
+	CustomChecks []string `yaml:"customChecks,omitempty"`
+
+	// PolicyDirs is a list of directories with Rego policies. Relative paths are relative to the configuration file.
+	PolicyDirs []string `yaml:"policyDirs,omitempty"`
+
+	// Baseline is the path to a baseline file. A relative path is relative to the configuration file.
+	Baseline            *string `yaml:"baseline,omitempty"`
+	FailOnStaleBaseline *bool   `yaml:"failOnStaleBaseline,omitempty"`
+
+	// Plugins is a list of WebAssembly plugins. Relative paths are relative to the configuration file.
+	Plugins           []string `yaml:"plugins,omitempty"`
+	PluginMemoryLimit *int     `yaml:"pluginMemoryLimit,omitempty"`
+	PluginTimeout     *string  `yaml:"pluginTimeout,omitempty"`
+
+	// Include and Exclude are patterns of the files to score when walking directories, in the gitignore syntax
+	Include []string `yaml:"include,omitempty"`
+	Exclude []string `yaml:"exclude,omitempty"`
+	// Kustomize renders directories with a kustomization file with kustomize
+	Kustomize *bool `yaml:"kustomize,omitempty"`
+
+	// HelmChart is the path to a Helm chart to render and score. Each entry in HelmValues is rendered and scored
+	// separately, and can be a comma separated list of values files that are merged. Relative paths are relative to
+	// the configuration file.
+	HelmChart       *string  `yaml:"helmChart,omitempty"`
+	HelmValues      []string `yaml:"helmValues,omitempty"`
+	HelmReleaseName *string  `yaml:"helmReleaseName,omitempty"`
+	HelmNamespace   *string  `yaml:"helmNamespace,omitempty"`
+
+	// ContinueOnParseError reports documents that can not be parsed as a failing check, instead of failing
+	ContinueOnParseError *bool `yaml:"continueOnParseError,omitempty"`
+
+	// Strict reports unknown and duplicate fields as a failing check
+	Strict *bool `yaml:"strict,omitempty"`
+
+	// FailOnUnknownKind reports the documents of kinds that are not supported as a failing check
+	FailOnUnknownKind *bool `yaml:"failOnUnknownKind,omitempty"`
+
+	// ExternalConfigRefs are the names of ConfigMaps and Secrets that are provided outside of the manifests
+	ExternalConfigRefs []string `yaml:"externalConfigRefs,omitempty"`
+
+	// PodTemplateKinds are kinds with a pod template that are scored with the pod checks, in addition to the built-in
+	// kinds
+	PodTemplateKinds []PodTemplateKind `yaml:"podTemplateKinds,omitempty"`
+
+	DisableIgnoreChecksAnnotations   *bool `yaml:"disableIgnoreChecksAnnotations,omitempty"`
+	DisableOptionalChecksAnnotations *bool `yaml:"disableOptionalChecksAnnotations,omitempty"`
+	RequireIgnoreReason              *bool `yaml:"requireIgnoreReason,omitempty"`
+
+	ExitOneOnWarning *bool   `yaml:"exitOneOnWarning,omitempty"`
+	OutputFormat     *string `yaml:"outputFormat,omitempty"`
+	OutputVersion    *string `yaml:"outputVersion,omitempty"`
+	Color            *string `yaml:"color,omitempty"`
+}
+
+// FileError is returned when a configuration file could not be loaded.
+// Line is 0 if the error could not be attributed to a specific line.
+type FileError struct {
+	Path string
+	Line int
+	Err  error
+}
+
+func (e *FileError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.Path, e.Line, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Err)
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// FindFile looks for a configuration file in dir and all of its parent directories.
+// An empty string is returned if no file could be found.
+func FindFile(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		for _, name := range DefaultFileNames {
+			candidate := filepath.Join(dir, name)
+			stat, err := os.Stat(candidate)
+			if err == nil && !stat.IsDir() {
+				return candidate, nil
+			}
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return "", err
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// LoadFile reads and validates the configuration file at path
+func LoadFile(path string) (*File, error) {
+	fp, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+
+	f, err := ParseFile(path, fp)
+	if err != nil {
+		return nil, err
+	}
+	f.resolvePaths(filepath.Dir(path))
+	return f, nil
+}
+
+// resolvePaths makes all relative paths in the file relative to dir
+func (f *File) resolvePaths(dir string) {
+	if f.Baseline != nil && !filepath.IsAbs(*f.Baseline) {
+		p := filepath.Join(dir, *f.Baseline)
+		f.Baseline = &p
+	}
+	if f.HelmChart != nil && !filepath.IsAbs(*f.HelmChart) {
+		p := filepath.Join(dir, *f.HelmChart)
+		f.HelmChart = &p
+	}
+	for _, paths := range [][]string{f.CustomChecks, f.PolicyDirs, f.Plugins} {
+		for i, p := range paths {
+			if !filepath.IsAbs(p) {
+				paths[i] = filepath.Join(dir, p)
+			}
+		}
+	}
+	for i, values := range f.HelmValues {
+		files := strings.Split(values, ",")
+		for j, p := range files {
+			if !filepath.IsAbs(p) {
+				files[j] = filepath.Join(dir, p)
+			}
+		}
+		f.HelmValues[i] = strings.Join(files, ",")
+	}
+}
+
+// ParseFile reads and validates a configuration file from r, name is used in error messages
+func ParseFile(name string, r io.Reader) (*File, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, &FileError{Path: name, Err: err}
+	}
+
+	var doc yaml.Node
+	if err := yaml.NewDecoder(bytes.NewReader(raw)).Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, &FileError{Path: name, Err: errors.New("the file is empty")}
+		}
+		return nil, yamlSyntaxError(name, err)
+	}
+
+	root := &doc
+	if root.Kind == yaml.DocumentNode && len(root.Content) == 1 {
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, &FileError{Path: name, Line: root.Line, Err: errors.New("expected a mapping at the top level")}
+	}
+
+	f := &File{}
+	if err := DecodeNode(name, root, f); err != nil {
+		return nil, err
+	}
+
+	if err := f.validate(name, root); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// DecodeNode decodes a mapping node into target, which must be a pointer to a struct.
+// Keys that do not exist in the struct, or that are set more than once are rejected.
+// Errors are returned as a FileError, where name is used as the path.
+func DecodeNode(name string, node *yaml.Node, target interface{}) error {
+	if node.Kind != yaml.MappingNode {
+		return &FileError{Path: name, Line: node.Line, Err: errors.New("expected a mapping")}
+	}
+
+	targetValue := reflect.ValueOf(target).Elem()
+	fields := make(map[string]reflect.Value)
+	for i := 0; i < targetValue.NumField(); i++ {
+		tag, _, _ := strings.Cut(targetValue.Type().Field(i).Tag.Get("yaml"), ",")
+		if tag != "" && tag != "-" {
+			fields[tag] = targetValue.Field(i)
+		}
+	}
+
+	seen := make(map[string]struct{})
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		if _, ok := seen[key.Value]; ok {
+			return &FileError{Path: name, Line: key.Line, Err: fmt.Errorf("field %q is set more than once", key.Value)}
+		}
+		seen[key.Value] = struct{}{}
+
+		field, ok := fields[key.Value]
+		if !ok {
+			return &FileError{Path: name, Line: key.Line, Err: fmt.Errorf("unknown field %q", key.Value)}
+		}
+		if err := value.Decode(field.Addr().Interface()); err != nil {
+			return &FileError{Path: name, Line: value.Line, Err: fmt.Errorf("field %q: %s", key.Value, yamlDecodeMessage(err))}
+		}
+	}
+
+	return nil
+}
+
+func (f *File) validate(name string, root *yaml.Node) error {
+	if f.Version == 0 {
+		return &FileError{Path: name, Line: root.Line, Err: errors.New("the required field \"version\" is not set")}
+	}
+	if f.Version != FileVersion {
+		return &FileError{Path: name, Line: valueLine(root, "version"), Err: fmt.Errorf("unsupported version %d, the latest supported version is %d", f.Version, FileVersion)}
+	}
+
+	if f.KubernetesVersion != nil {
+		if _, err := ParseSemver(*f.KubernetesVersion); err != nil {
+			return &FileError{Path: name, Line: valueLine(root, "kubernetesVersion"), Err: fmt.Errorf("invalid kubernetesVersion %q, use the format \"vN.NN\"", *f.KubernetesVersion)}
+		}
+	}
+
+	for _, field := range []struct {
+		key   string
+		value *int
+	}{
+		{"minReplicasDeployment", f.MinReplicasDeployment},
+		{"minReplicasHPA", f.MinReplicasHPA},
+	} {
+		if field.value != nil && *field.value < 0 {
+			return &FileError{Path: name, Line: valueLine(root, field.key), Err: fmt.Errorf("%s can not be negative", field.key)}
+		}
+	}
+
+	if severity := valueNode(root, "severity"); severity != nil {
+		for i := 0; i+1 < len(severity.Content); i += 2 {
+			if _, err := ParseSeverity(severity.Content[i+1].Value); err != nil {
+				return &FileError{Path: name, Line: severity.Content[i+1].Line, Err: fmt.Errorf("severity for %q: %w", severity.Content[i].Value, err)}
+			}
+		}
+	}
+
+	if kinds := valueNode(root, "podTemplateKinds"); kinds != nil {
+		for i, kind := range f.PodTemplateKinds {
+			if err := kind.Validate(); err != nil {
+				return &FileError{Path: name, Line: kinds.Content[i].Line, Err: errors.New("podTemplateKinds: apiVersion, kind and podTemplatePath are required")}
+			}
+		}
+	}
+
+	return nil
+}
+
+// valueNode returns the value node for key in the mapping node, or nil if the key is not set
+func valueNode(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// valueLine returns the line of the value for key in the mapping node
+func valueLine(node *yaml.Node, key string) int {
+	if value := valueNode(node, key); value != nil {
+		return value.Line
+	}
+	return node.Line
+}
+
+var yamlLinePrefix = regexp.MustCompile(`^(?:yaml: )?line (\d+): `)
+
+// yamlSyntaxError converts a syntax error from the yaml package to a FileError.
+// The yaml package embeds the line number in the message, which is moved to the Line field instead.
+func yamlSyntaxError(name string, err error) error {
+	var line int
+	msg := err.Error()
+	if m := yamlLinePrefix.FindStringSubmatch(msg); m != nil {
+		line, _ = strconv.Atoi(m[1])
+		msg = msg[len(m[0]):]
+	}
+	return &FileError{Path: name, Line: line, Err: errors.New(msg)}
+}
+
+// yamlDecodeMessage returns the message of a decoding error from the yaml package, without line numbers
+func yamlDecodeMessage(err error) string {
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		return err.Error()
+	}
+	msgs := make([]string, 0, len(typeErr.Errors))
+	for _, msg := range typeErr.Errors {
+		msgs = append(msgs, yamlLinePrefix.ReplaceAllString(msg, ""))
+	}
+	return strings.Join(msgs, ", ")
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+)
+
+func TestParseFile(t *testing.T) {
+	f, err := ParseFile("test.yaml", strings.NewReader(`version: 1
+kubernetesVersion: v1.29
+ignoreTests:
+  - container-resources
+  - pod-networkpolicy
+enableOptionalTests: [container-seccomp-profile]
+ignoreContainerCpuLimit: true
+minReplicasDeployment: 3
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, &File{
+		Version:                 1,
+		KubernetesVersion:       ptr.To("v1.29"),
+		IgnoreTests:             []string{"container-resources", "pod-networkpolicy"},
+		EnableOptionalTests:     []string{"container-seccomp-profile"},
+		IgnoreContainerCpuLimit: ptr.To(true),
+		MinReplicasDeployment:   ptr.To(3),
+	}, f)
+}
+
+func TestParseFileErrors(t *testing.T) {
+	tc := []struct {
+		input    string
+		expected string
+	}{
+		{"", "test.yaml: the file is empty"},
+		{"- foo\n", "test.yaml:1: expected a mapping at the top level"},
+		{"ignoreTests: [foo]\n", "test.yaml:1: the required field \"version\" is not set"},
+		{"version: 2\n", "test.yaml:1: unsupported version 2, the latest supported version is 1"},
+		{"version: 1\n\nfoo: bar\n", "test.yaml:3: unknown field \"foo\""},
+		{"version: 1\nversion: 1\n", "test.yaml:2: field \"version\" is set more than once"},
+		{"version: 1\nminReplicasHPA: abc\n", "test.yaml:2: field \"minReplicasHPA\": cannot unmarshal !!str `abc` into int"},
+		{"version: 1\nminReplicasHPA: -1\n", "test.yaml:2: minReplicasHPA can not be negative"},
+		{"version: 1\nkubernetesVersion: latest\n", "test.yaml:2: invalid kubernetesVersion \"latest\", use the format \"vN.NN\""},
+		{"version: 1\nseverity:\n  container-resources: warning\n  container-image-tag: fatal\n", "test.yaml:4: severity for \"container-image-tag\": invalid severity, must be one of 'critical', 'warning', 'info' or 'ok'"},
+		{"version: 1\n  foo: [\n", "test.yaml:2: mapping values are not allowed in this context"},
+		{"version: 1\npodTemplateKinds:\n- apiVersion: example.com/v1\n  kind: Worker\n", "test.yaml:3: podTemplateKinds: apiVersion, kind and podTemplatePath are required"},
+	}
+
+	for _, tc := range tc {
+		_, err := ParseFile("test.yaml", strings.NewReader(tc.input))
+		if assert.Error(t, err, tc.input) {
+			assert.Equal(t, tc.expected, err.Error())
+		}
+	}
+}
+
+func TestFindFile(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "a", "b")
+	assert.NoError(t, os.MkdirAll(sub, 0o755))
+
+	// No file found
+	path, err := FindFile(sub)
+	assert.NoError(t, err)
+	assert.Equal(t, "", path)
+
+	// Found in a parent directory
+	assert.NoError(t, os.WriteFile(filepath.Join(root, ".kube-score.yaml"), []byte("version: 1\n"), 0o600))
+	path, err = FindFile(sub)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, ".kube-score.yaml"), path)
+
+	// The closest file wins
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "a", ".kube-score.yml"), []byte("version: 1\n"), 0o600))
+	path, err = FindFile(sub)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "a", ".kube-score.yml"), path)
+}
+
+func TestLoadFileResolvesPaths(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".kube-score.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("version: 1\ncustomChecks: [checks/a.yaml, /abs/b.yaml]\npolicyDirs: [policies]\nplugins: [plugins/a.wasm]\nbaseline: baseline.yaml\nhelmChart: chart\nhelmValues: [values.yaml, \"base.yaml,/abs/prod.yaml\"]\n"), 0o600))
+
+	f, err := LoadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "checks", "a.yaml"), "/abs/b.yaml"}, f.CustomChecks)
+	assert.Equal(t, []string{filepath.Join(dir, "policies")}, f.PolicyDirs)
+	assert.Equal(t, []string{filepath.Join(dir, "plugins", "a.wasm")}, f.Plugins)
+	assert.Equal(t, filepath.Join(dir, "baseline.yaml"), *f.Baseline)
+	assert.Equal(t, filepath.Join(dir, "chart"), *f.HelmChart)
+	assert.Equal(t, []string{filepath.Join(dir, "values.yaml"), filepath.Join(dir, "base.yaml") + ",/abs/prod.yaml"}, f.HelmValues)
+}
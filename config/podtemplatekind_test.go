@@ -0,0 +1,39 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePodTemplateKind(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected PodTemplateKind
+	}{
+		{
+			"argoproj.io/v1alpha1/Rollout=spec.template,spec.replicas,spec.selector",
+			PodTemplateKind{APIVersion: "argoproj.io/v1alpha1", Kind: "Rollout", PodTemplatePath: "spec.template", ReplicasPath: "spec.replicas", SelectorPath: "spec.selector"},
+		},
+		{
+			"keda.sh/v1alpha1/ScaledJob=spec.jobTargetRef.template",
+			PodTemplateKind{APIVersion: "keda.sh/v1alpha1", Kind: "ScaledJob", PodTemplatePath: "spec.jobTargetRef.template"},
+		},
+		{
+			"v1/Worker=spec.template,,spec.selector",
+			PodTemplateKind{APIVersion: "v1", Kind: "Worker", PodTemplatePath: "spec.template", SelectorPath: "spec.selector"},
+		},
+	}
+
+	for _, tc := range cases {
+		kind, err := ParsePodTemplateKind(tc.input)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, kind)
+		assert.Equal(t, tc.input, kind.String())
+	}
+
+	for _, input := range []string{"", "Rollout=spec.template", "argoproj.io/v1alpha1/Rollout", "argoproj.io/v1alpha1/Rollout=", "v1/Worker=a,b,c,d"} {
+		_, err := ParsePodTemplateKind(input)
+		assert.Error(t, err, input)
+	}
+}
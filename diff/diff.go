@@ -0,0 +1,131 @@
+// Package diff compares the scores of two revisions of a set of objects, and reports the findings that are new,
+// resolved or changed in the new revision.
+package diff
+
+import (
+	"sort"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// Finding is a comment of a failing check on an object
+type Finding struct {
+	Check ks.Check
+	// Object is the ResourceRefKey of the object, objects are matched between the revisions by the key
+	Object string
+	// ObjectRef is the HumanFriendlyRef of the object
+	ObjectRef string
+	Path      string
+	Summary   string
+	Grade     scorecard.Grade
+	// OldGrade is the grade of the finding in the old revision, only set on changed findings
+	OldGrade scorecard.Grade
+}
+
+// Result is the difference between two revisions
+type Result struct {
+	// New are the findings that only exist in the new revision
+	New []Finding
+	// Resolved are the findings that only exist in the old revision
+	Resolved []Finding
+	// Changed are the findings that exist in both revisions, but with different grades
+	Changed []Finding
+}
+
+type findingKey struct {
+	check, object, path, summary string
+}
+
+func (f Finding) key() findingKey {
+	return findingKey{f.Check.ID, f.Object, f.Path, f.Summary}
+}
+
+// findings returns the findings of all failing checks in the scorecard.
+// Skipped checks, and checks that are suppressed by the baseline are not included.
+func findings(card *scorecard.Scorecard) map[findingKey]Finding {
+	res := make(map[findingKey]Finding)
+	for _, so := range *card {
+		for _, ts := range so.Checks {
+			if ts.Skipped || ts.Suppressed || ts.Grade > scorecard.GradeWarning {
+				continue
+			}
+			newFinding := func(path, summary string) Finding {
+				return Finding{
+					Check:     ts.Check,
+					Object:    so.ResourceRefKey(),
+					ObjectRef: so.HumanFriendlyRef(),
+					Path:      path,
+					Summary:   summary,
+					Grade:     ts.Grade,
+				}
+			}
+			if len(ts.Comments) == 0 {
+				f := newFinding("", "")
+				res[f.key()] = f
+			}
+			for _, c := range ts.Comments {
+				f := newFinding(c.Path, c.Summary)
+				res[f.key()] = f
+			}
+		}
+	}
+	return res
+}
+
+// Compare compares the score of the old revision with the score of the new revision
+func Compare(oldCard, newCard *scorecard.Scorecard) *Result {
+	oldFindings := findings(oldCard)
+	newFindings := findings(newCard)
+
+	res := &Result{}
+	for key, f := range newFindings {
+		old, ok := oldFindings[key]
+		switch {
+		case !ok:
+			res.New = append(res.New, f)
+		case old.Grade != f.Grade:
+			f.OldGrade = old.Grade
+			res.Changed = append(res.Changed, f)
+		}
+	}
+	for key, f := range oldFindings {
+		if _, ok := newFindings[key]; !ok {
+			res.Resolved = append(res.Resolved, f)
+		}
+	}
+
+	for _, list := range [][]Finding{res.New, res.Resolved, res.Changed} {
+		sort.Slice(list, func(i, j int) bool {
+			a, b := list[i].key(), list[j].key()
+			if a.check != b.check {
+				return a.check < b.check
+			}
+			if a.object != b.object {
+				return a.object < b.object
+			}
+			if a.path != b.path {
+				return a.path < b.path
+			}
+			return a.summary < b.summary
+		})
+	}
+
+	return res
+}
+
+// AnyIntroducedBelowOrEqualToGrade returns true if the new revision introduces a finding with a grade that is
+// lower than or equal to threshold, either as a new finding, or as a finding that got a lower grade
+func (r *Result) AnyIntroducedBelowOrEqualToGrade(threshold scorecard.Grade) bool {
+	for _, f := range r.New {
+		if f.Grade <= threshold {
+			return true
+		}
+	}
+	for _, f := range r.Changed {
+		if f.Grade <= threshold && f.Grade < f.OldGrade {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package diff
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+type location struct{}
+
+func (location) FileLocation() ks.FileLocation {
+	return ks.FileLocation{Name: "test.yaml", Line: 1}
+}
+
+func newCard(tests map[string][]scorecard.TestScore) *scorecard.Scorecard {
+	card := scorecard.New()
+	for name, scores := range tests {
+		o := card.NewObject(metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"}, metav1.ObjectMeta{Name: name}, nil)
+		for _, ts := range scores {
+			o.Add(ts, ts.Check, location{})
+		}
+	}
+	return &card
+}
+
+func score(id string, grade scorecard.Grade, comments ...scorecard.TestScoreComment) scorecard.TestScore {
+	return scorecard.TestScore{Check: ks.Check{ID: id, Name: id}, Grade: grade, Comments: comments}
+}
+
+func TestCompare(t *testing.T) {
+	cpu := scorecard.TestScoreComment{Path: "app", Summary: "CPU limit is not set"}
+	memory := scorecard.TestScoreComment{Path: "app", Summary: "Memory limit is not set"}
+
+	oldCard := newCard(map[string][]scorecard.TestScore{
+		"a": {score("container-resources", scorecard.GradeCritical, cpu), score("pod-probes", scorecard.GradeWarning)},
+		"b": {score("container-resources", scorecard.GradeCritical, memory)},
+	})
+	newerCard := newCard(map[string][]scorecard.TestScore{
+		"a": {
+			score("container-resources", scorecard.GradeCritical, cpu, memory),
+			score("pod-probes", scorecard.GradeCritical),
+			// Skipped checks are not findings
+			{Check: ks.Check{ID: "pod-networkpolicy"}, Grade: scorecard.GradeCritical, Skipped: true},
+		},
+	})
+
+	res := Compare(oldCard, newerCard)
+
+	findingRef := func(findings []Finding) (res []string) {
+		for _, f := range findings {
+			res = append(res, f.Check.ID+" "+f.Object+" "+f.Summary)
+		}
+		return
+	}
+	assert.Equal(t, []string{"container-resources Deployment/apps/v1//a Memory limit is not set"}, findingRef(res.New))
+	assert.Equal(t, []string{"container-resources Deployment/apps/v1//b Memory limit is not set"}, findingRef(res.Resolved))
+	assert.Equal(t, []string{"pod-probes Deployment/apps/v1//a "}, findingRef(res.Changed))
+	assert.Equal(t, scorecard.GradeWarning, res.Changed[0].OldGrade)
+
+	assert.True(t, res.AnyIntroducedBelowOrEqualToGrade(scorecard.GradeCritical))
+
+	// Only resolved findings, and findings that got a better grade
+	improved := newCard(map[string][]scorecard.TestScore{
+		"a": {score("container-resources", scorecard.GradeCritical, cpu), score("pod-probes", scorecard.GradeWarning)},
+	})
+	res = Compare(newerCard, improved)
+	assert.Len(t, res.Resolved, 1)
+	assert.Len(t, res.Changed, 1)
+	assert.False(t, res.AnyIntroducedBelowOrEqualToGrade(scorecard.GradeCritical))
+	assert.False(t, res.AnyIntroducedBelowOrEqualToGrade(scorecard.GradeWarning))
+}
+
+func TestHuman(t *testing.T) {
+	res := Compare(
+		newCard(map[string][]scorecard.TestScore{"a": {score("pod-probes", scorecard.GradeWarning)}}),
+		newCard(map[string][]scorecard.TestScore{"a": {
+			score("pod-probes", scorecard.GradeCritical),
+			score("container-resources", scorecard.GradeCritical, scorecard.TestScoreComment{Path: "app", Summary: "CPU limit is not set"}),
+		}}),
+	)
+
+	out, err := io.ReadAll(Human(res, false))
+	assert.NoError(t, err)
+	assert.Equal(t, `container-resources (container-resources)
+    + [CRITICAL] a apps/v1/Deployment (app): CPU limit is not set
+pod-probes (pod-probes)
+    ~ [WARNING -> CRITICAL] a apps/v1/Deployment
+1 new, 0 resolved and 1 changed findings
+`, string(out))
+}
@@ -0,0 +1,112 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/fatih/color"
+
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// Human renders the result as text, grouped by check. New findings are prefixed with "+", resolved findings
+// with "-" and changed findings with "~".
+func Human(r *Result, useColors bool) io.Reader {
+	// Override usage of colors to our own preference
+	color.NoColor = !useColors
+
+	type line struct {
+		prefix  string
+		finding Finding
+		color   *color.Color
+	}
+	var checkIDs []string
+	checkNames := make(map[string]string)
+	lines := make(map[string][]line)
+	add := func(prefix string, c *color.Color, findings []Finding) {
+		for _, f := range findings {
+			if _, ok := lines[f.Check.ID]; !ok {
+				checkIDs = append(checkIDs, f.Check.ID)
+				checkNames[f.Check.ID] = f.Check.Name
+			}
+			lines[f.Check.ID] = append(lines[f.Check.ID], line{prefix, f, c})
+		}
+	}
+	add("+", color.New(color.FgRed), r.New)
+	add("-", color.New(color.FgGreen), r.Resolved)
+	add("~", color.New(color.FgYellow), r.Changed)
+
+	sort.Strings(checkIDs)
+
+	w := bytes.NewBufferString("")
+	for _, id := range checkIDs {
+		_, _ = color.New(color.FgMagenta).Fprintf(w, "%s (%s)\n", checkNames[id], id)
+		for _, l := range lines[id] {
+			grade := l.finding.Grade.String()
+			if l.finding.OldGrade != 0 {
+				grade = l.finding.OldGrade.String() + " -> " + grade
+			}
+			_, _ = l.color.Fprintf(w, "    %s [%s] %s", l.prefix, grade, l.finding.ObjectRef)
+			if l.finding.Path != "" {
+				_, _ = fmt.Fprintf(w, " (%s)", l.finding.Path)
+			}
+			if l.finding.Summary != "" {
+				_, _ = fmt.Fprintf(w, ": %s", l.finding.Summary)
+			}
+			_, _ = fmt.Fprintln(w)
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "%d new, %d resolved and %d changed findings\n", len(r.New), len(r.Resolved), len(r.Changed))
+	return w
+}
+
+type jsonResult struct {
+	New      []jsonFinding `json:"new"`
+	Resolved []jsonFinding `json:"resolved"`
+	Changed  []jsonFinding `json:"changed"`
+}
+
+type jsonFinding struct {
+	Check     string          `json:"check"`
+	CheckName string          `json:"check_name"`
+	Object    string          `json:"object"`
+	ObjectRef string          `json:"object_ref"`
+	Path      string          `json:"path"`
+	Summary   string          `json:"summary"`
+	Grade     scorecard.Grade `json:"grade"`
+	OldGrade  scorecard.Grade `json:"old_grade,omitempty"`
+}
+
+// JSON renders the result as JSON
+func JSON(r *Result) io.Reader {
+	convert := func(in []Finding) []jsonFinding {
+		res := make([]jsonFinding, 0, len(in))
+		for _, f := range in {
+			res = append(res, jsonFinding{
+				Check:     f.Check.ID,
+				CheckName: f.Check.Name,
+				Object:    f.Object,
+				ObjectRef: f.ObjectRef,
+				Path:      f.Path,
+				Summary:   f.Summary,
+				Grade:     f.Grade,
+				OldGrade:  f.OldGrade,
+			})
+		}
+		return res
+	}
+
+	j, err := json.MarshalIndent(jsonResult{
+		New:      convert(r.New),
+		Resolved: convert(r.Resolved),
+		Changed:  convert(r.Changed),
+	}, "", "    ")
+	if err != nil {
+		panic(err)
+	}
+	return bytes.NewBuffer(j)
+}
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"io"
+	"strconv"
+	"strings"
 
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 
@@ -9,6 +11,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -25,15 +28,122 @@ type NamedReader interface {
 	Name() string
 }
 
+// RenderedReader is a NamedReader with a single object that is rendered from the file Name, such as the output of
+// kustomize. Line is the line in the file that the object is rendered from.
+type RenderedReader interface {
+	NamedReader
+	Line() int
+}
+
 type FileLocation struct {
 	Name string
 	Line int
+	// Fields are the positions of the fields of the object in the file, if the object is read from the file as-is
+	Fields FieldPositions `json:"-"`
+}
+
+// Position is a line and column in a file, starting at 1
+type Position struct {
+	Line   int
+	Column int
+}
+
+// FieldPositions are the positions of the fields in an object, by their field path. A field path is the keys from the
+// root of the object separated by ".", with the index of list items in brackets, such as
+// "spec.template.spec.containers[1].securityContext.privileged". Keys that contain ".", "[", "]" or quotes are quoted
+// in brackets, such as `metadata.annotations["kube-score/ignore"]`. The root of the object is the empty path.
+type FieldPositions map[string]Position
+
+// FieldPath returns the path of the key in the object at parent
+func FieldPath(parent, key string) string {
+	if key == "" || strings.ContainsAny(key, ".[]\"'") {
+		return parent + "[" + strconv.Quote(key) + "]"
+	}
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// FieldIndexPath returns the path of the list item at index in the list at parent
+func FieldIndexPath(parent string, index int) string {
+	return parent + "[" + strconv.Itoa(index) + "]"
+}
+
+// Find returns the position of the field at path. If the field does not exist, such as a field that should be set
+// but is missing, the position of the closest parent that exists is returned.
+func (f FieldPositions) Find(path string) (Position, bool) {
+	if len(f) == 0 {
+		return Position{}, false
+	}
+	for {
+		if pos, ok := f[path]; ok {
+			return pos, true
+		}
+		if path == "" {
+			return Position{}, false
+		}
+		path = parentFieldPath(path)
+	}
+}
+
+// parentFieldPath returns the path of the parent of the field at path
+func parentFieldPath(path string) string {
+	if strings.HasSuffix(path, "]") {
+		// A quoted key can contain "[", find the start of the quoted string
+		if strings.HasSuffix(path, "\"]") {
+			for i := strings.LastIndex(path, "[\""); i >= 0; i = strings.LastIndex(path[:i], "[\"") {
+				if _, err := strconv.Unquote(path[i+1 : len(path)-1]); err == nil {
+					return path[:i]
+				}
+			}
+		}
+		if i := strings.LastIndex(path, "["); i >= 0 {
+			return path[:i]
+		}
+	}
+	if i := strings.LastIndexAny(path, ".]"); i >= 0 {
+		if path[i] == ']' {
+			return path[:i+1]
+		}
+		return path[:i]
+	}
+	return ""
 }
 
 type BothMeta struct {
 	TypeMeta   metav1.TypeMeta
 	ObjectMeta metav1.ObjectMeta
 	FileLocationer
+
+	// DecodeObject decodes the full object in its unstructured form, see Object.
+	// It's nil if the BothMeta was not created by the parser.
+	DecodeObject func() map[string]interface{}
+
+	// UnknownFields are the fields of the object that are not a part of its kind, or are set more than once.
+	// They are only detected if the object was parsed with strict schema validation, and Strict is true.
+	UnknownFields []UnknownField
+	Strict        bool
+}
+
+// Object returns the full object in its unstructured form, as it was read from the input. The data and stringData of
+// Secrets are removed. The object is decoded when it's first needed, and is nil if the BothMeta was not created by the
+// parser.
+func (m BothMeta) Object() map[string]interface{} {
+	if m.DecodeObject == nil {
+		return nil
+	}
+	return m.DecodeObject()
+}
+
+// UnknownField is a field that is not a part of the kind of its object, or a field that is set more than once
+type UnknownField struct {
+	// Path is the path of the field, see FieldPositions
+	Path string
+	// Duplicate is true if the field is set more than once
+	Duplicate bool
+	// Suggestion is the name of the valid field that is the closest to an unknown field, if any
+	Suggestion string
 }
 
 type PodSpecer interface {
@@ -43,6 +153,26 @@ type PodSpecer interface {
 	GetPodTemplateSpec() corev1.PodTemplateSpec
 }
 
+// PodTemplateFielder is implemented by PodSpecers that have their pod template at another field than
+// "spec.template", such as custom resources. PodTemplateField returns the field path of the template, see
+// FieldPositions.
+type PodTemplateFielder interface {
+	PodTemplateField() string
+}
+
+// SelectorFielder is implemented by Replicated PodSpecers that have their selector at another field than
+// "spec.selector", such as custom resources. SelectorField returns the field path of the selector, see FieldPositions.
+type SelectorFielder interface {
+	SelectorField() string
+}
+
+// Replicated is implemented by PodSpecers that run replicas of their pod that are selected by a label selector, such as
+// custom resources with configured replicas and selector paths. Replicas and Selector are nil if they are not set.
+type Replicated interface {
+	Replicas() *int32
+	Selector() *metav1.LabelSelector
+}
+
 type FileLocationer interface {
 	FileLocation() FileLocation
 }
@@ -148,6 +278,107 @@ type HorizontalPodAutoscalers interface {
 	HorizontalPodAutoscalers() []HpaTargeter
 }
 
+// Role is a Role or a ClusterRole, the kind of the TypeMeta tells them apart
+type Role interface {
+	GetTypeMeta() metav1.TypeMeta
+	GetObjectMeta() metav1.ObjectMeta
+	Rules() []rbacv1.PolicyRule
+	FileLocationer
+}
+
+type Roles interface {
+	Roles() []Role
+}
+
+// RoleBinding is a RoleBinding or a ClusterRoleBinding, the kind of the TypeMeta tells them apart
+type RoleBinding interface {
+	GetTypeMeta() metav1.TypeMeta
+	GetObjectMeta() metav1.ObjectMeta
+	RoleRef() rbacv1.RoleRef
+	Subjects() []rbacv1.Subject
+	FileLocationer
+}
+
+type RoleBindings interface {
+	RoleBindings() []RoleBinding
+}
+
+type ServiceAccount interface {
+	ServiceAccount() corev1.ServiceAccount
+	FileLocationer
+}
+
+type ServiceAccounts interface {
+	ServiceAccounts() []ServiceAccount
+}
+
+type ConfigMap interface {
+	ConfigMap() corev1.ConfigMap
+	FileLocationer
+}
+
+type ConfigMaps interface {
+	ConfigMaps() []ConfigMap
+}
+
+// Secret is a Secret without its values, the values of Data and StringData are empty. The keys are kept.
+type Secret interface {
+	Secret() corev1.Secret
+	FileLocationer
+}
+
+type Secrets interface {
+	Secrets() []Secret
+}
+
+// ParseError is a document that could not be parsed. TypeMeta and ObjectMeta are the parts of the metadata that could
+// be read from the document, if any.
+type ParseError struct {
+	TypeMeta   metav1.TypeMeta
+	ObjectMeta metav1.ObjectMeta
+	Location   FileLocation
+	Err        error
+}
+
+func (p ParseError) FileLocation() FileLocation {
+	return p.Location
+}
+
+type ParseErrors interface {
+	ParseErrors() []ParseError
+}
+
+// UnknownKind is a document of a kind that is not supported by kube-score, such as a custom resource that is not
+// registered with the parser, or an object with a typo in its apiVersion. Suggestion is the apiVersion and kind of a
+// known kind that is close to the kind of the document, if any.
+type UnknownKind struct {
+	TypeMeta   metav1.TypeMeta
+	ObjectMeta metav1.ObjectMeta
+	Location   FileLocation
+	Suggestion string
+}
+
+func (u UnknownKind) FileLocation() FileLocation {
+	return u.Location
+}
+
+type UnknownKinds interface {
+	UnknownKinds() []UnknownKind
+}
+
+// Object is an object of a kind that is registered with the parser by a library user, such as a custom resource.
+// Decoded returns the object as it was decoded by the decoder of the kind.
+type Object interface {
+	GetTypeMeta() metav1.TypeMeta
+	GetObjectMeta() metav1.ObjectMeta
+	Decoded() any
+	FileLocationer
+}
+
+type Objects interface {
+	Objects() []Object
+}
+
 type AllTypes interface {
 	Metas
 	Pods
@@ -160,4 +391,12 @@ type AllTypes interface {
 	CronJobs
 	PodDisruptionBudgets
 	HorizontalPodAutoscalers
+	Roles
+	RoleBindings
+	ServiceAccounts
+	ConfigMaps
+	Secrets
+	ParseErrors
+	Objects
+	UnknownKinds
 }
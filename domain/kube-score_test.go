@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldPath(t *testing.T) {
+	assert.Equal(t, "spec", FieldPath("", "spec"))
+	assert.Equal(t, "spec.containers", FieldPath("spec", "containers"))
+	assert.Equal(t, `metadata.annotations["kube-score/ignore.reason"]`, FieldPath("metadata.annotations", "kube-score/ignore.reason"))
+	assert.Equal(t, "spec.containers[1]", FieldIndexPath("spec.containers", 1))
+}
+
+func TestFieldPositionsFind(t *testing.T) {
+	fields := FieldPositions{
+		"":                                 {Line: 1, Column: 1},
+		"spec":                             {Line: 5, Column: 1},
+		"spec.containers[1]":               {Line: 10, Column: 5},
+		"spec.containers[1].resources":     {Line: 12, Column: 7},
+		`metadata.annotations["a.b[0]"]`:   {Line: 3, Column: 5},
+		`metadata.annotations["a.b[0]"].c`: {Line: 4, Column: 7},
+	}
+
+	for path, expected := range map[string]Position{
+		"spec.containers[1].resources":            {Line: 12, Column: 7},
+		"spec.containers[1].resources.limits.cpu": {Line: 12, Column: 7},
+		"spec.containers[1].securityContext":      {Line: 10, Column: 5},
+		"spec.containers[1].env[3]":               {Line: 10, Column: 5},
+		"spec.containers[0]":                      {Line: 5, Column: 1},
+		"status":                                  {Line: 1, Column: 1},
+		`metadata.annotations["a.b[0]"].c.d`:      {Line: 4, Column: 7},
+		`metadata.annotations["a.b[0]"].e`:        {Line: 3, Column: 5},
+		`metadata.annotations["a.b[0]"]["x[\"y"]`: {Line: 3, Column: 5},
+	} {
+		pos, ok := fields.Find(path)
+		assert.True(t, ok, path)
+		assert.Equal(t, expected, pos, path)
+	}
+
+	_, ok := FieldPositions(nil).Find("spec")
+	assert.False(t, ok)
+}
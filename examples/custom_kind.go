@@ -0,0 +1,70 @@
+package examples
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/zegl/kube-score/config"
+	"github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/parser"
+	"github.com/zegl/kube-score/score"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/scorecard"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Database is the Go type of a custom resource
+type Database struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              DatabaseSpec `json:"spec"`
+}
+
+type DatabaseSpec struct {
+	Replicas int  `json:"replicas"`
+	Backups  bool `json:"backups"`
+}
+
+// ExampleCheckCustomKind shows how kube-score can be extended with a kind that it does not support, and with typed
+// checks of the objects of the kind
+//
+// In this example, raw is a YAML encoded Database
+func ExampleCheckCustomKind(raw []byte) (*scorecard.Scorecard, error) {
+	p, err := parser.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Databases are decoded to *Database
+	parser.RegisterKind[Database](p, schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Database"})
+
+	allObjects, err := p.ParseFiles(
+		[]domain.NamedReader{
+			namedReader{
+				Reader: bytes.NewReader(raw),
+				name:   "input",
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The check is only run on the objects that are decoded to *Database
+	allChecks := checks.New(nil)
+	checks.RegisterObjectCheck(allChecks, "Database", "Database has backups", "Makes sure that Databases are backed up", databaseHasBackups)
+
+	return score.Score(allObjects, allChecks, &config.RunConfiguration{})
+}
+
+func databaseHasBackups(db *Database) (score scorecard.TestScore, err error) {
+	if !db.Spec.Backups {
+		score.Grade = scorecard.GradeCritical
+		score.AddCommentWithField("", "Backups are not enabled", fmt.Sprintf("The %d replicas of the database are not backed up", db.Spec.Replicas), "spec.backups")
+		return
+	}
+	score.Grade = scorecard.GradeAllOK
+	return
+}
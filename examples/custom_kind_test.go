@@ -0,0 +1,31 @@
+package examples
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func TestExampleCheckCustomKind(t *testing.T) {
+	card, err := ExampleCheckCustomKind([]byte(`
+apiVersion: example.com/v1
+kind: Database
+metadata:
+    name: example
+spec:
+    replicas: 3
+    backups: false`))
+
+	assert.NoError(t, err)
+
+	assert.Len(t, *card, 1)
+
+	for _, v := range *card {
+		assert.Len(t, v.Checks, 1)
+		assert.Equal(t, "database-has-backups", v.Checks[0].Check.ID)
+		assert.Equal(t, scorecard.GradeCritical, v.Checks[0].Grade)
+		assert.Equal(t, "The 3 replicas of the database are not backed up", v.Checks[0].Comments[0].Description)
+		assert.Equal(t, 8, v.Checks[0].Comments[0].Line)
+	}
+}
@@ -0,0 +1,434 @@
+package fix
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// editor edits the lines of a YAML file, from the positions of the nodes in the file.
+// Line numbers are 1-based, as in yaml.Node.
+type editor struct {
+	lines []string
+}
+
+func newEditor(src []byte) *editor {
+	return &editor{lines: strings.Split(string(src), "\n")}
+}
+
+func (e *editor) bytes() []byte {
+	return []byte(strings.Join(e.lines, "\n"))
+}
+
+// parsePointer splits a JSON pointer (RFC 6901) into its segments
+func parsePointer(pointer string) ([]string, error) {
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, errors.New("the path must start with /")
+	}
+	segments := strings.Split(pointer[1:], "/")
+	for i, s := range segments {
+		segments[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(s)
+	}
+	return segments, nil
+}
+
+func (e *editor) apply(root *yaml.Node, op scorecard.PatchOperation) error {
+	if op.Op != "add" && op.Op != "replace" && op.Op != "remove" {
+		return fmt.Errorf("the operation %q is not supported", op.Op)
+	}
+
+	segments, err := parsePointer(op.Path)
+	if err != nil {
+		return err
+	}
+
+	node := root
+	var parent *yaml.Node
+	var index int
+	for i, segment := range segments {
+		switch node.Kind {
+		case yaml.MappingNode:
+			index = mappingIndex(node, segment)
+			if index < 0 {
+				if op.Op != "add" {
+					return errors.New("the path does not exist")
+				}
+				return e.addMember(node, segment, nestedValue(segments[i+1:], op.Value))
+			}
+			parent, node = node, node.Content[index+1]
+		case yaml.SequenceNode:
+			if segment == "-" {
+				if op.Op != "add" || i != len(segments)-1 {
+					return errors.New("- can only be used to add to the end of an array")
+				}
+				return e.appendItem(node, op.Value)
+			}
+			n, err := strconv.Atoi(segment)
+			if err != nil || n < 0 || n >= len(node.Content) {
+				return fmt.Errorf("the index %q is out of range", segment)
+			}
+			parent, index, node = node, n, node.Content[n]
+		default:
+			// Keys without a value are replaced by the value that is added
+			if node.Tag == "!!null" && op.Op == "add" && parent != nil {
+				return e.replace(parent, index, nestedValue(segments[i:], op.Value))
+			}
+			return fmt.Errorf("/%s is not an object or an array", strings.Join(segments[:i], "/"))
+		}
+	}
+
+	if parent == nil {
+		return errors.New("the root of the object can not be changed")
+	}
+	if op.Op == "remove" {
+		return e.remove(parent, index)
+	}
+	if parent.Kind == yaml.SequenceNode && op.Op == "add" {
+		return errors.New("adding items in the middle of an array is not supported")
+	}
+	return e.replace(parent, index, op.Value)
+}
+
+// nestedValue returns value nested in objects with the keys
+func nestedValue(keys []string, value interface{}) interface{} {
+	for i := len(keys) - 1; i >= 0; i-- {
+		value = map[string]interface{}{keys[i]: value}
+	}
+	return value
+}
+
+// mappingIndex returns the index of the key in the content of the mapping, or -1 if the key doesn't exist
+func mappingIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func isFlow(n *yaml.Node) bool {
+	return n.Style&yaml.FlowStyle != 0
+}
+
+func (e *editor) addMember(mapping *yaml.Node, key string, value interface{}) error {
+	if isFlow(mapping) || len(mapping.Content) == 0 {
+		k, v, err := encodeMember(key, value)
+		if err != nil {
+			return err
+		}
+		mapping.Content = append(mapping.Content, k, v)
+		return e.rewriteFlow(mapping)
+	}
+
+	indent := mapping.Content[0].Column - 1
+	rendered, err := renderBlock(map[string]interface{}{key: value}, indent)
+	if err != nil {
+		return err
+	}
+	e.insert(e.end(mapping, indent), rendered)
+	return nil
+}
+
+func (e *editor) appendItem(sequence *yaml.Node, value interface{}) error {
+	if isFlow(sequence) || len(sequence.Content) == 0 {
+		var v yaml.Node
+		if err := v.Encode(value); err != nil {
+			return err
+		}
+		sequence.Content = append(sequence.Content, &v)
+		return e.rewriteFlow(sequence)
+	}
+
+	indent := sequence.Column - 1
+	rendered, err := renderBlock([]interface{}{value}, indent)
+	if err != nil {
+		return err
+	}
+	e.insert(e.end(sequence, indent), rendered)
+	return nil
+}
+
+// replace replaces the value at index in parent, index is the index of the key if parent is a mapping
+func (e *editor) replace(parent *yaml.Node, index int, value interface{}) error {
+	node := parent.Content[index]
+	if parent.Kind == yaml.MappingNode {
+		node = parent.Content[index+1]
+	}
+
+	if isFlow(parent) {
+		var v yaml.Node
+		if err := v.Encode(value); err != nil {
+			return err
+		}
+		*node = v
+		return e.rewriteFlow(parent)
+	}
+
+	// Values on a single line are replaced in place, to keep the comments on the line
+	switch {
+	case node.Kind == yaml.ScalarNode && node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) == 0:
+		if start, end, ok := e.scalarSpan(node); ok {
+			if rendered, err := renderInline(value, false); err == nil && !strings.Contains(rendered, "\n") {
+				e.lines[node.Line-1] = e.lines[node.Line-1][:start] + rendered + e.lines[node.Line-1][end:]
+				return nil
+			}
+		}
+	case isFlow(node):
+		var v yaml.Node
+		if err := v.Encode(value); err != nil {
+			return err
+		}
+		v.Style |= yaml.FlowStyle
+		v.Line, v.Column = node.Line, node.Column
+		*node = v
+		return e.rewriteFlow(node)
+	}
+
+	if parent.Kind != yaml.MappingNode {
+		return errors.New("replacing multi-line items in arrays is not supported")
+	}
+	key := parent.Content[index]
+	first, last, err := e.memberLines(parent, index)
+	if err != nil {
+		return err
+	}
+	rendered, err := renderBlock(map[string]interface{}{key.Value: value}, key.Column-1)
+	if err != nil {
+		return err
+	}
+	e.lines = append(e.lines[:first-1], append(rendered, e.lines[last:]...)...)
+	return nil
+}
+
+func (e *editor) remove(parent *yaml.Node, index int) error {
+	if isFlow(parent) {
+		if parent.Kind == yaml.MappingNode {
+			parent.Content = append(parent.Content[:index], parent.Content[index+2:]...)
+		} else {
+			parent.Content = append(parent.Content[:index], parent.Content[index+1:]...)
+		}
+		return e.rewriteFlow(parent)
+	}
+
+	var first, last int
+	if parent.Kind == yaml.MappingNode {
+		var err error
+		if first, last, err = e.memberLines(parent, index); err != nil {
+			return err
+		}
+	} else {
+		item := parent.Content[index]
+		first = item.Line
+		last = e.end(item, parent.Column-1)
+	}
+	e.lines = append(e.lines[:first-1], e.lines[last:]...)
+	return nil
+}
+
+// memberLines returns the first and last line of the key at index in the mapping, and its value
+func (e *editor) memberLines(mapping *yaml.Node, index int) (first, last int, err error) {
+	key := mapping.Content[index]
+	start := byteOffset(e.lines[key.Line-1], key.Column)
+	if strings.TrimSpace(e.lines[key.Line-1][:start]) != "" {
+		return 0, 0, fmt.Errorf("the key %q is not on a line of its own", key.Value)
+	}
+
+	value := mapping.Content[index+1]
+	last = e.extend(max(key.Line, maxLine(value)), key.Column-1)
+	return key.Line, last, nil
+}
+
+// end returns the last line of the block collection n, with the items at the indentation indent
+func (e *editor) end(n *yaml.Node, indent int) int {
+	return e.extend(maxLine(n), indent)
+}
+
+// extend returns the last line that continues the line, the lines that are indented more than indent belong to the
+// value on the line, such as the lines of multi-line strings
+func (e *editor) extend(line, indent int) int {
+	for i := line + 1; i <= len(e.lines); i++ {
+		l := e.lines[i-1]
+		trimmed := strings.TrimSpace(l)
+		if trimmed == "" {
+			continue
+		}
+		if len(l)-len(strings.TrimLeft(l, " ")) <= indent || strings.HasPrefix(l, "---") || l == "..." {
+			break
+		}
+		line = i
+	}
+	return line
+}
+
+// maxLine returns the last line that n or any of its children starts on
+func maxLine(n *yaml.Node) int {
+	line := n.Line
+	for _, c := range n.Content {
+		line = max(line, maxLine(c))
+	}
+	return line
+}
+
+// insert inserts lines after the line
+func (e *editor) insert(line int, lines []string) {
+	e.lines = append(e.lines[:line], append(lines, e.lines[line:]...)...)
+}
+
+// rewriteFlow replaces the text of the flow collection n with the current content of n
+func (e *editor) rewriteFlow(n *yaml.Node) error {
+	line := e.lines[n.Line-1]
+	start := byteOffset(line, n.Column)
+	end := flowEnd(line, start)
+	if end < 0 {
+		return errors.New("collections on multiple lines in flow style are not supported")
+	}
+
+	c := *n
+	c.Style |= yaml.FlowStyle
+	rendered, err := renderInline(&c, true)
+	if err != nil {
+		return err
+	}
+	e.lines[n.Line-1] = line[:start] + rendered + line[end:]
+	return nil
+}
+
+// scalarSpan returns the byte offsets of the text of the scalar on its line
+func (e *editor) scalarSpan(n *yaml.Node) (start, end int, ok bool) {
+	line := e.lines[n.Line-1]
+	start = byteOffset(line, n.Column)
+
+	switch {
+	case n.Style&yaml.DoubleQuotedStyle != 0:
+		for i := start + 1; i < len(line); i++ {
+			switch line[i] {
+			case '\\':
+				i++
+			case '"':
+				return start, i + 1, true
+			}
+		}
+		return 0, 0, false
+	case n.Style&yaml.SingleQuotedStyle != 0:
+		for i := start + 1; i < len(line); i++ {
+			if line[i] == '\'' {
+				if i+1 < len(line) && line[i+1] == '\'' {
+					i++
+					continue
+				}
+				return start, i + 1, true
+			}
+		}
+		return 0, 0, false
+	default:
+		end = len(line)
+		if i := strings.Index(line[start:], " #"); i >= 0 {
+			end = start + i
+		}
+		rest := strings.TrimRight(line[start:end], " \t")
+		// Plain scalars that continue on the next line are not on a single line
+		if rest != n.Value {
+			return 0, 0, false
+		}
+		return start, start + len(rest), true
+	}
+}
+
+// flowEnd returns the byte offset after the end of the flow collection that starts at start, or -1 if the collection
+// does not end on the line
+func flowEnd(line string, start int) int {
+	depth := 0
+	var quote byte
+	for i := start; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return -1
+}
+
+// byteOffset converts the 1-based column of a yaml.Node to a byte offset in the line
+func byteOffset(line string, column int) int {
+	offset := 0
+	for i := 1; i < column && offset < len(line); i++ {
+		_, size := utf8.DecodeRuneInString(line[offset:])
+		offset += size
+	}
+	return offset
+}
+
+func encodeMember(key string, value interface{}) (*yaml.Node, *yaml.Node, error) {
+	var k, v yaml.Node
+	if err := k.Encode(key); err != nil {
+		return nil, nil, err
+	}
+	if err := v.Encode(value); err != nil {
+		return nil, nil, err
+	}
+	return &k, &v, nil
+}
+
+// renderBlock renders value in block style, indented by indent spaces
+func renderBlock(value interface{}, indent int) ([]string, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(value); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	prefix := strings.Repeat(" ", indent)
+	for i, l := range lines {
+		lines[i] = prefix + l
+	}
+	return lines, nil
+}
+
+// renderInline renders value on a single line, collections are rendered in flow style if flow is true
+func renderInline(value interface{}, flow bool) (string, error) {
+	var n yaml.Node
+	if node, ok := value.(*yaml.Node); ok {
+		n = *node
+	} else if err := n.Encode(value); err != nil {
+		return "", err
+	}
+	if n.Kind != yaml.ScalarNode && !flow {
+		return "", errors.New("the value is not a scalar")
+	}
+	if flow {
+		n.Style |= yaml.FlowStyle
+	}
+
+	out, err := yaml.Marshal(&n)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
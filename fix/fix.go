@@ -0,0 +1,184 @@
+// Package fix applies the fixes that are suggested by checks to the source YAML of the objects.
+//
+// The fixes are applied as edits of the text of the files, so that comments, the order of keys and the
+// formatting of the parts of the files that are not changed by a fix are kept as they are.
+package fix
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v3"
+
+	"github.com/zegl/kube-score/parser"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// Object identifies an object in a file
+type Object struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+}
+
+func (o Object) String() string {
+	s := o.Name
+	if o.Namespace != "" {
+		s += "/" + o.Namespace
+	}
+	return s + " " + o.APIVersion + "/" + o.Kind
+}
+
+// Change is a fix of a failing check on an object
+type Change struct {
+	Object Object
+	Check  string
+	Fix    scorecard.Fix
+}
+
+// Changes returns the fixes of all failing checks in the scorecard, grouped by the name of the file of the object.
+// Skipped checks, and checks that are suppressed by the baseline are not fixed.
+func Changes(card *scorecard.Scorecard) map[string][]Change {
+	keys := make([]string, 0, len(*card))
+	for key := range *card {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	res := make(map[string][]Change)
+	for _, key := range keys {
+		so := (*card)[key]
+		object := Object{
+			APIVersion: so.TypeMeta.APIVersion,
+			Kind:       so.TypeMeta.Kind,
+			Namespace:  so.ObjectMeta.Namespace,
+			Name:       so.ObjectMeta.Name,
+		}
+		// Sorted by the check, so that the output is the same in every run
+		checks := make([]scorecard.TestScore, len(so.Checks))
+		copy(checks, so.Checks)
+		sort.SliceStable(checks, func(i, j int) bool {
+			return checks[i].Check.ID < checks[j].Check.ID
+		})
+
+		for _, ts := range checks {
+			if ts.Skipped || ts.Suppressed || ts.Grade > scorecard.GradeWarning {
+				continue
+			}
+			for _, f := range ts.Fixes {
+				res[so.FileLocation.Name] = append(res[so.FileLocation.Name], Change{Object: object, Check: ts.Check.ID, Fix: f})
+			}
+		}
+	}
+	return res
+}
+
+// ApplyChanges applies the changes to src. Changes that can not be applied are skipped, and returned as errors.
+func ApplyChanges(src []byte, changes []Change) ([]byte, []error) {
+	var errs []error
+	for _, c := range changes {
+		res, err := Apply(src, c.Object, c.Fix.Patch)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %w", c.Object, c.Fix.Description, err))
+			continue
+		}
+		src = res
+	}
+	return src, errs
+}
+
+// Apply applies the patch to the object in src, and returns the patched source.
+// No changes are made if any of the operations fails.
+func Apply(src []byte, object Object, patch []scorecard.PatchOperation) ([]byte, error) {
+	for _, op := range patch {
+		root, err := findObject(src, object)
+		if err != nil {
+			return nil, err
+		}
+		e := newEditor(src)
+		if err := e.apply(root, op); err != nil {
+			return nil, fmt.Errorf("failed to %s %s: %w", op.Op, op.Path, err)
+		}
+		src = e.bytes()
+	}
+	return src, nil
+}
+
+// Diff returns a unified diff of the changes to the file name
+func Diff(name string, before, after []byte) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(before),
+		B:        splitLines(after),
+		FromFile: "a/" + strings.TrimPrefix(name, "/"),
+		ToFile:   "b/" + strings.TrimPrefix(name, "/"),
+		Context:  3,
+	})
+}
+
+// splitLines splits src into lines that end with a newline
+func splitLines(src []byte) []string {
+	lines := strings.SplitAfter(string(src), "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	lines[len(lines)-1] += "\n"
+	return lines
+}
+
+// findObject returns the root node of the object in src. The object is either a document, or an item of a List.
+func findObject(src []byte, object Object) (*yaml.Node, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(src))
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, errors.New("the object was not found in the file")
+			}
+			return nil, err
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+		if node := matchObject(doc.Content[0], object); node != nil {
+			return node, nil
+		}
+	}
+}
+
+// matchObject returns node if it's the object, or the item of the List in node that is the object
+func matchObject(node *yaml.Node, object Object) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	if items, ok := parser.ListItems(node); ok {
+		for _, item := range items {
+			if res := matchObject(item, object); res != nil {
+				return res
+			}
+		}
+		return nil
+	}
+
+	var meta struct {
+		APIVersion string `yaml:"apiVersion"`
+		Kind       string `yaml:"kind"`
+		Metadata   struct {
+			Name      string `yaml:"name"`
+			Namespace string `yaml:"namespace"`
+		} `yaml:"metadata"`
+	}
+	if err := node.Decode(&meta); err != nil {
+		return nil
+	}
+	if meta.APIVersion == object.APIVersion && meta.Kind == object.Kind &&
+		meta.Metadata.Name == object.Name && meta.Metadata.Namespace == object.Namespace {
+		return node
+	}
+	return nil
+}
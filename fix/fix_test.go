@@ -0,0 +1,272 @@
+package fix
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/parser"
+	"github.com/zegl/kube-score/score"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+var deployment = Object{APIVersion: "apps/v1", Kind: "Deployment", Name: "app"}
+
+const source = `# The service
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app # the name
+spec:
+  template:
+    metadata:
+      labels: {app: app}
+    spec:
+      containers:
+      - name: app
+        image: "app:1.0"
+        imagePullPolicy: IfNotPresent # not always
+        args:
+        - --verbose
+        resources:
+          limits:
+            cpu: 100m
+      - name: sidecar
+        image: sidecar:1.0
+        command: |
+          sleep 10
+          exit 1
+      # The end of the containers
+`
+
+func add(path string, value interface{}) scorecard.PatchOperation {
+	return scorecard.PatchOperation{Op: "add", Path: path, Value: value}
+}
+
+func TestApply(t *testing.T) {
+	tc := []struct {
+		name     string
+		patch    []scorecard.PatchOperation
+		expected string
+	}{
+		{
+			name:  "replace scalar",
+			patch: []scorecard.PatchOperation{add("/spec/template/spec/containers/0/imagePullPolicy", "Always")},
+			expected: `        imagePullPolicy: Always # not always
+`,
+		},
+		{
+			name: "add with missing parents",
+			patch: []scorecard.PatchOperation{
+				add("/spec/template/spec/containers/0/resources/requests/cpu", "100m"),
+				add("/spec/template/spec/containers/0/securityContext/readOnlyRootFilesystem", true),
+			},
+			expected: `        resources:
+          limits:
+            cpu: 100m
+          requests:
+            cpu: 100m
+        securityContext:
+          readOnlyRootFilesystem: true
+      - name: sidecar
+`,
+		},
+		{
+			name:  "add after multi-line string",
+			patch: []scorecard.PatchOperation{add("/spec/template/spec/containers/1/imagePullPolicy", "Always")},
+			expected: `        command: |
+          sleep 10
+          exit 1
+        imagePullPolicy: Always
+      # The end of the containers
+`,
+		},
+		{
+			name:  "add to object",
+			patch: []scorecard.PatchOperation{add("/spec/replicas", 3)},
+			expected: `      # The end of the containers
+  replicas: 3
+`,
+		},
+		{
+			name:     "add to flow style",
+			patch:    []scorecard.PatchOperation{add("/spec/template/metadata/labels/version", "1.0")},
+			expected: `      labels: {app: app, version: "1.0"}`,
+		},
+		{
+			name:  "append to array",
+			patch: []scorecard.PatchOperation{add("/spec/template/spec/containers/0/args/-", "--debug")},
+			expected: `        - --verbose
+        - --debug
+        resources:
+`,
+		},
+		{
+			name: "remove",
+			patch: []scorecard.PatchOperation{
+				{Op: "remove", Path: "/spec/template/spec/containers/0/resources"},
+				{Op: "remove", Path: "/spec/template/spec/containers/1"},
+			},
+			expected: `        - --verbose
+      # The end of the containers
+`,
+		},
+	}
+
+	for _, tc := range tc {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := Apply([]byte(source), deployment, tc.patch)
+			assert.NoError(t, err)
+			assert.Contains(t, string(res), tc.expected)
+			// The other object and the untouched parts are kept as they are
+			assert.Contains(t, string(res), "# The service\napiVersion: v1\nkind: Service\nmetadata:\n  name: app\n---\n")
+			assert.Contains(t, string(res), "  name: app # the name\n")
+		})
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	tc := []struct {
+		object   Object
+		op       scorecard.PatchOperation
+		expected string
+	}{
+		{Object{Kind: "Deployment", Name: "missing"}, add("/spec/replicas", 1), "the object was not found in the file"},
+		{deployment, scorecard.PatchOperation{Op: "move", Path: "/spec"}, `failed to move /spec: the operation "move" is not supported`},
+		{deployment, scorecard.PatchOperation{Op: "replace", Path: "/spec/replicas", Value: 1}, "failed to replace /spec/replicas: the path does not exist"},
+		{deployment, add("/spec/template/spec/containers/5/image", "a"), `failed to add /spec/template/spec/containers/5/image: the index "5" is out of range`},
+		{deployment, add("/kind/a", "a"), "failed to add /kind/a: /kind is not an object or an array"},
+	}
+
+	for _, tc := range tc {
+		_, err := Apply([]byte(source), tc.object, []scorecard.PatchOperation{tc.op})
+		assert.EqualError(t, err, tc.expected)
+	}
+}
+
+func TestApplyListItem(t *testing.T) {
+	src := `apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: app
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: app
+  spec:
+    template:
+      spec:
+        containers:
+        - name: app
+          image: app:1.0
+`
+	res, err := Apply([]byte(src), deployment, []scorecard.PatchOperation{add("/spec/template/spec/containers/0/imagePullPolicy", "Always")})
+	assert.NoError(t, err)
+	assert.Equal(t, src+"          imagePullPolicy: Always\n", string(res))
+}
+
+func TestDiff(t *testing.T) {
+	res, err := Apply([]byte(source), deployment, []scorecard.PatchOperation{add("/spec/template/spec/containers/0/imagePullPolicy", "Always")})
+	assert.NoError(t, err)
+
+	diff, err := Diff("deployment.yaml", []byte(source), res)
+	assert.NoError(t, err)
+	assert.Equal(t, `--- a/deployment.yaml
++++ b/deployment.yaml
+@@ -16,7 +16,7 @@
+       containers:
+       - name: app
+         image: "app:1.0"
+-        imagePullPolicy: IfNotPresent # not always
++        imagePullPolicy: Always # not always
+         args:
+         - --verbose
+         resources:
+`, diff)
+}
+
+type namedReader struct {
+	*strings.Reader
+	name string
+}
+
+func (n namedReader) Name() string {
+	return n.name
+}
+
+func scoreSource(t *testing.T, src string) *scorecard.Scorecard {
+	t.Helper()
+
+	p, err := parser.New(nil)
+	assert.NoError(t, err)
+	allObjects, err := p.ParseFiles([]ks.NamedReader{namedReader{Reader: strings.NewReader(src), name: "objects.yaml"}})
+	assert.NoError(t, err)
+
+	card, err := score.Score(allObjects, score.RegisterAllChecks(allObjects, nil, nil), &config.RunConfiguration{})
+	assert.NoError(t, err)
+	return card
+}
+
+func checkGrade(t *testing.T, card *scorecard.Scorecard, checkID string) scorecard.Grade {
+	t.Helper()
+	for _, so := range *card {
+		for _, c := range so.Checks {
+			if c.Check.ID == checkID {
+				return c.Grade
+			}
+		}
+	}
+	t.Fatalf("check %s was not run", checkID)
+	return 0
+}
+
+func TestChangesResolveChecks(t *testing.T) {
+	src := `apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: job
+spec:
+  schedule: "* * * * *"
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          restartPolicy: Never
+          containers:
+          - name: job
+            image: job:1.0
+            resources:
+              limits:
+                cpu: 100m
+                memory: 128Mi
+`
+
+	card := scoreSource(t, src)
+	checkIDs := []string{"cronjob-has-deadline", "container-image-pull-policy", "container-security-context-readonlyrootfilesystem", "container-resources"}
+	for _, id := range checkIDs {
+		assert.Greater(t, scorecard.GradeAllOK, checkGrade(t, card, id), id)
+	}
+
+	changes := Changes(card)
+	assert.Len(t, changes["objects.yaml"], 5)
+
+	res, errs := ApplyChanges([]byte(src), changes["objects.yaml"])
+	assert.Empty(t, errs)
+
+	card = scoreSource(t, string(res))
+	for _, id := range checkIDs {
+		assert.Equal(t, scorecard.GradeAllOK, checkGrade(t, card, id), id)
+	}
+	assert.Empty(t, Changes(card))
+}
@@ -0,0 +1,51 @@
+package input
+
+import (
+	"io"
+	"os"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+// File is a file that is opened when it's first read, and closed when it has been read to the end. Files are opened
+// lazily, so that scoring a large directory tree does not keep all of its files open at the same time.
+type File struct {
+	path string
+	name string
+	fp   *os.File
+	done bool
+}
+
+var _ ks.NamedReader = (*File)(nil)
+
+// NewFile returns a file that is read from path, and is reported to be the file name
+func NewFile(path, name string) *File {
+	return &File{path: path, name: name}
+}
+
+// Name is the name of the file
+func (f *File) Name() string {
+	return f.name
+}
+
+// Read reads from the file, it's opened on the first read and closed when the end of the file is reached
+func (f *File) Read(p []byte) (int, error) {
+	if f.done {
+		return 0, io.EOF
+	}
+	if f.fp == nil {
+		fp, err := os.Open(f.path)
+		if err != nil {
+			return 0, err
+		}
+		f.fp = fp
+	}
+
+	n, err := f.fp.Read(p)
+	if err != nil {
+		_ = f.fp.Close()
+		f.fp = nil
+		f.done = true
+	}
+	return n, err
+}
@@ -0,0 +1,171 @@
+// Package helm renders Helm charts in-process, without the helm binary or a cluster, and maps each rendered object
+// back to the template that it is rendered from.
+package helm
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v4/pkg/chart/common"
+	commonutil "helm.sh/helm/v4/pkg/chart/common/util"
+	chart "helm.sh/helm/v4/pkg/chart/v2"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/engine"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/input"
+	"github.com/zegl/kube-score/parser"
+)
+
+// Options configures how a chart is rendered
+type Options struct {
+	// ReleaseName is the name of the release, "release-name" if empty, the same as with "helm template"
+	ReleaseName string
+	// Namespace is the namespace of the release, "default" if empty
+	Namespace string
+	// KubernetesVersion is the version in .Capabilities.KubeVersion, the default version of Helm if empty
+	KubernetesVersion string
+	// ValuesFiles are merged in order, values in later files take precedence
+	ValuesFiles []string
+}
+
+var (
+	// documentSeparator matches the "---" lines that separate the documents in a rendered template
+	documentSeparator = regexp.MustCompile(`(?m)^---.*$`)
+)
+
+// Render renders the chart in chartPath, which can be a directory or a packaged chart, and returns the objects in
+// the order of the template files.
+//
+// Objects are named with the path of their template, and the line of the document in the template that the object
+// is rendered from. The line is a best effort, as a template can render a different number of documents than it
+// has, such as when a document is in a range loop.
+func Render(chartPath string, opts Options) ([]ks.NamedReader, error) {
+	chrt, err := loader.Load(chartPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart %s: %w", chartPath, err)
+	}
+
+	values := map[string]any{}
+	for _, f := range opts.ValuesFiles {
+		fp, err := os.Open(f)
+		if err != nil {
+			return nil, err
+		}
+		v, err := loader.LoadValues(fp)
+		_ = fp.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", f, err)
+		}
+		values = loader.MergeMaps(values, v)
+	}
+
+	if err := chartutil.ProcessDependencies(chrt, values); err != nil {
+		return nil, fmt.Errorf("failed to process the dependencies of %s: %w", chartPath, err)
+	}
+
+	caps := common.DefaultCapabilities.Copy()
+	if opts.KubernetesVersion != "" {
+		kubeVersion, err := common.ParseKubeVersion(opts.KubernetesVersion)
+		if err != nil {
+			return nil, err
+		}
+		caps.KubeVersion = *kubeVersion
+	}
+
+	releaseOptions := common.ReleaseOptions{
+		Name:      opts.ReleaseName,
+		Namespace: opts.Namespace,
+		Revision:  1,
+		IsInstall: true,
+	}
+	if releaseOptions.Name == "" {
+		releaseOptions.Name = "release-name"
+	}
+	if releaseOptions.Namespace == "" {
+		releaseOptions.Namespace = "default"
+	}
+
+	renderValues, err := commonutil.ToRenderValues(chrt, values, releaseOptions, caps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render chart %s: %w", chartPath, err)
+	}
+	rendered, err := engine.Render(chrt, renderValues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render chart %s: %w", chartPath, err)
+	}
+
+	templates := make(map[string][]byte)
+	collectTemplates(chrt, templates)
+
+	names := make([]string, 0, len(rendered))
+	for name := range rendered {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var res []ks.NamedReader
+	for _, name := range names {
+		if !isManifest(name) {
+			continue
+		}
+
+		// The names of the templates start with the name of the chart, and not with the path of the chart
+		fileName := filepath.Join(chartPath, filepath.FromSlash(strings.SplitN(name, "/", 2)[1]))
+		lines := parser.NewTemplateLines(templates[name])
+
+		for _, doc := range documentSeparator.Split(rendered[name], -1) {
+			if strings.TrimSpace(doc) == "" {
+				continue
+			}
+			var meta struct {
+				Kind string `yaml:"kind"`
+			}
+			// Documents with only comments are skipped, as with "helm template"
+			if err := yaml.Unmarshal([]byte(doc), &meta); err != nil {
+				return nil, fmt.Errorf("%s: invalid YAML rendered: %w", name, err)
+			}
+			if meta.Kind == "" && isComments(doc) {
+				continue
+			}
+			res = append(res, input.NewRendered([]byte(strings.TrimLeft(doc, "\n")), fileName, lines.Next(meta.Kind)))
+		}
+	}
+	return res, nil
+}
+
+// collectTemplates adds the templates of the chart and its dependencies, by the names used by the engine
+func collectTemplates(chrt *chart.Chart, templates map[string][]byte) {
+	for _, t := range chrt.Templates {
+		templates[path.Join(chrt.ChartFullPath(), t.Name)] = t.Data
+	}
+	for _, dep := range chrt.Dependencies() {
+		collectTemplates(dep, templates)
+	}
+}
+
+// isManifest returns if the template renders Kubernetes objects, and not partials or notes
+func isManifest(name string) bool {
+	base := path.Base(name)
+	if strings.HasPrefix(base, "_") || strings.EqualFold(base, "NOTES.txt") {
+		return false
+	}
+	ext := path.Ext(base)
+	return ext == ".yaml" || ext == ".yml" || ext == ".json"
+}
+
+func isComments(doc string) bool {
+	for _, line := range strings.Split(doc, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+	return true
+}
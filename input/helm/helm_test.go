@@ -0,0 +1,95 @@
+package helm
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+func writeChart(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return dir
+}
+
+func TestRender(t *testing.T) {
+	dir := writeChart(t, map[string]string{
+		"Chart.yaml":              "apiVersion: v2\nname: app\nversion: 0.1.0\n",
+		"values.yaml":             "replicas: 1\nports: [80, 443]\n",
+		"values-prod.yaml":        "replicas: 3\n",
+		"values-canary.yaml":      "replicas: 4\n",
+		"templates/_helpers.tpl":  `{{- define "app.name" -}}{{ .Release.Name }}-app{{- end }}`,
+		"templates/NOTES.txt":     "Installed {{ .Release.Name }}\n",
+		"templates/comments.yaml": "# Only a comment\n",
+		"templates/deployment.yaml": `# The deployment
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ include "app.name" . }}
+  namespace: {{ .Release.Namespace }}
+spec:
+  replicas: {{ .Values.replicas }}
+{{- range .Values.ports }}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ include "app.name" $ }}-{{ . }}
+{{- end }}
+`,
+	})
+
+	objects, err := Render(dir, Options{
+		ReleaseName: "prod",
+		Namespace:   "apps",
+		ValuesFiles: []string{filepath.Join(dir, "values-prod.yaml"), filepath.Join(dir, "values-canary.yaml")},
+	})
+	assert.NoError(t, err)
+
+	type location struct {
+		Name string
+		Line int
+	}
+	var locations []location
+	var contents []string
+	for _, o := range objects {
+		locations = append(locations, location{o.Name(), o.(ks.RenderedReader).Line()})
+		content, err := io.ReadAll(o)
+		assert.NoError(t, err)
+		contents = append(contents, string(content))
+	}
+
+	deployment := filepath.Join(dir, "templates", "deployment.yaml")
+	assert.Equal(t, []location{{deployment, 2}, {deployment, 11}, {deployment, 11}}, locations)
+	assert.Contains(t, contents[0], "name: prod-app\n")
+	assert.Contains(t, contents[0], "namespace: apps\n")
+	// Values files are merged in order
+	assert.Contains(t, contents[0], "replicas: 4\n")
+	assert.Contains(t, contents[2], "name: prod-app-443\n")
+}
+
+func TestRenderErrors(t *testing.T) {
+	_, err := Render(t.TempDir(), Options{})
+	assert.ErrorContains(t, err, "failed to load chart")
+
+	dir := writeChart(t, map[string]string{
+		"Chart.yaml":          "apiVersion: v2\nname: app\nversion: 0.1.0\n",
+		"templates/pod.yaml":  "{{ .Values.missing.field }}\n",
+		"values-invalid.yaml": "- not a map\n",
+	})
+	_, err = Render(dir, Options{})
+	assert.ErrorContains(t, err, "failed to render chart")
+
+	_, err = Render(dir, Options{ValuesFiles: []string{filepath.Join(dir, "values-invalid.yaml")}})
+	assert.ErrorContains(t, err, "failed to parse")
+}
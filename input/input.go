@@ -0,0 +1,357 @@
+// Package input finds the files to score from the paths given on the command line, which can be files,
+// directories that are walked recursively, or glob patterns.
+//
+// Directories with a kustomization file are rendered by the kustomize package instead of being walked.
+package input
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IgnoreFileName is the name of the files that list paths that are not scored, in the gitignore syntax.
+// The patterns in a file are relative to the directory of the file.
+const IgnoreFileName = ".kube-score-ignore"
+
+// Extensions are the extensions of the files that are scored when walking a directory, YAML files and JSON files
+// with one or more JSON documents
+var Extensions = []string{".yaml", ".yml", ".json", ".jsonl", ".ndjson"}
+
+// Options configures how directories are walked
+type Options struct {
+	// Include are patterns of the files to score when walking a directory, all files with one of the Extensions are
+	// scored if empty
+	Include []string
+	// Exclude are patterns of files and directories that are not scored when walking a directory
+	Exclude []string
+	// Kustomize returns directories with a kustomization file as is, instead of walking them
+	Kustomize bool
+}
+
+// KustomizationFileNames are the names of the files that make a directory a kustomization
+var KustomizationFileNames = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+
+// Expand returns the files to score. Directories are walked recursively, and paths that don't exist but
+// contain *, ? or [ are matched as glob patterns, where ** matches any number of directories.
+// Files are returned in the order of paths, and files in directories in lexical order. "-" is returned as is.
+//
+// With opts.Kustomize, directories with a kustomization file are returned instead of the files in them, except for
+// the directories that are resources of another returned kustomization, as their objects are already part of it.
+func Expand(paths []string, opts Options) ([]string, error) {
+	w := &walker{seen: make(map[string]bool), kustomize: opts.Kustomize}
+
+	var err error
+	if w.include, err = compilePatterns("", opts.Include); err != nil {
+		return nil, fmt.Errorf("invalid include pattern: %w", err)
+	}
+	if w.exclude, err = compilePatterns("", opts.Exclude); err != nil {
+		return nil, fmt.Errorf("invalid exclude pattern: %w", err)
+	}
+
+	for _, p := range paths {
+		if p == "-" {
+			w.files = append(w.files, p)
+			continue
+		}
+
+		info, err := os.Stat(p)
+		switch {
+		case err == nil && info.IsDir():
+			w.visited = make(map[string]bool)
+			if err := w.walk(p, "", nil, nil); err != nil {
+				return nil, err
+			}
+		case err == nil:
+			w.add(p)
+		case errors.Is(err, os.ErrNotExist) && isGlob(p):
+			if err := w.glob(p); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, err
+		}
+	}
+
+	if opts.Kustomize {
+		return w.withoutReferencedKustomizations()
+	}
+	return w.files, nil
+}
+
+// Walked returns if the file at rel, a slash separated path relative to a directory, is scored when the directory is
+// walked with opts. The ignore files in the directory are not read, so that it works for files that don't exist.
+func Walked(rel string, opts Options) (bool, error) {
+	include, err := compilePatterns("", opts.Include)
+	if err != nil {
+		return false, fmt.Errorf("invalid include pattern: %w", err)
+	}
+	exclude, err := compilePatterns("", opts.Exclude)
+	if err != nil {
+		return false, fmt.Errorf("invalid exclude pattern: %w", err)
+	}
+
+	parts := strings.Split(rel, "/")
+	for i, part := range parts {
+		if part == ".git" || part == IgnoreFileName {
+			return false, nil
+		}
+		isDir := i < len(parts)-1
+		if matched, negated := exclude.match(strings.Join(parts[:i+1], "/"), isDir); matched && !negated {
+			return false, nil
+		}
+	}
+	if !hasExtension(rel) {
+		return false, nil
+	}
+	if len(include.patterns) > 0 {
+		if matched, negated := include.match(rel, false); !matched || negated {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// IsKustomization returns if dir is a directory with a kustomization file
+func IsKustomization(dir string) bool {
+	_, ok := KustomizationFile(dir)
+	return ok
+}
+
+// KustomizationFile returns the path of the kustomization file in dir
+func KustomizationFile(dir string) (string, bool) {
+	for _, name := range KustomizationFileNames {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+type walker struct {
+	include, exclude *patternList
+	kustomize        bool
+	files            []string
+	// seen are the absolute paths of all files that have been added
+	seen map[string]bool
+	// visited are the real paths of the directories in the current walk, used to protect from symlink loops
+	visited map[string]bool
+}
+
+func (w *walker) add(path string) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	if w.seen[abs] {
+		return
+	}
+	w.seen[abs] = true
+	w.files = append(w.files, path)
+}
+
+// walk walks the directory dir recursively, rel is the slash separated path of dir relative to the root of the walk.
+// Files are added if they match glob, or are accepted by the include patterns and extensions if glob is nil.
+func (w *walker) walk(dir, rel string, ignores []*patternList, glob *patternList) error {
+	real, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	if w.visited[real] {
+		return nil
+	}
+	w.visited[real] = true
+
+	if w.kustomize && glob == nil && IsKustomization(dir) {
+		w.add(dir)
+		return nil
+	}
+
+	if fp, err := os.Open(filepath.Join(dir, IgnoreFileName)); err == nil {
+		ignore, err := parseIgnoreFile(rel, fp)
+		_ = fp.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", filepath.Join(dir, IgnoreFileName), err)
+		}
+		ignores = append(ignores[:len(ignores):len(ignores)], ignore)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if e.Name() == ".git" || e.Name() == IgnoreFileName {
+			continue
+		}
+
+		path := filepath.Join(dir, e.Name())
+		entryRel := e.Name()
+		if rel != "" {
+			entryRel = rel + "/" + e.Name()
+		}
+
+		// Follows symlinks, broken symlinks are skipped
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		isDir := info.IsDir()
+
+		if isIgnored(ignores, entryRel, isDir) {
+			continue
+		}
+		if matched, negated := w.exclude.match(entryRel, isDir); matched && !negated {
+			continue
+		}
+
+		if isDir {
+			if err := w.walk(path, entryRel, ignores, glob); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if glob != nil {
+			if matched, _ := glob.match(entryRel, false); matched {
+				w.add(path)
+			}
+			continue
+		}
+
+		if !hasExtension(e.Name()) {
+			continue
+		}
+		if len(w.include.patterns) > 0 {
+			if matched, negated := w.include.match(entryRel, false); !matched || negated {
+				continue
+			}
+		}
+		w.add(path)
+	}
+
+	return nil
+}
+
+// glob adds the files that match the glob pattern
+func (w *walker) glob(glob string) error {
+	// The directory to walk is the part of the pattern before the first segment with a wildcard
+	segments := strings.Split(filepath.ToSlash(glob), "/")
+	root := ""
+	for len(segments) > 1 && !isGlob(segments[0]) {
+		root += segments[0] + "/"
+		segments = segments[1:]
+	}
+	if root == "" {
+		root = "."
+	}
+
+	// The pattern is anchored to the root of the walk
+	compiled, err := compilePattern("/" + strings.Join(segments, "/"))
+	if err != nil {
+		return err
+	}
+
+	before := len(w.files)
+	w.visited = make(map[string]bool)
+	if err := w.walk(filepath.FromSlash(root), "", nil, &patternList{patterns: []pattern{compiled}}); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("no files match %s", glob)
+		}
+		return err
+	}
+	if len(w.files) == before {
+		return fmt.Errorf("no files match %s", glob)
+	}
+	return nil
+}
+
+// withoutReferencedKustomizations returns the files, without the kustomization directories that are referenced as
+// resources or components by another kustomization in the files
+func (w *walker) withoutReferencedKustomizations() ([]string, error) {
+	referenced := make(map[string]bool)
+	for _, f := range w.files {
+		kustomization, ok := KustomizationFile(f)
+		if !ok {
+			continue
+		}
+		refs, err := kustomizationReferences(kustomization)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", kustomization, err)
+		}
+		for _, ref := range refs {
+			referenced[ref] = true
+		}
+	}
+
+	res := make([]string, 0, len(w.files))
+	for _, f := range w.files {
+		if abs, err := filepath.Abs(f); err == nil && referenced[abs] && IsKustomization(f) {
+			continue
+		}
+		res = append(res, f)
+	}
+	return res, nil
+}
+
+// kustomizationReferences returns the absolute paths of the local resources, bases and components of a kustomization
+func kustomizationReferences(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var kustomization struct {
+		Resources  []string `yaml:"resources"`
+		Bases      []string `yaml:"bases"`
+		Components []string `yaml:"components"`
+	}
+	if err := yaml.Unmarshal(content, &kustomization); err != nil {
+		return nil, err
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	var refs []string
+	for _, list := range [][]string{kustomization.Resources, kustomization.Bases, kustomization.Components} {
+		for _, ref := range list {
+			if strings.Contains(ref, "://") {
+				continue
+			}
+			refs = append(refs, filepath.Join(dir, filepath.FromSlash(ref)))
+		}
+	}
+	return refs, nil
+}
+
+func isIgnored(ignores []*patternList, rel string, isDir bool) bool {
+	ignored := false
+	// Files in subdirectories take precedence over the files in the parent directories
+	for _, ignore := range ignores {
+		if matched, negated := ignore.match(rel, isDir); matched {
+			ignored = !negated
+		}
+	}
+	return ignored
+}
+
+func isGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+func hasExtension(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, e := range Extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
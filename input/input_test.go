@@ -0,0 +1,176 @@
+package input
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tree creates the files in a temporary directory, and returns the directory
+func tree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return dir
+}
+
+func rel(t *testing.T, dir string, files []string) []string {
+	t.Helper()
+	res := make([]string, 0, len(files))
+	for _, f := range files {
+		r, err := filepath.Rel(dir, f)
+		assert.NoError(t, err)
+		res = append(res, filepath.ToSlash(r))
+	}
+	return res
+}
+
+func TestExpandDirectory(t *testing.T) {
+	dir := tree(t, map[string]string{
+		"a.yaml":                     "",
+		"README.md":                  "",
+		"b/c.yml":                    "",
+		"b/d.json":                   "",
+		"b/generated/e.yaml":         "",
+		"b/tmp/f.yaml":               "",
+		"b/.kube-score-ignore":       "# comment\ngenerated/\n*.tmp.yaml\n",
+		"b/x.tmp.yaml":               "",
+		"g/h.yaml":                   "",
+		"g/i.yaml":                   "",
+		"g/.kube-score-ignore":       "*.yaml\n!h.yaml\n",
+		"test/fixtures/j.yaml":       "",
+		".kube-score-ignore":         "/test/\n",
+		"nested/deep/test/k.yaml":    "",
+		"nested/deep/values.yaml":    "",
+		"nested/deep/templates.yaml": "",
+	})
+
+	files, err := Expand([]string{dir}, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"a.yaml",
+		"b/c.yml",
+		"b/d.json",
+		"b/tmp/f.yaml",
+		"g/h.yaml",
+		"nested/deep/templates.yaml",
+		"nested/deep/test/k.yaml",
+		"nested/deep/values.yaml",
+	}, rel(t, dir, files))
+
+	files, err = Expand([]string{dir}, Options{Include: []string{"nested/**"}, Exclude: []string{"values.yaml", "test/"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"nested/deep/templates.yaml"}, rel(t, dir, files))
+}
+
+func TestExpandGlob(t *testing.T) {
+	dir := tree(t, map[string]string{
+		"a/b.yaml":             "",
+		"a/c/d.yaml":           "",
+		"a/c/e.txt":            "",
+		"a/c/f/g.yaml":         "",
+		"x/.kube-score-ignore": "g.yaml\n",
+		"x/g.yaml":             "",
+	})
+
+	files, err := Expand([]string{filepath.Join(dir, "a", "**", "*.yaml")}, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a/b.yaml", "a/c/d.yaml", "a/c/f/g.yaml"}, rel(t, dir, files))
+
+	files, err = Expand([]string{filepath.Join(dir, "a", "*", "*.txt"), filepath.Join(dir, "a", "b.yaml")}, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a/c/e.txt", "a/b.yaml"}, rel(t, dir, files))
+
+	_, err = Expand([]string{filepath.Join(dir, "x", "*.yaml")}, Options{})
+	assert.ErrorContains(t, err, "no files match")
+
+	_, err = Expand([]string{filepath.Join(dir, "missing.yaml")}, Options{})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestExpandSymlinkLoop(t *testing.T) {
+	dir := tree(t, map[string]string{
+		"a/b.yaml": "",
+	})
+	if err := os.Symlink(dir, filepath.Join(dir, "a", "loop")); err != nil {
+		t.Skip("symlinks are not supported")
+	}
+	assert.NoError(t, os.Symlink(filepath.Join(dir, "missing"), filepath.Join(dir, "a", "broken.yaml")))
+
+	files, err := Expand([]string{dir, filepath.Join(dir, "a", "b.yaml")}, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a/b.yaml"}, rel(t, dir, files))
+}
+
+func TestExpandStdin(t *testing.T) {
+	files, err := Expand([]string{"-"}, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"-"}, files)
+}
+
+func TestExpandKustomize(t *testing.T) {
+	dir := tree(t, map[string]string{
+		"base/kustomization.yaml":          "resources:\n- deployment.yaml\n",
+		"base/deployment.yaml":             "",
+		"overlays/prod/kustomization.yaml": "resources:\n- ../../base\n- https://example.com/remote.yaml\n",
+		"overlays/dev/Kustomization":       "resources:\n- ../../base\n",
+		"plain/service.yaml":               "",
+	})
+
+	files, err := Expand([]string{dir}, Options{Kustomize: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"overlays/dev", "overlays/prod", "plain/service.yaml"}, rel(t, dir, files))
+
+	// Kustomizations that are given explicitly are rendered on their own
+	files, err = Expand([]string{filepath.Join(dir, "base")}, Options{Kustomize: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"base"}, rel(t, dir, files))
+
+	files, err = Expand([]string{filepath.Join(dir, "base")}, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"base/deployment.yaml", "base/kustomization.yaml"}, rel(t, dir, files))
+}
+
+func TestWalked(t *testing.T) {
+	opts := Options{Include: []string{"*.yaml"}, Exclude: []string{"excluded/"}}
+	tc := map[string]bool{
+		"deployment.yaml":          true,
+		"sub/service.yaml":         true,
+		"deployment.json":          false,
+		"README.md":                false,
+		"excluded/deployment.yaml": false,
+		".git/config.yaml":         false,
+	}
+	for rel, expected := range tc {
+		walked, err := Walked(rel, opts)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, walked, rel)
+	}
+
+	walked, err := Walked("deployment.json", Options{})
+	assert.NoError(t, err)
+	assert.True(t, walked)
+}
+
+func TestFile(t *testing.T) {
+	dir := tree(t, map[string]string{"a.yaml": "kind: Pod\n"})
+
+	f := NewFile(filepath.Join(dir, "a.yaml"), "a.yaml")
+	assert.Equal(t, "a.yaml", f.Name())
+	assert.Nil(t, f.fp, "the file is opened when it's read")
+
+	content, err := io.ReadAll(f)
+	assert.NoError(t, err)
+	assert.Equal(t, "kind: Pod\n", string(content))
+	assert.Nil(t, f.fp, "the file is closed when it has been read")
+
+	_, err = io.ReadAll(NewFile(filepath.Join(dir, "missing.yaml"), "missing.yaml"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,210 @@
+// Package kustomize renders kustomizations in-process, and maps each rendered object back to the file and line of
+// the resource that it originates from.
+package kustomize
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/input"
+	"github.com/zegl/kube-score/parser"
+)
+
+const originAnnotation = "config.kubernetes.io/origin"
+
+// Render renders the kustomization in dir, and returns the objects in the order that they are rendered.
+//
+// Objects that originate from a local resource are named with the path of the resource file, and the line where the
+// resource starts, even if the kustomization changes its name. Generated objects are named with the kustomization
+// file that configures the generator, and objects from remote resources with the kustomization file in dir.
+func Render(dir string) ([]ks.NamedReader, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	kustomizationFile, ok := input.KustomizationFile(root)
+	if !ok {
+		return nil, fmt.Errorf("%s: no kustomization file found", dir)
+	}
+
+	fSys := &originFileSystem{FileSystem: filesys.MakeFsOnDisk(), kustomizationFile: kustomizationFile}
+	resMap, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fSys, root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render kustomization %s: %w", dir, err)
+	}
+
+	sources := make(map[string]*sourceFile)
+	var res []ks.NamedReader
+	for _, r := range resMap.Resources() {
+		origin, err := r.GetOrigin()
+		if err != nil {
+			return nil, fmt.Errorf("failed to render kustomization %s: %w", dir, err)
+		}
+		if fSys.injected {
+			annotations := r.GetAnnotations()
+			delete(annotations, originAnnotation)
+			if err := r.SetAnnotations(annotations); err != nil {
+				return nil, err
+			}
+		}
+
+		name, line := kustomizationFile, 1
+		switch {
+		case origin == nil || origin.Repo != "":
+		case origin.ConfiguredIn != "":
+			name = filepath.Join(root, filepath.FromSlash(origin.ConfiguredIn))
+			line = sourceFor(sources, name).generatorLine(r.GetName())
+		case origin.Path != "":
+			name = filepath.Join(root, filepath.FromSlash(origin.Path))
+			line = sourceFor(sources, name).objectLine(r)
+		}
+
+		content, err := r.AsYAML()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, input.NewRendered(content, name, line))
+	}
+	return res, nil
+}
+
+// originFileSystem adds the originAnnotations build option to the kustomization file that is rendered, so that
+// kustomize annotates each object with the file that it originates from
+type originFileSystem struct {
+	filesys.FileSystem
+	kustomizationFile string
+	// injected is true if the option was not set by the kustomization file itself
+	injected bool
+}
+
+func (f *originFileSystem) ReadFile(path string) ([]byte, error) {
+	content, err := f.FileSystem.ReadFile(path)
+	if err != nil || path != f.kustomizationFile {
+		return content, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		// Let kustomize report invalid kustomization files
+		return content, nil
+	}
+
+	m := doc.Content[0]
+	var buildMetadata *yaml.Node
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == "buildMetadata" {
+			buildMetadata = m.Content[i+1]
+		}
+	}
+	if buildMetadata == nil {
+		buildMetadata = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "buildMetadata"}, buildMetadata)
+	}
+	if buildMetadata.Kind != yaml.SequenceNode {
+		return content, nil
+	}
+	for _, option := range buildMetadata.Content {
+		if option.Value == types.OriginAnnotations {
+			return content, nil
+		}
+	}
+	buildMetadata.Content = append(buildMetadata.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: types.OriginAnnotations})
+	f.injected = true
+
+	return yaml.Marshal(&doc)
+}
+
+// sourceFile is a file that rendered objects originate from
+type sourceFile struct {
+	documents []document
+	root      yaml.Node
+}
+
+// document is an object in a source file
+type document struct {
+	line int
+	kind string
+	name string
+}
+
+func sourceFor(sources map[string]*sourceFile, path string) *sourceFile {
+	if s, ok := sources[path]; ok {
+		return s
+	}
+	s := &sourceFile{}
+	sources[path] = s
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return s
+	}
+	_ = yaml.Unmarshal(content, &s.root)
+
+	// The documents are read in the same way as by the parser, so that the lines are the same as if the file was
+	// scored on its own
+	docs, err := parser.ReadDocuments(bytes.NewReader(content))
+	if err != nil {
+		return s
+	}
+	for _, doc := range docs {
+		var meta struct {
+			Kind     string `yaml:"kind"`
+			Metadata struct {
+				Name string `yaml:"name"`
+			} `yaml:"metadata"`
+		}
+		if doc.Node.Decode(&meta) == nil {
+			s.documents = append(s.documents, document{line: doc.Line, kind: meta.Kind, name: meta.Metadata.Name})
+		}
+	}
+	return s
+}
+
+// objectLine returns the line of the document that the object originates from. The kind must match, and as
+// kustomize can add a prefix and a suffix to the name, the document with the longest name that is a part of the
+// name of the object is used.
+func (s *sourceFile) objectLine(r *resource.Resource) int {
+	line, best := 1, -1
+	for _, d := range s.documents {
+		if d.kind != r.GetKind() || !strings.Contains(r.GetName(), d.name) {
+			continue
+		}
+		if len(d.name) > best {
+			line, best = d.line, len(d.name)
+		}
+	}
+	return line
+}
+
+// generatorLine returns the line of the generator of an object with the name. Generators are matched on the
+// longest name that is a part of the name of the object, as kustomize can add a prefix and a hash suffix to it.
+func (s *sourceFile) generatorLine(name string) int {
+	line, best := 1, -1
+	var visit func(n *yaml.Node)
+	visit = func(n *yaml.Node) {
+		if n.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				key, value := n.Content[i], n.Content[i+1]
+				if key.Value == "name" && value.Kind == yaml.ScalarNode && value.Value != "" &&
+					strings.Contains(name, value.Value) && len(value.Value) > best {
+					line, best = key.Line, len(value.Value)
+				}
+			}
+		}
+		for _, c := range n.Content {
+			visit(c)
+		}
+	}
+	visit(&s.root)
+	return line
+}
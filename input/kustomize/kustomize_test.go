@@ -0,0 +1,115 @@
+package kustomize
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+func tree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return dir
+}
+
+func TestRender(t *testing.T) {
+	dir := tree(t, map[string]string{
+		"base/kustomization.yaml": "resources:\n- deployment.yaml\n- services.yaml\n",
+		"base/deployment.yaml": `# The app
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: app:1.0
+`,
+		"base/services.yaml": `apiVersion: v1
+kind: Service
+metadata:
+  name: app
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: app-internal
+`,
+		"overlay/kustomization.yaml": `namePrefix: prod-
+namespace: prod
+resources:
+- ../base
+patches:
+- path: replicas.yaml
+configMapGenerator:
+- name: config
+  literals:
+  - key=value
+`,
+		"overlay/replicas.yaml": "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: app\nspec:\n  replicas: 3\n",
+	})
+
+	objects, err := Render(filepath.Join(dir, "overlay"))
+	assert.NoError(t, err)
+
+	type location struct {
+		Name string
+		Line int
+	}
+	var locations []location
+	for _, o := range objects {
+		rel, err := filepath.Rel(dir, o.Name())
+		assert.NoError(t, err)
+		locations = append(locations, location{filepath.ToSlash(rel), o.(ks.RenderedReader).Line()})
+	}
+	assert.Equal(t, []location{
+		{"base/deployment.yaml", 2},
+		{"base/services.yaml", 1},
+		{"base/services.yaml", 6},
+		{"overlay/kustomization.yaml", 8},
+	}, locations)
+
+	// The objects are rendered, and the origin annotations are removed
+	content, err := io.ReadAll(objects[0])
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "name: prod-app\n")
+	assert.Contains(t, string(content), "replicas: 3\n")
+	assert.NotContains(t, string(content), "config.kubernetes.io/origin")
+}
+
+func TestRenderKeepsOriginAnnotations(t *testing.T) {
+	dir := tree(t, map[string]string{
+		"kustomization.yaml": "resources:\n- service.yaml\nbuildMetadata: [originAnnotations]\n",
+		"service.yaml":       "apiVersion: v1\nkind: Service\nmetadata:\n  name: app\n",
+	})
+
+	objects, err := Render(dir)
+	assert.NoError(t, err)
+	assert.Len(t, objects, 1)
+	content, err := io.ReadAll(objects[0])
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "config.kubernetes.io/origin")
+}
+
+func TestRenderError(t *testing.T) {
+	dir := tree(t, map[string]string{
+		"kustomization.yaml": "resources:\n- missing.yaml\n",
+	})
+	_, err := Render(dir)
+	assert.ErrorContains(t, err, "failed to render kustomization")
+
+	_, err = Render(t.TempDir())
+	assert.ErrorContains(t, err, "no kustomization file found")
+}
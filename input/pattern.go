@@ -0,0 +1,142 @@
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// pattern is a compiled pattern in the gitignore syntax
+type pattern struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// patternList is a list of patterns, with paths relative to base
+type patternList struct {
+	base     string
+	patterns []pattern
+}
+
+// compilePattern compiles a pattern in the gitignore syntax. Patterns without a slash match the name of a file or
+// directory at any level, other patterns are relative to the base of the list.
+func compilePattern(p string) (pattern, error) {
+	var res pattern
+	if strings.HasPrefix(p, "!") {
+		res.negate = true
+		p = p[1:]
+	}
+	if strings.HasSuffix(p, "/") {
+		res.dirOnly = true
+		p = strings.TrimRight(p, "/")
+	}
+	if !strings.Contains(p, "/") {
+		p = "**/" + p
+	}
+	p = strings.TrimPrefix(p, "/")
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "/**") && i+3 == len(p):
+			sb.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(p[i+1:], ']')
+			if end < 0 {
+				return res, fmt.Errorf("invalid pattern %q: unterminated [", p)
+			}
+			class := p[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(p):
+			i++
+			sb.WriteString(regexp.QuoteMeta(string(p[i])))
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return res, fmt.Errorf("invalid pattern %q: %w", p, err)
+	}
+	res.re = re
+	return res, nil
+}
+
+// compilePatterns compiles a list of patterns, relative to base
+func compilePatterns(base string, patterns []string) (*patternList, error) {
+	list := &patternList{base: base}
+	for _, p := range patterns {
+		compiled, err := compilePattern(p)
+		if err != nil {
+			return nil, err
+		}
+		list.patterns = append(list.patterns, compiled)
+	}
+	return list, nil
+}
+
+// parseIgnoreFile parses a file in the gitignore syntax
+func parseIgnoreFile(base string, r io.Reader) (*patternList, error) {
+	var patterns []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// A leading \ escapes # and !
+		if strings.HasPrefix(line, `\#`) || strings.HasPrefix(line, `\!`) {
+			line = line[1:]
+		}
+		patterns = append(patterns, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return compilePatterns(base, patterns)
+}
+
+// match returns if any pattern matches the path, and if the last matching pattern is negated.
+// rel is the slash separated path relative to the root of the walk.
+func (l *patternList) match(rel string, isDir bool) (matched, negated bool) {
+	if l.base != "" {
+		if !strings.HasPrefix(rel, l.base+"/") {
+			return false, false
+		}
+		rel = strings.TrimPrefix(rel, l.base+"/")
+	}
+	rel = path.Clean(rel)
+
+	for _, p := range l.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		if p.re.MatchString(rel) {
+			matched, negated = true, p.negate
+		}
+	}
+	return matched, negated
+}
@@ -0,0 +1,31 @@
+package input
+
+import (
+	"bytes"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+// Rendered is a single object that is rendered from a file, such as by kustomize or Helm
+type Rendered struct {
+	*bytes.Reader
+	name string
+	line int
+}
+
+var _ ks.RenderedReader = Rendered{}
+
+// NewRendered returns a rendered object, that is reported to be in the file name at the line
+func NewRendered(content []byte, name string, line int) Rendered {
+	return Rendered{Reader: bytes.NewReader(content), name: name, line: line}
+}
+
+// Name is the path of the file that the object is rendered from
+func (r Rendered) Name() string {
+	return r.name
+}
+
+// Line is the line in the file that the object is rendered from
+func (r Rendered) Line() int {
+	return r.line
+}
@@ -0,0 +1,428 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+// Document is a YAML document in a file
+type Document struct {
+	// Node is the root node of the document
+	Node *yaml.Node
+	// Line is the line where the content of the document starts, after any comments
+	Line int
+	// Comments are the comments before the content of the document, one per line
+	Comments []string
+}
+
+// HelmTemplate returns the name of the template that the document is rendered from by Helm, from its
+// "# Source: " comment. False is returned if the document is not rendered by Helm.
+func (d Document) HelmTemplate() (string, bool) {
+	const helmTemplatePrefix = "# Source: "
+	for _, comment := range d.Comments {
+		if strings.HasPrefix(comment, helmTemplatePrefix) {
+			return comment[len(helmTemplatePrefix):], true
+		}
+	}
+	return "", false
+}
+
+// DocumentError is a document that could not be read. Line is the line of the error if it is known, and the first
+// line of the document otherwise.
+type DocumentError struct {
+	Line int
+	Err  error
+}
+
+func (e *DocumentError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+func (e *DocumentError) Unwrap() error {
+	return e.Err
+}
+
+// ReadDocuments reads all documents from r. Empty documents, and documents with only comments, are skipped.
+// Documents are separated by "---", which can be followed by a comment.
+//
+// Files that start with "{" or "[" are read as a stream of JSON values, such as a single JSON document, newline
+// delimited JSON, or the output of "kubectl get -o json". The items of JSON arrays are read as separate documents, and
+// JSON values that are not objects are skipped.
+func ReadDocuments(r io.Reader) ([]Document, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	docs, errs := decodeDocuments(content, false)
+	if len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return docs, nil
+}
+
+// ReadDocumentsTolerant reads all documents from r like ReadDocuments, but a document that can not be read does not
+// stop the reading of the other documents. The documents that could not be read are returned as errors, and err is
+// only set if r could not be read.
+//
+// A JSON stream continues at the next line that starts with "{" or "[" after an error, such as the next line of
+// newline delimited JSON.
+func ReadDocumentsTolerant(r io.Reader) (docs []Document, docErrs []*DocumentError, err error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	docs, docErrs = decodeDocuments(content, true)
+	return docs, docErrs, nil
+}
+
+func decodeDocuments(content []byte, tolerant bool) ([]Document, []*DocumentError) {
+	if isJSON(content) {
+		docs, errs := readJSONDocuments(content, tolerant)
+		if len(errs) == 0 {
+			return docs, nil
+		}
+		// A YAML flow mapping also starts with "{"
+		if yamlDocs, yamlErr := readYAMLDocuments(content, 0); yamlErr == nil {
+			return yamlDocs, nil
+		}
+		return docs, errs
+	}
+
+	docs, err := readYAMLDocuments(content, 0)
+	if err == nil {
+		return docs, nil
+	}
+	if !tolerant {
+		return nil, []*DocumentError{err}
+	}
+
+	// The YAML decoder can not continue after an error, read the documents one at a time so that an error only affects
+	// the document that it is in
+	docs = nil
+	var errs []*DocumentError
+	for _, part := range splitYAMLDocuments(content) {
+		partDocs, err := readYAMLDocuments(part.content, part.line-1)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		docs = append(docs, partDocs...)
+	}
+	return docs, errs
+}
+
+var (
+	yamlDocumentSeparator = regexp.MustCompile(`^---(\s|$)`)
+	yamlErrorLine         = regexp.MustCompile(`^yaml: line (\d+): `)
+)
+
+type yamlDocumentPart struct {
+	content []byte
+	// line is the line in the file of the first line of the content
+	line int
+}
+
+// splitYAMLDocuments splits content at the "---" document separators. Each part starts with its separator.
+func splitYAMLDocuments(content []byte) []yamlDocumentPart {
+	var parts []yamlDocumentPart
+	lines := bytes.SplitAfter(content, []byte("\n"))
+	part := yamlDocumentPart{line: 1}
+	for i, line := range lines {
+		if yamlDocumentSeparator.Match(line) && i > 0 {
+			parts = append(parts, part)
+			part = yamlDocumentPart{line: i + 1}
+		}
+		part.content = append(part.content, line...)
+	}
+	return append(parts, part)
+}
+
+// readYAMLDocuments reads the YAML documents in content, which starts at line lineOffset+1 of the file
+func readYAMLDocuments(content []byte, lineOffset int) ([]Document, *DocumentError) {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	var docs []Document
+	for {
+		var doc yaml.Node
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			// The line of a syntax error is a part of the message of the yaml package
+			docErr := &DocumentError{Line: lineOffset + 1, Err: err}
+			if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+				line, _ := strconv.Atoi(m[1])
+				docErr = &DocumentError{Line: lineOffset + line, Err: errors.New(strings.TrimPrefix(err.Error(), m[0]))}
+			}
+			return docs, docErr
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+		root := doc.Content[0]
+		if isNull(root) {
+			continue
+		}
+		if lineOffset > 0 {
+			shiftNode(root, lineOffset, 0)
+		}
+
+		comments := splitComments(doc.HeadComment)
+		comments = append(comments, splitComments(root.HeadComment)...)
+		if root.Kind == yaml.MappingNode && len(root.Content) > 0 {
+			comments = append(comments, splitComments(root.Content[0].HeadComment)...)
+		}
+
+		docs = append(docs, Document{Node: root, Line: root.Line, Comments: comments})
+	}
+}
+
+// isJSON returns true if the first character that is not whitespace is the start of a JSON object or array
+func isJSON(content []byte) bool {
+	content = bytes.TrimLeft(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf")), " \t\r\n")
+	return len(content) > 0 && (content[0] == '{' || content[0] == '[')
+}
+
+// jsonValueStart matches the lines that start with a JSON object or array
+var jsonValueStart = regexp.MustCompile(`(?m)^[{\[]`)
+
+// readJSONDocuments reads a stream of JSON values. The lines and columns of the nodes are the positions in content.
+// If tolerant is true, reading continues at the next line that starts a JSON value after an error.
+func readJSONDocuments(content []byte, tolerant bool) ([]Document, []*DocumentError) {
+	var docs []Document
+	var errs []*DocumentError
+	start := 0
+	for {
+		streamDocs, errOffset, err := readJSONStream(content, start)
+		docs = append(docs, streamDocs...)
+		if err == nil {
+			return docs, errs
+		}
+		errs = append(errs, err)
+		if !tolerant || errOffset >= len(content) {
+			return docs, errs
+		}
+
+		// The character before the offset is the one that caused the error, which can be the start of the next value
+		from := max(errOffset-1, start)
+		loc := jsonValueStart.FindIndex(content[from:])
+		if loc == nil || from+loc[0] <= start {
+			return docs, errs
+		}
+		start = from + loc[0]
+	}
+}
+
+// readJSONStream reads the JSON values in content from start. If a value could not be read, the offset in content
+// where reading stopped is returned with the error.
+func readJSONStream(content []byte, start int) ([]Document, int, *DocumentError) {
+	decoder := json.NewDecoder(bytes.NewReader(content[start:]))
+	var docs []Document
+	for {
+		// The start of the next value, used as the line of errors without a position
+		next := start + int(decoder.InputOffset())
+		next += len(content[next:]) - len(bytes.TrimLeft(content[next:], " \t\r\n"))
+
+		var raw json.RawMessage
+		err := decoder.Decode(&raw)
+		if errors.Is(err, io.EOF) {
+			return docs, 0, nil
+		}
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			offset := min(start+int(syntaxErr.Offset), len(content))
+			line, _ := offsetPosition(content, offset)
+			return docs, offset, &DocumentError{Line: line, Err: fmt.Errorf("invalid JSON: %w", err)}
+		}
+		if err != nil {
+			line, _ := offsetPosition(content, next)
+			return docs, len(content), &DocumentError{Line: line, Err: fmt.Errorf("invalid JSON: %w", err)}
+		}
+
+		// The value ends at the offset of the decoder, which may be followed by a newline
+		end := start + int(decoder.InputOffset())
+		line, column := offsetPosition(content, end-len(raw))
+
+		var doc yaml.Node
+		if err := yaml.Unmarshal(raw, &doc); err != nil {
+			return docs, end, &DocumentError{Line: line, Err: fmt.Errorf("invalid JSON: %w", err)}
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+		root := doc.Content[0]
+		shiftNode(root, line-1, column-1)
+
+		nodes := []*yaml.Node{root}
+		if root.Kind == yaml.SequenceNode {
+			nodes = root.Content
+		}
+		// Only objects can be Kubernetes objects, other values such as the items of arrays of strings are skipped
+		for _, node := range nodes {
+			if node.Kind == yaml.MappingNode {
+				docs = append(docs, Document{Node: node, Line: node.Line})
+			}
+		}
+	}
+}
+
+// offsetPosition returns the line and column of the byte offset in content
+func offsetPosition(content []byte, offset int) (line, column int) {
+	before := content[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	column = utf8.RuneCount(before[bytes.LastIndexByte(before, '\n')+1:]) + 1
+	return line, column
+}
+
+// shiftNode moves the node and its children by lines, and by columns on its first line
+func shiftNode(node *yaml.Node, lines, columns int) {
+	if node.Line == 1 {
+		node.Column += columns
+	}
+	node.Line += lines
+	for _, c := range node.Content {
+		shiftNode(c, lines, columns)
+	}
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+func splitComments(comment string) []string {
+	if comment == "" {
+		return nil
+	}
+	var res []string
+	for _, line := range strings.Split(comment, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			res = append(res, line)
+		}
+	}
+	return res
+}
+
+// ListItems returns the items of a List document, and false if the node is not a List. Lists of a kind, such as a
+// DeploymentList from the Kubernetes API, are also Lists.
+func ListItems(node *yaml.Node) ([]*yaml.Node, bool) {
+	if node.Kind != yaml.MappingNode {
+		return nil, false
+	}
+	var apiVersion, kind string
+	var items *yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		switch node.Content[i].Value {
+		case "apiVersion":
+			apiVersion = node.Content[i+1].Value
+		case "kind":
+			kind = node.Content[i+1].Value
+		case "items":
+			items = node.Content[i+1]
+		}
+	}
+	isList := apiVersion == "v1" && kind == "List"
+	isKindList := strings.HasSuffix(kind, "List") && kind != "List" && items != nil
+	if !isList && !isKindList {
+		return nil, false
+	}
+	if items == nil || items.Kind != yaml.SequenceNode {
+		return nil, true
+	}
+	return items.Content, true
+}
+
+var (
+	templateKindLine = regexp.MustCompile(`^kind:\s*["']?([^"'\s#]+)["']?\s*(#.*)?$`)
+	templateAction   = regexp.MustCompile(`^\{\{.*\}\}$`)
+)
+
+// TemplateLines finds the lines in a Helm template that rendered documents are rendered from
+type TemplateLines struct {
+	// kinds are the start lines of the documents in the template by their kind, for kinds that are not templated
+	kinds map[string][]int
+	// used are the number of rendered documents of each kind
+	used map[string]int
+}
+
+// NewTemplateLines returns the lines of the documents in the template. The start line of a document is the first
+// line after the "---" separator that is not empty, a comment, or only a template action, the same as the Line
+// of a Document that is read from a file.
+func NewTemplateLines(template []byte) *TemplateLines {
+	t := &TemplateLines{kinds: make(map[string][]int), used: make(map[string]int)}
+
+	docStart := 0
+	for i, line := range strings.Split(string(template), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(line, "---") {
+			docStart = 0
+			continue
+		}
+		if docStart == 0 && trimmed != "" && !strings.HasPrefix(trimmed, "#") && !templateAction.MatchString(trimmed) {
+			docStart = i + 1
+		}
+		if m := templateKindLine.FindStringSubmatch(line); m != nil && docStart > 0 {
+			t.kinds[m[1]] = append(t.kinds[m[1]], docStart)
+		}
+	}
+	return t
+}
+
+// Next returns the line of the next rendered document of the kind, or 1 if the kind is not found. The n-th document
+// of a kind is rendered from the n-th document of the kind in the template, and any additional documents from the
+// last one, such as documents in a range loop.
+func (t *TemplateLines) Next(kind string) int {
+	lines := t.kinds[kind]
+	if len(lines) == 0 {
+		return 1
+	}
+	n := t.used[kind]
+	t.used[kind]++
+	return lines[min(n, len(lines)-1)]
+}
+
+// FieldPositions returns the positions of all fields in the object in node. The position of a field in a mapping is
+// the position of its key, and the position of a list item is the position of the item.
+func FieldPositions(node *yaml.Node) ks.FieldPositions {
+	res := make(ks.FieldPositions)
+	addFieldPositions(res, "", node)
+	return res
+}
+
+func addFieldPositions(res ks.FieldPositions, path string, node *yaml.Node) {
+	if _, ok := res[path]; !ok {
+		res[path] = ks.Position{Line: node.Line, Column: node.Column}
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			keyPath := ks.FieldPath(path, key.Value)
+			res[keyPath] = ks.Position{Line: key.Line, Column: key.Column}
+			addFieldPositions(res, keyPath, node.Content[i+1])
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			addFieldPositions(res, ks.FieldIndexPath(path, i), item)
+		}
+	}
+}
+
+// marshalNode returns the YAML of a node
+func marshalNode(node *yaml.Node) ([]byte, error) {
+	raw, err := yaml.Marshal(node)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode document: %w", err)
+	}
+	return raw, nil
+}
@@ -0,0 +1,20 @@
+package configmap
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+type ConfigMap struct {
+	Obj      corev1.ConfigMap
+	Location ks.FileLocation
+}
+
+func (c ConfigMap) ConfigMap() corev1.ConfigMap {
+	return c.Obj
+}
+
+func (c ConfigMap) FileLocation() ks.FileLocation {
+	return c.Location
+}
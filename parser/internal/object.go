@@ -0,0 +1,31 @@
+package internal
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+// Object is an object of a kind that is registered with the parser
+type Object struct {
+	TypeMeta   metav1.TypeMeta
+	ObjectMeta metav1.ObjectMeta
+	Obj        any
+	Location   ks.FileLocation
+}
+
+func (o Object) FileLocation() ks.FileLocation {
+	return o.Location
+}
+
+func (o Object) GetTypeMeta() metav1.TypeMeta {
+	return o.TypeMeta
+}
+
+func (o Object) GetObjectMeta() metav1.ObjectMeta {
+	return o.ObjectMeta
+}
+
+func (o Object) Decoded() any {
+	return o.Obj
+}
@@ -0,0 +1,80 @@
+package internal
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+// PodTemplateObject is an object of a kind with a pod template at a configured field, such as a custom resource
+type PodTemplateObject struct {
+	TypeMeta      metav1.TypeMeta
+	ObjectMeta    metav1.ObjectMeta
+	Template      corev1.PodTemplateSpec
+	TemplateField string
+	ReplicaCount  *int32
+	LabelSelector *metav1.LabelSelector
+	SelectorPath  string
+	Location      ks.FileLocation
+}
+
+func (p PodTemplateObject) FileLocation() ks.FileLocation {
+	return p.Location
+}
+
+func (p PodTemplateObject) GetTypeMeta() metav1.TypeMeta {
+	return p.TypeMeta
+}
+
+func (p PodTemplateObject) GetObjectMeta() metav1.ObjectMeta {
+	return p.ObjectMeta
+}
+
+func (p PodTemplateObject) GetPodTemplateSpec() corev1.PodTemplateSpec {
+	p.Template.ObjectMeta.Namespace = p.ObjectMeta.Namespace
+	return p.Template
+}
+
+func (p PodTemplateObject) PodTemplateField() string {
+	return p.TemplateField
+}
+
+func (p PodTemplateObject) Replicas() *int32 {
+	return p.ReplicaCount
+}
+
+func (p PodTemplateObject) Selector() *metav1.LabelSelector {
+	return p.LabelSelector
+}
+
+func (p PodTemplateObject) SelectorField() string {
+	return p.SelectorPath
+}
+
+// Corev1PodTemplate is a bare PodTemplate, which has its pod template at "template"
+type Corev1PodTemplate struct {
+	corev1.PodTemplate
+	Location ks.FileLocation
+}
+
+func (p Corev1PodTemplate) FileLocation() ks.FileLocation {
+	return p.Location
+}
+
+func (p Corev1PodTemplate) GetTypeMeta() metav1.TypeMeta {
+	return p.TypeMeta
+}
+
+func (p Corev1PodTemplate) GetObjectMeta() metav1.ObjectMeta {
+	return p.ObjectMeta
+}
+
+func (p Corev1PodTemplate) GetPodTemplateSpec() corev1.PodTemplateSpec {
+	p.Template.ObjectMeta.Namespace = p.ObjectMeta.Namespace
+	return p.Template
+}
+
+func (p Corev1PodTemplate) PodTemplateField() string {
+	return "template"
+}
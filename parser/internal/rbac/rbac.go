@@ -0,0 +1,215 @@
+package rbac
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+type RoleV1 struct {
+	Obj      rbacv1.Role
+	Location ks.FileLocation
+}
+
+func (r RoleV1) GetTypeMeta() metav1.TypeMeta {
+	return r.Obj.TypeMeta
+}
+
+func (r RoleV1) GetObjectMeta() metav1.ObjectMeta {
+	return r.Obj.ObjectMeta
+}
+
+func (r RoleV1) Rules() []rbacv1.PolicyRule {
+	return r.Obj.Rules
+}
+
+func (r RoleV1) FileLocation() ks.FileLocation {
+	return r.Location
+}
+
+type ClusterRoleV1 struct {
+	Obj      rbacv1.ClusterRole
+	Location ks.FileLocation
+}
+
+func (r ClusterRoleV1) GetTypeMeta() metav1.TypeMeta {
+	return r.Obj.TypeMeta
+}
+
+func (r ClusterRoleV1) GetObjectMeta() metav1.ObjectMeta {
+	return r.Obj.ObjectMeta
+}
+
+func (r ClusterRoleV1) Rules() []rbacv1.PolicyRule {
+	return r.Obj.Rules
+}
+
+func (r ClusterRoleV1) FileLocation() ks.FileLocation {
+	return r.Location
+}
+
+type RoleV1beta1 struct {
+	Obj      rbacv1beta1.Role
+	Location ks.FileLocation
+}
+
+func (r RoleV1beta1) GetTypeMeta() metav1.TypeMeta {
+	return r.Obj.TypeMeta
+}
+
+func (r RoleV1beta1) GetObjectMeta() metav1.ObjectMeta {
+	return r.Obj.ObjectMeta
+}
+
+func (r RoleV1beta1) Rules() []rbacv1.PolicyRule {
+	return convertRules(r.Obj.Rules)
+}
+
+func (r RoleV1beta1) FileLocation() ks.FileLocation {
+	return r.Location
+}
+
+type ClusterRoleV1beta1 struct {
+	Obj      rbacv1beta1.ClusterRole
+	Location ks.FileLocation
+}
+
+func (r ClusterRoleV1beta1) GetTypeMeta() metav1.TypeMeta {
+	return r.Obj.TypeMeta
+}
+
+func (r ClusterRoleV1beta1) GetObjectMeta() metav1.ObjectMeta {
+	return r.Obj.ObjectMeta
+}
+
+func (r ClusterRoleV1beta1) Rules() []rbacv1.PolicyRule {
+	return convertRules(r.Obj.Rules)
+}
+
+func (r ClusterRoleV1beta1) FileLocation() ks.FileLocation {
+	return r.Location
+}
+
+func convertRules(rules []rbacv1beta1.PolicyRule) []rbacv1.PolicyRule {
+	var res []rbacv1.PolicyRule
+	for _, rule := range rules {
+		res = append(res, rbacv1.PolicyRule{
+			Verbs:           rule.Verbs,
+			APIGroups:       rule.APIGroups,
+			Resources:       rule.Resources,
+			ResourceNames:   rule.ResourceNames,
+			NonResourceURLs: rule.NonResourceURLs,
+		})
+	}
+	return res
+}
+
+type RoleBindingV1 struct {
+	Obj      rbacv1.RoleBinding
+	Location ks.FileLocation
+}
+
+func (r RoleBindingV1) GetTypeMeta() metav1.TypeMeta {
+	return r.Obj.TypeMeta
+}
+
+func (r RoleBindingV1) GetObjectMeta() metav1.ObjectMeta {
+	return r.Obj.ObjectMeta
+}
+
+func (r RoleBindingV1) RoleRef() rbacv1.RoleRef {
+	return r.Obj.RoleRef
+}
+
+func (r RoleBindingV1) Subjects() []rbacv1.Subject {
+	return r.Obj.Subjects
+}
+
+func (r RoleBindingV1) FileLocation() ks.FileLocation {
+	return r.Location
+}
+
+type ClusterRoleBindingV1 struct {
+	Obj      rbacv1.ClusterRoleBinding
+	Location ks.FileLocation
+}
+
+func (r ClusterRoleBindingV1) GetTypeMeta() metav1.TypeMeta {
+	return r.Obj.TypeMeta
+}
+
+func (r ClusterRoleBindingV1) GetObjectMeta() metav1.ObjectMeta {
+	return r.Obj.ObjectMeta
+}
+
+func (r ClusterRoleBindingV1) RoleRef() rbacv1.RoleRef {
+	return r.Obj.RoleRef
+}
+
+func (r ClusterRoleBindingV1) Subjects() []rbacv1.Subject {
+	return r.Obj.Subjects
+}
+
+func (r ClusterRoleBindingV1) FileLocation() ks.FileLocation {
+	return r.Location
+}
+
+type RoleBindingV1beta1 struct {
+	Obj      rbacv1beta1.RoleBinding
+	Location ks.FileLocation
+}
+
+func (r RoleBindingV1beta1) GetTypeMeta() metav1.TypeMeta {
+	return r.Obj.TypeMeta
+}
+
+func (r RoleBindingV1beta1) GetObjectMeta() metav1.ObjectMeta {
+	return r.Obj.ObjectMeta
+}
+
+func (r RoleBindingV1beta1) RoleRef() rbacv1.RoleRef {
+	return rbacv1.RoleRef(r.Obj.RoleRef)
+}
+
+func (r RoleBindingV1beta1) Subjects() []rbacv1.Subject {
+	return convertSubjects(r.Obj.Subjects)
+}
+
+func (r RoleBindingV1beta1) FileLocation() ks.FileLocation {
+	return r.Location
+}
+
+type ClusterRoleBindingV1beta1 struct {
+	Obj      rbacv1beta1.ClusterRoleBinding
+	Location ks.FileLocation
+}
+
+func (r ClusterRoleBindingV1beta1) GetTypeMeta() metav1.TypeMeta {
+	return r.Obj.TypeMeta
+}
+
+func (r ClusterRoleBindingV1beta1) GetObjectMeta() metav1.ObjectMeta {
+	return r.Obj.ObjectMeta
+}
+
+func (r ClusterRoleBindingV1beta1) RoleRef() rbacv1.RoleRef {
+	return rbacv1.RoleRef(r.Obj.RoleRef)
+}
+
+func (r ClusterRoleBindingV1beta1) Subjects() []rbacv1.Subject {
+	return convertSubjects(r.Obj.Subjects)
+}
+
+func (r ClusterRoleBindingV1beta1) FileLocation() ks.FileLocation {
+	return r.Location
+}
+
+func convertSubjects(subjects []rbacv1beta1.Subject) []rbacv1.Subject {
+	var res []rbacv1.Subject
+	for _, subject := range subjects {
+		res = append(res, rbacv1.Subject(subject))
+	}
+	return res
+}
@@ -0,0 +1,101 @@
+package internal
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	appsv1beta2 "k8s.io/api/apps/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+type Appsv1ReplicaSet struct {
+	appsv1.ReplicaSet
+	Location ks.FileLocation
+}
+
+func (r Appsv1ReplicaSet) FileLocation() ks.FileLocation {
+	return r.Location
+}
+
+func (r Appsv1ReplicaSet) GetTypeMeta() metav1.TypeMeta {
+	return r.TypeMeta
+}
+
+func (r Appsv1ReplicaSet) GetObjectMeta() metav1.ObjectMeta {
+	return r.ObjectMeta
+}
+
+func (r Appsv1ReplicaSet) GetPodTemplateSpec() corev1.PodTemplateSpec {
+	r.Spec.Template.ObjectMeta.Namespace = r.ObjectMeta.Namespace
+	return r.Spec.Template
+}
+
+func (r Appsv1ReplicaSet) Replicas() *int32 {
+	return r.Spec.Replicas
+}
+
+func (r Appsv1ReplicaSet) Selector() *metav1.LabelSelector {
+	return r.Spec.Selector
+}
+
+type Appsv1beta2ReplicaSet struct {
+	appsv1beta2.ReplicaSet
+	Location ks.FileLocation
+}
+
+func (r Appsv1beta2ReplicaSet) FileLocation() ks.FileLocation {
+	return r.Location
+}
+
+func (r Appsv1beta2ReplicaSet) GetTypeMeta() metav1.TypeMeta {
+	return r.TypeMeta
+}
+
+func (r Appsv1beta2ReplicaSet) GetObjectMeta() metav1.ObjectMeta {
+	return r.ObjectMeta
+}
+
+func (r Appsv1beta2ReplicaSet) GetPodTemplateSpec() corev1.PodTemplateSpec {
+	r.Spec.Template.ObjectMeta.Namespace = r.ObjectMeta.Namespace
+	return r.Spec.Template
+}
+
+func (r Appsv1beta2ReplicaSet) Replicas() *int32 {
+	return r.Spec.Replicas
+}
+
+func (r Appsv1beta2ReplicaSet) Selector() *metav1.LabelSelector {
+	return r.Spec.Selector
+}
+
+type Extensionsv1beta1ReplicaSet struct {
+	extensionsv1beta1.ReplicaSet
+	Location ks.FileLocation
+}
+
+func (r Extensionsv1beta1ReplicaSet) FileLocation() ks.FileLocation {
+	return r.Location
+}
+
+func (r Extensionsv1beta1ReplicaSet) GetTypeMeta() metav1.TypeMeta {
+	return r.TypeMeta
+}
+
+func (r Extensionsv1beta1ReplicaSet) GetObjectMeta() metav1.ObjectMeta {
+	return r.ObjectMeta
+}
+
+func (r Extensionsv1beta1ReplicaSet) GetPodTemplateSpec() corev1.PodTemplateSpec {
+	r.Spec.Template.ObjectMeta.Namespace = r.ObjectMeta.Namespace
+	return r.Spec.Template
+}
+
+func (r Extensionsv1beta1ReplicaSet) Replicas() *int32 {
+	return r.Spec.Replicas
+}
+
+func (r Extensionsv1beta1ReplicaSet) Selector() *metav1.LabelSelector {
+	return r.Spec.Selector
+}
@@ -0,0 +1,47 @@
+package internal
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+type Corev1ReplicationController struct {
+	corev1.ReplicationController
+	Location ks.FileLocation
+}
+
+func (r Corev1ReplicationController) FileLocation() ks.FileLocation {
+	return r.Location
+}
+
+func (r Corev1ReplicationController) GetTypeMeta() metav1.TypeMeta {
+	return r.TypeMeta
+}
+
+func (r Corev1ReplicationController) GetObjectMeta() metav1.ObjectMeta {
+	return r.ObjectMeta
+}
+
+func (r Corev1ReplicationController) GetPodTemplateSpec() corev1.PodTemplateSpec {
+	var template corev1.PodTemplateSpec
+	if r.Spec.Template != nil {
+		template = *r.Spec.Template
+	}
+	template.ObjectMeta.Namespace = r.ObjectMeta.Namespace
+	return template
+}
+
+func (r Corev1ReplicationController) Replicas() *int32 {
+	return r.Spec.Replicas
+}
+
+// Selector is nil if the selector is not set, the selector of a ReplicationController defaults to the labels of its
+// pod template
+func (r Corev1ReplicationController) Selector() *metav1.LabelSelector {
+	if len(r.Spec.Selector) == 0 {
+		return nil
+	}
+	return &metav1.LabelSelector{MatchLabels: r.Spec.Selector}
+}
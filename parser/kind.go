@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kjson "sigs.k8s.io/json"
+	sigsyaml "sigs.k8s.io/yaml"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/parser/internal"
+)
+
+// KindDecoder decodes the JSON of an object of a kind that is registered with RegisterDecoder. The decoded object is
+// returned by ks.Object.Decoded.
+type KindDecoder func(data []byte) (any, error)
+
+// registeredKind is a kind that is registered by a library user. validated is true if the unknown fields of the
+// objects are found by decode.
+type registeredKind struct {
+	decode    func(data []byte) (any, []ks.UnknownField, error)
+	validated bool
+}
+
+// RegisterDecoder registers a decoder of the objects of a kind that kube-score does not support. The decoded objects
+// are returned by ks.AllTypes.Objects, and are scored by the meta checks and by the object checks of their decoded
+// type, see checks.RegisterObjectCheck. The objects are not validated with Config.Strict.
+func (p *Parser) RegisterDecoder(gvk schema.GroupVersionKind, decoder KindDecoder) {
+	p.kinds[gvk] = registeredKind{
+		decode: func(data []byte) (any, []ks.UnknownField, error) {
+			decoded, err := decoder(data)
+			return decoded, nil, err
+		},
+	}
+}
+
+// RegisterKind registers a kind that kube-score does not support, and that is decoded to a *T with the JSON tags of
+// T, such as the Go type of a custom resource. See RegisterDecoder. With Config.Strict, the objects are validated
+// against the fields of T.
+func RegisterKind[T any](p *Parser, gvk schema.GroupVersionKind) {
+	p.kinds[gvk] = registeredKind{
+		decode: func(data []byte) (any, []ks.UnknownField, error) {
+			decoded := new(T)
+			strictErrs, err := kjson.UnmarshalStrict(data, decoded)
+			if err != nil {
+				return nil, nil, err
+			}
+			return decoded, unknownFields(reflect.TypeOf(decoded), strictErrs), nil
+		},
+		validated: true,
+	}
+}
+
+// decodeRegisteredKind decodes an object of a registered kind, object is the unstructured form of fileContents. With
+// Strict, the unknown and duplicate fields of the objects of validated kinds are returned.
+func (p *Parser) decodeRegisteredKind(kind registeredKind, fileContents []byte, node *yaml.Node, object map[string]interface{}, location ks.FileLocation) (internal.Object, []ks.UnknownField, error) {
+	var meta metav1.PartialObjectMetadata
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object, &meta); err != nil {
+		return internal.Object{}, nil, err
+	}
+	data, err := sigsyaml.YAMLToJSON(fileContents)
+	if err != nil {
+		return internal.Object{}, nil, err
+	}
+	decoded, unknown, err := kind.decode(data)
+	if err != nil {
+		return internal.Object{}, nil, err
+	}
+	if decoded == nil {
+		return internal.Object{}, nil, fmt.Errorf("the decoder of %s returned nil", meta.GroupVersionKind())
+	}
+
+	if !p.config.Strict || !kind.validated {
+		unknown = nil
+	} else {
+		unknown = append(unknown, duplicates(node)...)
+	}
+	return internal.Object{
+		TypeMeta:   meta.TypeMeta,
+		ObjectMeta: meta.ObjectMeta,
+		Obj:        decoded,
+		Location:   location,
+	}, unknown, nil
+}
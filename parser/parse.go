@@ -1,11 +1,11 @@
 package parser
 
 import (
-	"bytes"
 	"fmt"
-	"io"
 	"log"
-	"strings"
+	"os"
+	"reflect"
+	"sync"
 
 	"gopkg.in/yaml.v3"
 	appsv1 "k8s.io/api/apps/v1"
@@ -21,27 +21,53 @@ import (
 	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	policyv1 "k8s.io/api/policy/v1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	rbacv1beta1 "k8s.io/api/rbac/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	sigsyaml "sigs.k8s.io/yaml"
 
+	"github.com/zegl/kube-score/config"
 	ks "github.com/zegl/kube-score/domain"
 	"github.com/zegl/kube-score/parser/internal"
+	internalconfigmap "github.com/zegl/kube-score/parser/internal/configmap"
 	internalcronjob "github.com/zegl/kube-score/parser/internal/cronjob"
 	internalnetpol "github.com/zegl/kube-score/parser/internal/networkpolicy"
 	internalpdb "github.com/zegl/kube-score/parser/internal/pdb"
 	internalpod "github.com/zegl/kube-score/parser/internal/pod"
+	internalrbac "github.com/zegl/kube-score/parser/internal/rbac"
+	internalsecret "github.com/zegl/kube-score/parser/internal/secret"
 	internalservice "github.com/zegl/kube-score/parser/internal/service"
+	internalserviceaccount "github.com/zegl/kube-score/parser/internal/serviceaccount"
 )
 
 type Parser struct {
-	scheme *runtime.Scheme
-	codecs serializer.CodecFactory
-	config *Config
+	scheme       *runtime.Scheme
+	codecs       serializer.CodecFactory
+	strictCodecs serializer.CodecFactory
+	config       *Config
+
+	podTemplateKinds map[schema.GroupVersionKind]config.PodTemplateKind
+	kinds            map[schema.GroupVersionKind]registeredKind
 }
 
 type Config struct {
 	VerboseOutput int
+
+	// ContinueOnParseError makes documents that can not be parsed parse errors in the result, see
+	// ks.ParseErrors, instead of failing the whole parse
+	ContinueOnParseError bool
+
+	// Strict reports the unknown and duplicate fields of the objects of the kinds that are decoded as
+	// ks.BothMeta.UnknownFields, with a suggestion of the closest valid field name for unknown fields
+	Strict bool
+
+	// PodTemplateKinds are the kinds with a pod template that are decoded as PodSpecers, in addition to
+	// DefaultPodTemplateKinds
+	PodTemplateKinds []config.PodTemplateKind
 }
 
 type schemaAdderFunc func(scheme *runtime.Scheme) error
@@ -53,9 +79,13 @@ func New(config *Config) (*Parser, error) {
 
 	scheme := runtime.NewScheme()
 	p := &Parser{
-		scheme: scheme,
-		codecs: serializer.NewCodecFactory(scheme),
-		config: config,
+		scheme:       scheme,
+		codecs:       serializer.NewCodecFactory(scheme),
+		strictCodecs: serializer.NewCodecFactory(scheme, serializer.EnableStrict),
+		config:       config,
+
+		podTemplateKinds: podTemplateKinds(config.PodTemplateKinds),
+		kinds:            make(map[schema.GroupVersionKind]registeredKind),
 	}
 	if err := p.addToScheme(); err != nil {
 		return nil, fmt.Errorf("failed to init: %w", err)
@@ -75,6 +105,8 @@ func (p *Parser) addToScheme() error {
 		batchv1beta1.AddToScheme,
 		policyv1beta1.AddToScheme,
 		policyv1.AddToScheme,
+		rbacv1.AddToScheme,
+		rbacv1beta1.AddToScheme,
 	}
 
 	for _, adder := range adders {
@@ -103,6 +135,37 @@ type parsedObjects struct {
 	ingresses            []ks.Ingress // supports multiple versions of ingress
 	cronjobs             []ks.CronJob
 	hpaTargeters         []ks.HpaTargeter // all versions of HPAs
+	roles                []ks.Role        // Roles and ClusterRoles
+	roleBindings         []ks.RoleBinding // RoleBindings and ClusterRoleBindings
+	serviceAccounts      []ks.ServiceAccount
+	configMaps           []ks.ConfigMap
+	secrets              []ks.Secret
+	parseErrors          []ks.ParseError
+	objects              []ks.Object // objects of registered kinds
+	unknownKinds         []ks.UnknownKind
+}
+
+// add adds all objects in o
+func (p *parsedObjects) add(o *parsedObjects) {
+	p.bothMetas = append(p.bothMetas, o.bothMetas...)
+	p.pods = append(p.pods, o.pods...)
+	p.podspecers = append(p.podspecers, o.podspecers...)
+	p.networkPolicies = append(p.networkPolicies, o.networkPolicies...)
+	p.services = append(p.services, o.services...)
+	p.podDisruptionBudgets = append(p.podDisruptionBudgets, o.podDisruptionBudgets...)
+	p.deployments = append(p.deployments, o.deployments...)
+	p.statefulsets = append(p.statefulsets, o.statefulsets...)
+	p.ingresses = append(p.ingresses, o.ingresses...)
+	p.cronjobs = append(p.cronjobs, o.cronjobs...)
+	p.hpaTargeters = append(p.hpaTargeters, o.hpaTargeters...)
+	p.roles = append(p.roles, o.roles...)
+	p.roleBindings = append(p.roleBindings, o.roleBindings...)
+	p.serviceAccounts = append(p.serviceAccounts, o.serviceAccounts...)
+	p.configMaps = append(p.configMaps, o.configMaps...)
+	p.secrets = append(p.secrets, o.secrets...)
+	p.parseErrors = append(p.parseErrors, o.parseErrors...)
+	p.objects = append(p.objects, o.objects...)
+	p.unknownKinds = append(p.unknownKinds, o.unknownKinds...)
 }
 
 func (p *parsedObjects) Services() []ks.Service {
@@ -149,149 +212,310 @@ func (p *parsedObjects) HorizontalPodAutoscalers() []ks.HpaTargeter {
 	return p.hpaTargeters
 }
 
+func (p *parsedObjects) Roles() []ks.Role {
+	return p.roles
+}
+
+func (p *parsedObjects) RoleBindings() []ks.RoleBinding {
+	return p.roleBindings
+}
+
+func (p *parsedObjects) ServiceAccounts() []ks.ServiceAccount {
+	return p.serviceAccounts
+}
+
+func (p *parsedObjects) ConfigMaps() []ks.ConfigMap {
+	return p.configMaps
+}
+
+func (p *parsedObjects) Secrets() []ks.Secret {
+	return p.secrets
+}
+
+func (p *parsedObjects) ParseErrors() []ks.ParseError {
+	return p.parseErrors
+}
+
+func (p *parsedObjects) Objects() []ks.Object {
+	return p.objects
+}
+
+func (p *parsedObjects) UnknownKinds() []ks.UnknownKind {
+	return p.unknownKinds
+}
+
 func Empty() ks.AllTypes {
 	return &parsedObjects{}
 }
 
 func (p *Parser) ParseFiles(files []ks.NamedReader) (ks.AllTypes, error) {
 	s := &parsedObjects{}
+	templates := make(map[string]*TemplateLines)
 
 	for _, namedReader := range files {
-		fullFile, err := io.ReadAll(namedReader)
-		if err != nil {
-			return nil, err
-		}
-
-		// Convert to unix style newlines
-		fullFile = bytes.ReplaceAll(fullFile, []byte("\r\n"), []byte("\n"))
+		renderedReader, rendered := namedReader.(ks.RenderedReader)
 
-		offset := 1 // Line numbers are 1 indexed
-
-		// Remove initial "---\n" if present
-		if bytes.HasPrefix(fullFile, []byte("---\n")) {
-			fullFile = fullFile[4:]
-			offset = 2
+		docs, err := p.readDocuments(s, namedReader, renderedReader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", namedReader.Name(), err)
 		}
 
-		for _, fileContents := range bytes.Split(fullFile, []byte("\n---\n")) {
-
-			if len(bytes.TrimSpace(fileContents)) > 0 {
-				if err := p.detectAndDecode(s, namedReader.Name(), offset, fileContents); err != nil {
-					return nil, err
-				}
+		for _, doc := range docs {
+			// The lines of the nodes are only the lines in the file if the file is not rendered from another file
+			location, fromTemplate := detectFileLocation(ks.FileLocation{Name: namedReader.Name(), Line: doc.Line}, doc, templates)
+			if rendered && !fromTemplate {
+				location.Line = renderedReader.Line()
 			}
 
-			offset += 2 + bytes.Count(fileContents, []byte("\n"))
+			if err := p.detectAndDecode(s, location, !rendered && !fromTemplate, doc.Node); err != nil {
+				return nil, err
+			}
 		}
 	}
 
 	return s, nil
 }
 
-func (p *Parser) detectAndDecode(s *parsedObjects, fileName string, fileOffset int, raw []byte) error {
-	var detect detectKind
-	err := yaml.Unmarshal(raw, &detect)
+// readDocuments reads the documents in r. With ContinueOnParseError, the documents that could not be read are added
+// as parse errors to s.
+func (p *Parser) readDocuments(s *parsedObjects, r ks.NamedReader, rendered ks.RenderedReader) ([]Document, error) {
+	if !p.config.ContinueOnParseError {
+		return ReadDocuments(r)
+	}
+
+	docs, docErrs, err := ReadDocumentsTolerant(r)
 	if err != nil {
+		return nil, err
+	}
+	for _, docErr := range docErrs {
+		location := ks.FileLocation{Name: r.Name(), Line: docErr.Line}
+		if rendered != nil {
+			location.Line = rendered.Line()
+		}
+		s.parseErrors = append(s.parseErrors, newParseError(location, nil, docErr.Err))
+	}
+	return docs, nil
+}
+
+// parseError returns err, or adds it to s as a parse error of the document in node with ContinueOnParseError
+func (p *Parser) parseError(s *parsedObjects, location ks.FileLocation, node *yaml.Node, err error) error {
+	if !p.config.ContinueOnParseError {
 		return err
 	}
+	s.parseErrors = append(s.parseErrors, newParseError(location, node, err))
+	return nil
+}
 
-	detectedVersion := schema.FromAPIVersionAndKind(detect.ApiVersion, detect.Kind)
+// newParseError returns a parse error with the metadata that could be read from node, if any
+func newParseError(location ks.FileLocation, node *yaml.Node, err error) ks.ParseError {
+	typeMeta, objectMeta := documentMeta(location, node)
+	return ks.ParseError{
+		TypeMeta:   typeMeta,
+		ObjectMeta: objectMeta,
+		Location:   location,
+		Err:        err,
+	}
+}
+
+// documentMeta returns the metadata that could be read from the document in node, if any. Objects without a name are
+// named by their location, so that every document is a separate object.
+func documentMeta(location ks.FileLocation, node *yaml.Node) (metav1.TypeMeta, metav1.ObjectMeta) {
+	var meta struct {
+		APIVersion string `yaml:"apiVersion"`
+		Kind       string `yaml:"kind"`
+		Metadata   struct {
+			Name        string            `yaml:"name"`
+			Namespace   string            `yaml:"namespace"`
+			Annotations map[string]string `yaml:"annotations"`
+		} `yaml:"metadata"`
+	}
+	if node != nil {
+		// Fields with the wrong type are skipped, all other fields are still decoded
+		_ = node.Decode(&meta)
+	}
+
+	objectMeta := metav1.ObjectMeta{Name: meta.Metadata.Name, Namespace: meta.Metadata.Namespace, Annotations: meta.Metadata.Annotations}
+	if objectMeta.Name == "" {
+		objectMeta.Name = fmt.Sprintf("%s:%d", location.Name, location.Line)
+	}
+	return metav1.TypeMeta{APIVersion: meta.APIVersion, Kind: meta.Kind}, objectMeta
+}
 
+// detectAndDecode decodes the object in the node. If nodeLines is true, the lines of the nodes are the lines in the
+// file of the location, and list items are located at their own line.
+func (p *Parser) detectAndDecode(s *parsedObjects, location ks.FileLocation, nodeLines bool, node *yaml.Node) error {
 	// Parse lists and their items recursively
-	if detectedVersion == corev1.SchemeGroupVersion.WithKind("List") {
-		var list corev1.List
-		err := p.decode(raw, &list)
-		if err != nil {
-			return err
-		}
-		for _, listItem := range list.Items {
-			err := p.detectAndDecode(s, fileName, fileOffset, listItem.Raw)
-			if err != nil {
+	if items, ok := ListItems(node); ok {
+		for _, item := range items {
+			itemLocation := location
+			if nodeLines {
+				itemLocation.Line = item.Line
+			}
+			if err := p.detectAndDecode(s, itemLocation, nodeLines, item); err != nil {
 				return err
 			}
 		}
 		return nil
 	}
 
-	err = p.decodeItem(s, detectedVersion, fileName, fileOffset, raw)
+	var detect detectKind
+	if err := node.Decode(&detect); err != nil {
+		if !p.config.ContinueOnParseError {
+			return fmt.Errorf("failed to parse %s:%d: %w", location.Name, location.Line, err)
+		}
+		return p.parseError(s, location, node, err)
+	}
+	detectedVersion := schema.FromAPIVersionAndKind(detect.ApiVersion, detect.Kind)
+
+	if nodeLines {
+		location.Fields = FieldPositions(node)
+	}
+
+	raw, err := marshalNode(node)
 	if err != nil {
-		return err
+		return p.parseError(s, location, node, err)
 	}
 
+	// The objects are only added if the whole document could be decoded
+	item := &parsedObjects{}
+	if err := p.decodeItem(item, detectedVersion, location, node, raw); err != nil {
+		return p.parseError(s, location, node, err)
+	}
+	s.add(item)
 	return nil
 }
 
-func (p *Parser) decode(data []byte, object runtime.Object) error {
+// unstructuredObject decodes a YAML document to its unstructured form.
+// Integers are decoded as int64, and not as float64 as with encoding/json.
+func unstructuredObject(raw []byte) (map[string]interface{}, error) {
+	j, err := sigsyaml.YAMLToJSON(raw)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]interface{}
+	if err := utiljson.Unmarshal(j, &object); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// decode decodes data to object. With Strict, the unknown fields in data are returned.
+func (p *Parser) decode(data []byte, object runtime.Object) ([]ks.UnknownField, error) {
 	deserializer := p.codecs.UniversalDeserializer()
-	if _, _, err := deserializer.Decode(data, nil, object); err != nil {
-		gvk := object.GetObjectKind().GroupVersionKind()
-		return fmt.Errorf("Failed to parse %s: err=%w", gvk, err)
+	if p.config.Strict {
+		deserializer = p.strictCodecs.UniversalDeserializer()
 	}
-	return nil
+	_, _, err := deserializer.Decode(data, nil, object)
+	if strictErr, ok := runtime.AsStrictDecodingError(err); ok {
+		// The object is fully decoded even if it has unknown fields
+		return unknownFields(reflect.TypeOf(object), strictErr.Errors()), nil
+	}
+	return nil, wrapDecodeError(object.GetObjectKind().GroupVersionKind(), err)
 }
 
-func detectFileLocation(fileName string, fileOffset int, fileContents []byte) ks.FileLocation {
-	// If the object YAML begins with a Helm style "# Source: " comment
-	// Use the information in there as the file name
-	firstRow := string(bytes.Split(fileContents, []byte("\n"))[0])
-	helmTemplatePrefix := "# Source: "
-	if strings.HasPrefix(firstRow, helmTemplatePrefix) {
-		return ks.FileLocation{
-			Name: firstRow[len(helmTemplatePrefix):],
-			Line: 1, // Set line to 1 as the line definition gets lost in Helm
-		}
+func wrapDecodeError(gvk schema.GroupVersionKind, err error) error {
+	if err == nil {
+		return nil
 	}
+	return fmt.Errorf("Failed to parse %s: err=%w", gvk, err)
+}
 
-	return ks.FileLocation{
-		Name: fileName,
-		Line: fileOffset,
+// detectFileLocation returns the location of a document. If the document has a Helm style "# Source: " comment, the
+// location is the template in the comment, and fromTemplate is true. The line in the template is found on a best
+// effort basis if the template exists.
+func detectFileLocation(location ks.FileLocation, doc Document, templates map[string]*TemplateLines) (res ks.FileLocation, fromTemplate bool) {
+	name, ok := doc.HelmTemplate()
+	if !ok {
+		return location, false
+	}
+	lines, ok := templates[name]
+	if !ok {
+		template, _ := os.ReadFile(name)
+		lines = NewTemplateLines(template)
+		templates[name] = lines
 	}
+	var detect detectKind
+	_ = doc.Node.Decode(&detect)
+	return ks.FileLocation{Name: name, Line: lines.Next(detect.Kind)}, true
 }
 
-func (p *Parser) decodeItem(s *parsedObjects, detectedVersion schema.GroupVersionKind, fileName string, fileOffset int, fileContents []byte) error {
-	addPodSpeccer := func(ps ks.PodSpecer) {
-		s.podspecers = append(s.podspecers, ps)
+func (p *Parser) decodeItem(s *parsedObjects, detectedVersion schema.GroupVersionKind, fileLocation ks.FileLocation, node *yaml.Node, fileContents []byte) error {
+	var errs parseErrors
+
+	// With Strict, the unknown and duplicate fields are found when the object is decoded, and are added to its meta.
+	// Kinds without a schema, such as the pod template kinds, are not validated.
+	strict := p.config.Strict
+	var unknown []ks.UnknownField
+	decode := func(object runtime.Object) error {
+		fields, err := p.decode(fileContents, object)
+		unknown = append(unknown, fields...)
+		if p.config.Strict {
+			unknown = append(unknown, duplicates(node)...)
+		}
+		return err
+	}
+
+	// The unstructured object is only decoded once, and is shared by all metas of this item. It's decoded when it's
+	// first needed, by the kinds that are decoded from it or by the external checks, as most objects never need it.
+	// The data and stringData of Secrets are removed.
+	isSecret := false
+	var objectErr error
+	decodeUnstructured := sync.OnceValue(func() map[string]interface{} {
+		object, err := unstructuredObject(fileContents)
+		objectErr = err
+		if isSecret && object != nil {
+			delete(object, "data")
+			delete(object, "stringData")
+		}
+		return object
+	})
+
+	addMeta := func(typeMeta metav1.TypeMeta, objectMeta metav1.ObjectMeta, fl ks.FileLocationer) {
 		s.bothMetas = append(s.bothMetas, ks.BothMeta{
-			TypeMeta:       ps.GetTypeMeta(),
-			ObjectMeta:     ps.GetObjectMeta(),
-			FileLocationer: ps,
+			TypeMeta:       typeMeta,
+			ObjectMeta:     objectMeta,
+			FileLocationer: fl,
+			DecodeObject:   decodeUnstructured,
+			UnknownFields:  unknown,
+			Strict:         strict,
 		})
 	}
 
-	fileLocation := detectFileLocation(fileName, fileOffset, fileContents)
-
-	var errs parseErrors
+	addPodSpeccer := func(ps ks.PodSpecer) {
+		s.podspecers = append(s.podspecers, ps)
+		addMeta(ps.GetTypeMeta(), ps.GetObjectMeta(), ps)
+	}
 
 	switch detectedVersion {
 	case corev1.SchemeGroupVersion.WithKind("Pod"):
 		var pod corev1.Pod
-		errs.AddIfErr(p.decode(fileContents, &pod))
+		errs.AddIfErr(decode(&pod))
 		p := internalpod.Pod{Obj: pod, Location: fileLocation}
 		s.pods = append(s.pods, p)
-		s.bothMetas = append(s.bothMetas, ks.BothMeta{TypeMeta: pod.TypeMeta, ObjectMeta: pod.ObjectMeta, FileLocationer: p})
+		addMeta(pod.TypeMeta, pod.ObjectMeta, p)
 
 	case batchv1.SchemeGroupVersion.WithKind("Job"):
 		var job batchv1.Job
-		errs.AddIfErr(p.decode(fileContents, &job))
+		errs.AddIfErr(decode(&job))
 		addPodSpeccer(internal.Batchv1Job{Job: job, Location: fileLocation})
 
 	case batchv1beta1.SchemeGroupVersion.WithKind("CronJob"):
 		var cronjob batchv1beta1.CronJob
-		errs.AddIfErr(p.decode(fileContents, &cronjob))
+		errs.AddIfErr(decode(&cronjob))
 		cjob := internalcronjob.CronJobV1beta1{Obj: cronjob, Location: fileLocation}
 		addPodSpeccer(cjob)
 		s.cronjobs = append(s.cronjobs, cjob)
 
 	case batchv1.SchemeGroupVersion.WithKind("CronJob"):
 		var cronjob batchv1.CronJob
-		errs.AddIfErr(p.decode(fileContents, &cronjob))
+		errs.AddIfErr(decode(&cronjob))
 		cjob := internalcronjob.CronJobV1{Obj: cronjob, Location: fileLocation}
 		addPodSpeccer(cjob)
 		s.cronjobs = append(s.cronjobs, cjob)
 
 	case appsv1.SchemeGroupVersion.WithKind("Deployment"):
 		var deployment appsv1.Deployment
-		errs.AddIfErr(p.decode(fileContents, &deployment))
+		errs.AddIfErr(decode(&deployment))
 		deploy := internal.Appsv1Deployment{Obj: deployment, Location: fileLocation}
 		addPodSpeccer(deploy)
 
@@ -299,20 +523,20 @@ func (p *Parser) decodeItem(s *parsedObjects, detectedVersion schema.GroupVersio
 		s.deployments = append(s.deployments, deploy)
 	case appsv1beta1.SchemeGroupVersion.WithKind("Deployment"):
 		var deployment appsv1beta1.Deployment
-		errs.AddIfErr(p.decode(fileContents, &deployment))
+		errs.AddIfErr(decode(&deployment))
 		addPodSpeccer(internal.Appsv1beta1Deployment{Deployment: deployment, Location: fileLocation})
 	case appsv1beta2.SchemeGroupVersion.WithKind("Deployment"):
 		var deployment appsv1beta2.Deployment
-		errs.AddIfErr(p.decode(fileContents, &deployment))
+		errs.AddIfErr(decode(&deployment))
 		addPodSpeccer(internal.Appsv1beta2Deployment{Deployment: deployment, Location: fileLocation})
 	case extensionsv1beta1.SchemeGroupVersion.WithKind("Deployment"):
 		var deployment extensionsv1beta1.Deployment
-		errs.AddIfErr(p.decode(fileContents, &deployment))
+		errs.AddIfErr(decode(&deployment))
 		addPodSpeccer(internal.Extensionsv1beta1Deployment{Deployment: deployment, Location: fileLocation})
 
 	case appsv1.SchemeGroupVersion.WithKind("StatefulSet"):
 		var statefulSet appsv1.StatefulSet
-		errs.AddIfErr(p.decode(fileContents, &statefulSet))
+		errs.AddIfErr(decode(&statefulSet))
 		sset := internal.Appsv1StatefulSet{Obj: statefulSet, Location: fileLocation}
 		addPodSpeccer(sset)
 
@@ -320,84 +544,103 @@ func (p *Parser) decodeItem(s *parsedObjects, detectedVersion schema.GroupVersio
 		s.statefulsets = append(s.statefulsets, sset)
 	case appsv1beta1.SchemeGroupVersion.WithKind("StatefulSet"):
 		var statefulSet appsv1beta1.StatefulSet
-		errs.AddIfErr(p.decode(fileContents, &statefulSet))
+		errs.AddIfErr(decode(&statefulSet))
 		addPodSpeccer(internal.Appsv1beta1StatefulSet{StatefulSet: statefulSet, Location: fileLocation})
 	case appsv1beta2.SchemeGroupVersion.WithKind("StatefulSet"):
 		var statefulSet appsv1beta2.StatefulSet
-		errs.AddIfErr(p.decode(fileContents, &statefulSet))
+		errs.AddIfErr(decode(&statefulSet))
 		addPodSpeccer(internal.Appsv1beta2StatefulSet{StatefulSet: statefulSet, Location: fileLocation})
 
 	case appsv1.SchemeGroupVersion.WithKind("DaemonSet"):
 		var daemonset appsv1.DaemonSet
-		errs.AddIfErr(p.decode(fileContents, &daemonset))
+		errs.AddIfErr(decode(&daemonset))
 		addPodSpeccer(internal.Appsv1DaemonSet{DaemonSet: daemonset, Location: fileLocation})
 	case appsv1beta2.SchemeGroupVersion.WithKind("DaemonSet"):
 		var daemonset appsv1beta2.DaemonSet
-		errs.AddIfErr(p.decode(fileContents, &daemonset))
+		errs.AddIfErr(decode(&daemonset))
 		addPodSpeccer(internal.Appsv1beta2DaemonSet{DaemonSet: daemonset, Location: fileLocation})
 	case extensionsv1beta1.SchemeGroupVersion.WithKind("DaemonSet"):
 		var daemonset extensionsv1beta1.DaemonSet
-		errs.AddIfErr(p.decode(fileContents, &daemonset))
+		errs.AddIfErr(decode(&daemonset))
 		addPodSpeccer(internal.Extensionsv1beta1DaemonSet{DaemonSet: daemonset, Location: fileLocation})
 
+	case appsv1.SchemeGroupVersion.WithKind("ReplicaSet"):
+		var replicaSet appsv1.ReplicaSet
+		errs.AddIfErr(decode(&replicaSet))
+		addPodSpeccer(internal.Appsv1ReplicaSet{ReplicaSet: replicaSet, Location: fileLocation})
+	case appsv1beta2.SchemeGroupVersion.WithKind("ReplicaSet"):
+		var replicaSet appsv1beta2.ReplicaSet
+		errs.AddIfErr(decode(&replicaSet))
+		addPodSpeccer(internal.Appsv1beta2ReplicaSet{ReplicaSet: replicaSet, Location: fileLocation})
+	case extensionsv1beta1.SchemeGroupVersion.WithKind("ReplicaSet"):
+		var replicaSet extensionsv1beta1.ReplicaSet
+		errs.AddIfErr(decode(&replicaSet))
+		addPodSpeccer(internal.Extensionsv1beta1ReplicaSet{ReplicaSet: replicaSet, Location: fileLocation})
+
+	case corev1.SchemeGroupVersion.WithKind("ReplicationController"):
+		var rc corev1.ReplicationController
+		errs.AddIfErr(decode(&rc))
+		addPodSpeccer(internal.Corev1ReplicationController{ReplicationController: rc, Location: fileLocation})
+
+	case corev1.SchemeGroupVersion.WithKind("PodTemplate"):
+		var podTemplate corev1.PodTemplate
+		errs.AddIfErr(decode(&podTemplate))
+		addPodSpeccer(internal.Corev1PodTemplate{PodTemplate: podTemplate, Location: fileLocation})
+
 	case networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"):
 		var netpol networkingv1.NetworkPolicy
-		errs.AddIfErr(p.decode(fileContents, &netpol))
+		errs.AddIfErr(decode(&netpol))
 		np := internalnetpol.NetworkPolicy{Obj: netpol, Location: fileLocation}
 		s.networkPolicies = append(s.networkPolicies, np)
-		s.bothMetas = append(s.bothMetas, ks.BothMeta{TypeMeta: netpol.TypeMeta, ObjectMeta: netpol.ObjectMeta, FileLocationer: np})
+		addMeta(netpol.TypeMeta, netpol.ObjectMeta, np)
 
 	case corev1.SchemeGroupVersion.WithKind("Service"):
 		var service corev1.Service
-		errs.AddIfErr(p.decode(fileContents, &service))
+		errs.AddIfErr(decode(&service))
 		serv := internalservice.Service{Obj: service, Location: fileLocation}
 		s.services = append(s.services, serv)
-		s.bothMetas = append(s.bothMetas, ks.BothMeta{TypeMeta: service.TypeMeta, ObjectMeta: service.ObjectMeta, FileLocationer: serv})
+		addMeta(service.TypeMeta, service.ObjectMeta, serv)
 
 	case policyv1beta1.SchemeGroupVersion.WithKind("PodDisruptionBudget"):
 		var disruptBudget policyv1beta1.PodDisruptionBudget
-		errs.AddIfErr(p.decode(fileContents, &disruptBudget))
+		errs.AddIfErr(decode(&disruptBudget))
 		dbug := internalpdb.PodDisruptionBudgetV1beta1{Obj: disruptBudget, Location: fileLocation}
 		s.podDisruptionBudgets = append(s.podDisruptionBudgets, dbug)
-		s.bothMetas = append(s.bothMetas, ks.BothMeta{TypeMeta: disruptBudget.TypeMeta, ObjectMeta: disruptBudget.ObjectMeta, FileLocationer: dbug})
+		addMeta(disruptBudget.TypeMeta, disruptBudget.ObjectMeta, dbug)
 	case policyv1.SchemeGroupVersion.WithKind("PodDisruptionBudget"):
 		var disruptBudget policyv1.PodDisruptionBudget
-		errs.AddIfErr(p.decode(fileContents, &disruptBudget))
+		errs.AddIfErr(decode(&disruptBudget))
 		dbug := internalpdb.PodDisruptionBudgetV1{Obj: disruptBudget, Location: fileLocation}
 		s.podDisruptionBudgets = append(s.podDisruptionBudgets, dbug)
-		s.bothMetas = append(s.bothMetas, ks.BothMeta{
-			TypeMeta:       disruptBudget.TypeMeta,
-			ObjectMeta:     disruptBudget.ObjectMeta,
-			FileLocationer: dbug,
-		})
+		addMeta(disruptBudget.TypeMeta, disruptBudget.ObjectMeta, dbug)
 
 	case extensionsv1beta1.SchemeGroupVersion.WithKind("Ingress"):
 		var ingress extensionsv1beta1.Ingress
-		errs.AddIfErr(p.decode(fileContents, &ingress))
+		errs.AddIfErr(decode(&ingress))
 		ing := internal.ExtensionsIngressV1beta1{Ingress: ingress, Location: fileLocation}
 		s.ingresses = append(s.ingresses, ing)
-		s.bothMetas = append(s.bothMetas, ks.BothMeta{TypeMeta: ingress.TypeMeta, ObjectMeta: ingress.ObjectMeta, FileLocationer: ing})
+		addMeta(ingress.TypeMeta, ingress.ObjectMeta, ing)
 
 	case networkingv1beta1.SchemeGroupVersion.WithKind("Ingress"):
 		var ingress networkingv1beta1.Ingress
-		errs.AddIfErr(p.decode(fileContents, &ingress))
+		errs.AddIfErr(decode(&ingress))
 		ing := internal.IngressV1beta1{Ingress: ingress, Location: fileLocation}
 		s.ingresses = append(s.ingresses, ing)
-		s.bothMetas = append(s.bothMetas, ks.BothMeta{TypeMeta: ingress.TypeMeta, ObjectMeta: ingress.ObjectMeta, FileLocationer: ing})
+		addMeta(ingress.TypeMeta, ingress.ObjectMeta, ing)
 
 	case networkingv1.SchemeGroupVersion.WithKind("Ingress"):
 		var ingress networkingv1.Ingress
-		errs.AddIfErr(p.decode(fileContents, &ingress))
+		errs.AddIfErr(decode(&ingress))
 		ing := internal.IngressV1{Ingress: ingress, Location: fileLocation}
 		s.ingresses = append(s.ingresses, ing)
-		s.bothMetas = append(s.bothMetas, ks.BothMeta{TypeMeta: ingress.TypeMeta, ObjectMeta: ingress.ObjectMeta, FileLocationer: ing})
+		addMeta(ingress.TypeMeta, ingress.ObjectMeta, ing)
 
 	case autoscalingv1.SchemeGroupVersion.WithKind("HorizontalPodAutoscaler"):
 		var hpa autoscalingv1.HorizontalPodAutoscaler
-		errs.AddIfErr(p.decode(fileContents, &hpa))
+		errs.AddIfErr(decode(&hpa))
 		h := internal.HPAv1{HorizontalPodAutoscaler: hpa, Location: fileLocation}
 		s.hpaTargeters = append(s.hpaTargeters, h)
-		s.bothMetas = append(s.bothMetas, ks.BothMeta{TypeMeta: hpa.TypeMeta, ObjectMeta: hpa.ObjectMeta, FileLocationer: h})
+		addMeta(hpa.TypeMeta, hpa.ObjectMeta, h)
 
 	// The autoscaling/v2beta1 and v2beta2 API types were removed in Kubernetes 1.26.
 	// To stay backwards compatible with manifests that still use those apiVersions,
@@ -408,15 +651,129 @@ func (p *Parser) decodeItem(s *parsedObjects, detectedVersion schema.GroupVersio
 		schema.GroupVersion{Group: "autoscaling", Version: "v2beta2"}.WithKind("HorizontalPodAutoscaler"),
 		autoscalingv2.SchemeGroupVersion.WithKind("HorizontalPodAutoscaler"):
 		var hpa autoscalingv2.HorizontalPodAutoscaler
-		errs.AddIfErr(p.decode(fileContents, &hpa))
+		errs.AddIfErr(decode(&hpa))
 		h := internal.HPAv2{HorizontalPodAutoscaler: hpa, Location: fileLocation}
 		s.hpaTargeters = append(s.hpaTargeters, h)
-		s.bothMetas = append(s.bothMetas, ks.BothMeta{TypeMeta: hpa.TypeMeta, ObjectMeta: hpa.ObjectMeta, FileLocationer: h})
+		addMeta(hpa.TypeMeta, hpa.ObjectMeta, h)
+
+	case corev1.SchemeGroupVersion.WithKind("ServiceAccount"):
+		var serviceAccount corev1.ServiceAccount
+		errs.AddIfErr(decode(&serviceAccount))
+		sa := internalserviceaccount.ServiceAccount{Obj: serviceAccount, Location: fileLocation}
+		s.serviceAccounts = append(s.serviceAccounts, sa)
+		addMeta(serviceAccount.TypeMeta, serviceAccount.ObjectMeta, sa)
+
+	case corev1.SchemeGroupVersion.WithKind("ConfigMap"):
+		var configMap corev1.ConfigMap
+		errs.AddIfErr(decode(&configMap))
+		cm := internalconfigmap.ConfigMap{Obj: configMap, Location: fileLocation}
+		s.configMaps = append(s.configMaps, cm)
+		addMeta(configMap.TypeMeta, configMap.ObjectMeta, cm)
+
+	case corev1.SchemeGroupVersion.WithKind("Secret"):
+		var secret corev1.Secret
+		errs.AddIfErr(decode(&secret))
+		// The values of Secrets are never kept, they are not needed by the checks and must not be passed on to the
+		// plugins and policies. Only the keys are kept.
+		for key := range secret.Data {
+			secret.Data[key] = nil
+		}
+		for key := range secret.StringData {
+			secret.StringData[key] = ""
+		}
+		isSecret = true
+		sec := internalsecret.Secret{Obj: secret, Location: fileLocation}
+		s.secrets = append(s.secrets, sec)
+		addMeta(secret.TypeMeta, secret.ObjectMeta, sec)
+
+	case rbacv1.SchemeGroupVersion.WithKind("Role"):
+		var role rbacv1.Role
+		errs.AddIfErr(decode(&role))
+		r := internalrbac.RoleV1{Obj: role, Location: fileLocation}
+		s.roles = append(s.roles, r)
+		addMeta(role.TypeMeta, role.ObjectMeta, r)
+	case rbacv1beta1.SchemeGroupVersion.WithKind("Role"):
+		var role rbacv1beta1.Role
+		errs.AddIfErr(decode(&role))
+		r := internalrbac.RoleV1beta1{Obj: role, Location: fileLocation}
+		s.roles = append(s.roles, r)
+		addMeta(role.TypeMeta, role.ObjectMeta, r)
+	case rbacv1.SchemeGroupVersion.WithKind("ClusterRole"):
+		var role rbacv1.ClusterRole
+		errs.AddIfErr(decode(&role))
+		r := internalrbac.ClusterRoleV1{Obj: role, Location: fileLocation}
+		s.roles = append(s.roles, r)
+		addMeta(role.TypeMeta, role.ObjectMeta, r)
+	case rbacv1beta1.SchemeGroupVersion.WithKind("ClusterRole"):
+		var role rbacv1beta1.ClusterRole
+		errs.AddIfErr(decode(&role))
+		r := internalrbac.ClusterRoleV1beta1{Obj: role, Location: fileLocation}
+		s.roles = append(s.roles, r)
+		addMeta(role.TypeMeta, role.ObjectMeta, r)
+
+	case rbacv1.SchemeGroupVersion.WithKind("RoleBinding"):
+		var binding rbacv1.RoleBinding
+		errs.AddIfErr(decode(&binding))
+		b := internalrbac.RoleBindingV1{Obj: binding, Location: fileLocation}
+		s.roleBindings = append(s.roleBindings, b)
+		addMeta(binding.TypeMeta, binding.ObjectMeta, b)
+	case rbacv1beta1.SchemeGroupVersion.WithKind("RoleBinding"):
+		var binding rbacv1beta1.RoleBinding
+		errs.AddIfErr(decode(&binding))
+		b := internalrbac.RoleBindingV1beta1{Obj: binding, Location: fileLocation}
+		s.roleBindings = append(s.roleBindings, b)
+		addMeta(binding.TypeMeta, binding.ObjectMeta, b)
+	case rbacv1.SchemeGroupVersion.WithKind("ClusterRoleBinding"):
+		var binding rbacv1.ClusterRoleBinding
+		errs.AddIfErr(decode(&binding))
+		b := internalrbac.ClusterRoleBindingV1{Obj: binding, Location: fileLocation}
+		s.roleBindings = append(s.roleBindings, b)
+		addMeta(binding.TypeMeta, binding.ObjectMeta, b)
+	case rbacv1beta1.SchemeGroupVersion.WithKind("ClusterRoleBinding"):
+		var binding rbacv1beta1.ClusterRoleBinding
+		errs.AddIfErr(decode(&binding))
+		b := internalrbac.ClusterRoleBindingV1beta1{Obj: binding, Location: fileLocation}
+		s.roleBindings = append(s.roleBindings, b)
+		addMeta(binding.TypeMeta, binding.ObjectMeta, b)
 
 	default:
+		if kind, ok := p.kinds[detectedVersion]; ok {
+			strict = strict && kind.validated
+			object := decodeUnstructured()
+			errs.AddIfErr(objectErr)
+			if object != nil {
+				o, fields, err := p.decodeRegisteredKind(kind, fileContents, node, object, fileLocation)
+				errs.AddIfErr(wrapDecodeError(detectedVersion, err))
+				if err == nil {
+					unknown = append(unknown, fields...)
+					s.objects = append(s.objects, o)
+					addMeta(o.TypeMeta, o.ObjectMeta, o)
+				}
+			}
+			break
+		}
+
+		if kind, ok := p.podTemplateKinds[detectedVersion]; ok {
+			strict = false
+			object := decodeUnstructured()
+			errs.AddIfErr(objectErr)
+			if object != nil {
+				ps, ok, err := decodePodTemplateObject(kind, object, fileLocation)
+				errs.AddIfErr(wrapDecodeError(detectedVersion, err))
+				if ok {
+					addPodSpeccer(ps)
+				} else if err == nil {
+					// Objects without a pod template, such as Rollouts with a workloadRef, only have the meta checks
+					addMeta(ps.TypeMeta, ps.ObjectMeta, ps)
+				}
+			}
+			break
+		}
+
 		if p.config.VerboseOutput > 1 {
 			log.Printf("Unknown datatype: %s", detectedVersion.String())
 		}
+		s.unknownKinds = append(s.unknownKinds, p.newUnknownKind(detectedVersion, fileLocation, node))
 	}
 
 	if errs.Any() {
@@ -2,10 +2,16 @@ package parser
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	ks "github.com/zegl/kube-score/domain"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -41,6 +47,13 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func readDocuments(t *testing.T, doc string) []Document {
+	t.Helper()
+	docs, err := ReadDocuments(strings.NewReader(doc))
+	assert.NoError(t, err)
+	return docs
+}
+
 func TestFileLocationHelm(t *testing.T) {
 	doc := `# Source: app1/templates/deployment.yaml
 kind: Deployment
@@ -53,11 +66,71 @@ spec:
       labels:
         foo: bar`
 
-	fl := detectFileLocation("someName", 1, []byte(doc))
+	fl, fromTemplate := detectFileLocation(ks.FileLocation{Name: "someName", Line: 2}, readDocuments(t, doc)[0], map[string]*TemplateLines{})
+	assert.True(t, fromTemplate)
 	assert.Equal(t, "app1/templates/deployment.yaml", fl.Name)
 	assert.Equal(t, 1, fl.Line)
 }
 
+func TestFileLocationHelmTemplate(t *testing.T) {
+	dir := t.TempDir()
+	template := filepath.Join(dir, "deployment.yaml")
+	assert.NoError(t, os.WriteFile(template, []byte(`{{- if .Values.enabled }}
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Release.Name }}
+{{- range .Values.ports }}
+---
+# A service for each port
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ $.Release.Name }}-{{ . }}
+{{- end }}
+{{- end }}
+`), 0o644))
+
+	parser, err := New(nil)
+	assert.NoError(t, err)
+	parsed, err := parser.ParseFiles([]ks.NamedReader{namedReader{strings.NewReader(`---
+# Source: ` + template + `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+---
+# Source: ` + template + `
+apiVersion: v1
+kind: Service
+metadata:
+  name: app-80
+---
+# Source: ` + template + `
+apiVersion: v1
+kind: Service
+metadata:
+  name: app-443
+`), "STDIN"}})
+	assert.NoError(t, err)
+
+	var lines []int
+	for _, m := range parsed.Metas() {
+		assert.Equal(t, template, m.FileLocation().Name)
+		lines = append(lines, m.FileLocation().Line)
+	}
+	assert.Equal(t, []int{2, 9, 9}, lines)
+}
+
+type namedReader struct {
+	io.Reader
+	name string
+}
+
+func (n namedReader) Name() string {
+	return n.name
+}
+
 func TestFileLocation(t *testing.T) {
 	doc := `kind: Deployment
 apiVersion: apps/v1
@@ -69,7 +142,545 @@ spec:
       labels:
         foo: bar`
 
-	fl := detectFileLocation("someName", 123, []byte(doc))
+	fl, fromTemplate := detectFileLocation(ks.FileLocation{Name: "someName", Line: 123}, readDocuments(t, doc)[0], map[string]*TemplateLines{})
+	assert.False(t, fromTemplate)
 	assert.Equal(t, "someName", fl.Name)
 	assert.Equal(t, 123, fl.Line)
 }
+
+func TestReadDocuments(t *testing.T) {
+	docs := readDocuments(t, `--- # The first document
+# A comment
+apiVersion: v1
+kind: Service
+metadata:
+  name: a
+---   
+--- # Empty
+# Only a comment
+---
+apiVersion: v1
+kind: List
+items:
+  - apiVersion: v1
+    kind: Service
+    metadata:
+      name: b
+  -
+    apiVersion: v1
+    kind: Service
+    metadata:
+      name: c
+`)
+	assert.Len(t, docs, 2)
+	assert.Equal(t, 3, docs[0].Line)
+	assert.Equal(t, []string{"# The first document", "# A comment"}, docs[0].Comments)
+	assert.Equal(t, 11, docs[1].Line)
+
+	items, ok := ListItems(docs[1].Node)
+	assert.True(t, ok)
+	assert.Len(t, items, 2)
+	assert.Equal(t, 14, items[0].Line)
+	assert.Equal(t, 19, items[1].Line)
+
+	_, ok = ListItems(docs[0].Node)
+	assert.False(t, ok)
+}
+
+func TestParseListLines(t *testing.T) {
+	parser, err := New(nil)
+	assert.NoError(t, err)
+	parsed, err := parser.ParseFiles([]ks.NamedReader{namedReader{strings.NewReader(`apiVersion: v1
+kind: Service
+metadata:
+  name: a
+--- # A list
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: b
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: c
+`), "list.yaml"}})
+	assert.NoError(t, err)
+
+	lines := make(map[string]int)
+	for _, m := range parsed.Metas() {
+		lines[m.ObjectMeta.Name] = m.FileLocation().Line
+	}
+	assert.Equal(t, map[string]int{"a": 1, "b": 9, "c": 13}, lines)
+}
+
+func TestParseFieldPositions(t *testing.T) {
+	parser, err := New(nil)
+	assert.NoError(t, err)
+	parsed, err := parser.ParseFiles([]ks.NamedReader{namedReader{strings.NewReader(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: a
+  annotations:
+    example.com/owner: team-a
+spec:
+  template:
+    spec:
+      containers:
+      - name: foo
+        image: foo
+      - name: bar
+        securityContext:
+          privileged: true
+`), "deployment.yaml"}})
+	assert.NoError(t, err)
+	assert.Len(t, parsed.Deployments(), 1)
+
+	fields := parsed.Deployments()[0].FileLocation().Fields
+	assert.Equal(t, ks.Position{Line: 1, Column: 1}, fields[""])
+	assert.Equal(t, ks.Position{Line: 6, Column: 5}, fields[`metadata.annotations["example.com/owner"]`])
+	assert.Equal(t, ks.Position{Line: 11, Column: 9}, fields["spec.template.spec.containers[0]"])
+	assert.Equal(t, ks.Position{Line: 12, Column: 9}, fields["spec.template.spec.containers[0].image"])
+	assert.Equal(t, ks.Position{Line: 15, Column: 11}, fields["spec.template.spec.containers[1].securityContext.privileged"])
+}
+
+func TestReadDocumentsJSON(t *testing.T) {
+	// Newline delimited JSON, with an array of objects and a value that is not an object
+	docs := readDocuments(t, `{"apiVersion": "v1", "kind": "Service", "metadata": {"name": "a"}}
+{"apiVersion": "v1", "kind": "Service", "metadata": {"name": "b"}}
+
+[{"apiVersion": "v1", "kind": "Service", "metadata": {"name": "c"}},
+  {"apiVersion": "v1", "kind": "Service", "metadata": {"name": "d"}}, "e"]
+`)
+	assert.Len(t, docs, 4)
+	assert.Equal(t, []int{1, 2, 4, 5}, []int{docs[0].Line, docs[1].Line, docs[2].Line, docs[3].Line})
+	assert.Equal(t, 2, docs[2].Node.Column)
+	assert.Equal(t, 3, docs[3].Node.Column)
+
+	fields := FieldPositions(docs[1].Node)
+	assert.Equal(t, ks.Position{Line: 2, Column: 41}, fields["metadata"])
+	fields = FieldPositions(docs[3].Node)
+	assert.Equal(t, ks.Position{Line: 5, Column: 56}, fields["metadata.name"])
+}
+
+func TestParseJSONList(t *testing.T) {
+	parser, err := New(nil)
+	assert.NoError(t, err)
+	// The output of "kubectl get -o json", and of the Kubernetes API
+	parsed, err := parser.ParseFiles([]ks.NamedReader{namedReader{strings.NewReader(`{
+    "apiVersion": "v1",
+    "items": [
+        {
+            "apiVersion": "v1",
+            "kind": "Service",
+            "metadata": {
+                "name": "a"
+            }
+        },
+        {
+            "apiVersion": "apps/v1",
+            "kind": "Deployment",
+            "metadata": {
+                "name": "b"
+            }
+        }
+    ],
+    "kind": "List"
+}
+{"apiVersion": "apps/v1", "kind": "DeploymentList", "items": [{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "c"}}]}
+`), "dump.json"}})
+	assert.NoError(t, err)
+
+	lines := make(map[string]int)
+	for _, m := range parsed.Metas() {
+		lines[m.ObjectMeta.Name] = m.FileLocation().Line
+	}
+	assert.Equal(t, map[string]int{"a": 4, "b": 11, "c": 21}, lines)
+	assert.Len(t, parsed.Deployments(), 2)
+
+	fields := parsed.Deployments()[0].FileLocation().Fields
+	assert.Equal(t, ks.Position{Line: 15, Column: 17}, fields["metadata.name"])
+}
+
+func TestReadDocumentsTolerant(t *testing.T) {
+	docs, docErrs, err := ReadDocumentsTolerant(strings.NewReader(`apiVersion: v1
+kind: Service
+metadata:
+  name: a
+--- # A syntax error
+apiVersion: v1
+kind: Service
+metadata: name: b
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: c
+`))
+	assert.NoError(t, err)
+	assert.Len(t, docs, 2)
+	assert.Equal(t, []int{1, 10}, []int{docs[0].Line, docs[1].Line})
+	assert.Len(t, docErrs, 1)
+	assert.Equal(t, 8, docErrs[0].Line)
+	assert.Equal(t, "line 8: mapping values are not allowed in this context", docErrs[0].Error())
+
+	_, err = ReadDocuments(strings.NewReader("metadata: name: b\n"))
+	assert.Error(t, err)
+
+	docs, docErrs, err = ReadDocumentsTolerant(strings.NewReader(`{"kind": "Service", "metadata": {"name": "a"}}
+{"kind": "Service", "metadata": {"name": "b"}
+{"kind": "Service", "metadata": {"name": "c"}}
+`))
+	assert.NoError(t, err)
+	assert.Len(t, docs, 2)
+	assert.Equal(t, []int{1, 3}, []int{docs[0].Line, docs[1].Line})
+	assert.Len(t, docErrs, 1)
+	assert.Equal(t, 3, docErrs[0].Line)
+}
+
+func TestParseContinueOnParseError(t *testing.T) {
+	parser, err := New(&Config{ContinueOnParseError: true})
+	assert.NoError(t, err)
+	parsed, err := parser.ParseFiles([]ks.NamedReader{namedReader{strings.NewReader(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: a
+spec:
+  replicas: many
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: b
+`), "objects.yaml"}})
+	assert.NoError(t, err)
+
+	// The Deployment that can not be decoded is only a parse error
+	assert.Empty(t, parsed.Deployments())
+	assert.Len(t, parsed.Services(), 1)
+	assert.Len(t, parsed.ParseErrors(), 1)
+
+	parseErr := parsed.ParseErrors()[0]
+	assert.Equal(t, "Deployment", parseErr.TypeMeta.Kind)
+	assert.Equal(t, "a", parseErr.ObjectMeta.Name)
+	assert.Equal(t, "objects.yaml", parseErr.Location.Name)
+	assert.Equal(t, 1, parseErr.Location.Line)
+	assert.Contains(t, parseErr.Err.Error(), "replicas")
+}
+
+func TestParseStrict(t *testing.T) {
+	doc := `apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: a
+  nmae: b
+spec:
+  schedule: "* * * * *"
+  schedule: "0 * * * *"
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: foo
+            image: foo
+            securityContext:
+              runAsNonroot: true
+            foo: bar
+`
+	parser, err := New(&Config{Strict: true})
+	assert.NoError(t, err)
+	parsed, err := parser.ParseFiles([]ks.NamedReader{namedReader{strings.NewReader(doc), "cronjob.yaml"}})
+	assert.NoError(t, err)
+	assert.Len(t, parsed.Metas(), 1)
+
+	meta := parsed.Metas()[0]
+	assert.True(t, meta.Strict)
+	assert.Equal(t, []ks.UnknownField{
+		{Path: "metadata.nmae", Suggestion: "name"},
+		{Path: "spec.jobTemplate.spec.template.spec.containers[0].foo"},
+		{Path: "spec.jobTemplate.spec.template.spec.containers[0].securityContext.runAsNonroot", Suggestion: "runAsNonRoot"},
+		{Path: "spec.schedule", Duplicate: true},
+	}, meta.UnknownFields)
+
+	// Unknown fields are ignored if not strict
+	parser, err = New(nil)
+	assert.NoError(t, err)
+	parsed, err = parser.ParseFiles([]ks.NamedReader{namedReader{strings.NewReader(doc), "cronjob.yaml"}})
+	assert.NoError(t, err)
+	assert.False(t, parsed.Metas()[0].Strict)
+	assert.Empty(t, parsed.Metas()[0].UnknownFields)
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("name", "name"))
+	assert.Equal(t, 1, editDistance("nmae", "name"))
+	assert.Equal(t, 1, editDistance("replica", "replicas"))
+	assert.Equal(t, 2, editDistance("image", "mage2"))
+	assert.Equal(t, 4, editDistance("", "port"))
+}
+
+func TestParseUnknownKinds(t *testing.T) {
+	doc := `apiVersion: apps/v1beat1
+kind: Deployment
+metadata:
+  name: typo
+  namespace: foo
+---
+apiVersion: v1
+kind: Endpoints
+metadata:
+  name: endpoints
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+---
+foo: bar
+`
+	parser, err := New(nil)
+	assert.NoError(t, err)
+	parsed, err := parser.ParseFiles([]ks.NamedReader{namedReader{strings.NewReader(doc), "unknown.yaml"}})
+	assert.NoError(t, err)
+	assert.Empty(t, parsed.Metas())
+
+	unknown := parsed.UnknownKinds()
+	if assert.Len(t, unknown, 4) {
+		assert.Equal(t, "typo", unknown[0].ObjectMeta.Name)
+		assert.Equal(t, "foo", unknown[0].ObjectMeta.Namespace)
+		assert.Equal(t, "apps/v1beta1 Deployment", unknown[0].Suggestion)
+		assert.Equal(t, 1, unknown[0].Location.Line)
+
+		// Known kinds that are not scored are not suggested
+		assert.Equal(t, "Endpoints", unknown[1].TypeMeta.Kind)
+		assert.Empty(t, unknown[1].Suggestion)
+
+		assert.Empty(t, unknown[2].Suggestion)
+
+		// Documents without a name are named by their location
+		assert.Equal(t, "unknown.yaml:17", unknown[3].ObjectMeta.Name)
+		assert.Empty(t, unknown[3].TypeMeta.Kind)
+	}
+}
+
+func TestParseRBAC(t *testing.T) {
+	doc := `apiVersion: rbac.authorization.k8s.io/v1beta1
+kind: ClusterRole
+metadata:
+  name: role
+rules:
+- apiGroups: [""]
+  resources: ["pods"]
+  verbs: ["get"]
+---
+apiVersion: rbac.authorization.k8s.io/v1beta1
+kind: RoleBinding
+metadata:
+  name: binding
+  namespace: foo
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: role
+subjects:
+- kind: ServiceAccount
+  name: sa
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: sa
+  namespace: foo
+`
+	parser, err := New(nil)
+	assert.NoError(t, err)
+	parsed, err := parser.ParseFiles([]ks.NamedReader{namedReader{strings.NewReader(doc), "rbac.yaml"}})
+	assert.NoError(t, err)
+	assert.Len(t, parsed.Metas(), 3)
+
+	if assert.Len(t, parsed.Roles(), 1) {
+		assert.Equal(t, []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}}}, parsed.Roles()[0].Rules())
+	}
+	if assert.Len(t, parsed.RoleBindings(), 1) {
+		binding := parsed.RoleBindings()[0]
+		assert.Equal(t, rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: "role"}, binding.RoleRef())
+		assert.Equal(t, []rbacv1.Subject{{Kind: "ServiceAccount", Name: "sa"}}, binding.Subjects())
+	}
+	if assert.Len(t, parsed.ServiceAccounts(), 1) {
+		assert.Equal(t, "sa", parsed.ServiceAccounts()[0].ServiceAccount().Name)
+	}
+}
+
+func TestParseSecretWithoutValues(t *testing.T) {
+	doc := `apiVersion: v1
+kind: Secret
+metadata:
+  name: secret
+data:
+  password: aHVudGVyMg==
+stringData:
+  username: admin
+`
+	parser, err := New(nil)
+	assert.NoError(t, err)
+	parsed, err := parser.ParseFiles([]ks.NamedReader{namedReader{strings.NewReader(doc), "secret.yaml"}})
+	assert.NoError(t, err)
+
+	if assert.Len(t, parsed.Secrets(), 1) {
+		secret := parsed.Secrets()[0].Secret()
+		assert.Equal(t, map[string][]byte{"password": nil}, secret.Data)
+		assert.Equal(t, map[string]string{"username": ""}, secret.StringData)
+	}
+	if assert.Len(t, parsed.Metas(), 1) {
+		object := parsed.Metas()[0].Object()
+		assert.Equal(t, "secret", object["metadata"].(map[string]interface{})["name"])
+		assert.NotContains(t, object, "data")
+		assert.NotContains(t, object, "stringData")
+	}
+}
+
+func TestParseObject(t *testing.T) {
+	doc := `apiVersion: v1
+kind: Service
+metadata:
+  name: app
+spec:
+  ports:
+  - port: 80
+`
+	parser, err := New(nil)
+	assert.NoError(t, err)
+	parsed, err := parser.ParseFiles([]ks.NamedReader{namedReader{strings.NewReader(doc), "service.yaml"}})
+	assert.NoError(t, err)
+
+	if assert.Len(t, parsed.Metas(), 1) {
+		meta := parsed.Metas()[0]
+		object := meta.Object()
+		assert.Equal(t, int64(80), object["spec"].(map[string]interface{})["ports"].([]interface{})[0].(map[string]interface{})["port"])
+		// The object is only decoded once
+		object["decoded"] = true
+		assert.Equal(t, true, meta.Object()["decoded"])
+	}
+
+	assert.Nil(t, ks.BothMeta{}.Object())
+}
+
+func TestParsePodTemplateKinds(t *testing.T) {
+	parser, err := New(nil)
+	assert.NoError(t, err)
+	parsed, err := parser.ParseFiles([]ks.NamedReader{namedReader{strings.NewReader(`apiVersion: apps.kruise.io/v1alpha1
+kind: CloneSet
+metadata:
+  name: a
+  namespace: ns
+spec:
+  replicas: 5
+  selector:
+    matchLabels:
+      app: a
+  template:
+    metadata:
+      labels:
+        app: a
+    spec:
+      containers:
+      - name: a
+        image: a:1.0.0
+---
+apiVersion: example.com/v1
+kind: Worker
+metadata:
+  name: b
+spec:
+  template:
+    spec:
+      containers:
+      - name: b
+`), "crds.yaml"}})
+	assert.NoError(t, err)
+
+	// Kinds that are not configured are not PodSpecers
+	assert.Len(t, parsed.PodSpeccers(), 1)
+	assert.Len(t, parsed.Metas(), 1)
+
+	ps := parsed.PodSpeccers()[0]
+	assert.Equal(t, "CloneSet", ps.GetTypeMeta().Kind)
+	assert.Equal(t, "a", ps.GetObjectMeta().Name)
+	assert.Equal(t, "ns", ps.GetPodTemplateSpec().Namespace)
+	assert.Equal(t, "a:1.0.0", ps.GetPodTemplateSpec().Spec.Containers[0].Image)
+	assert.Equal(t, "spec.template", ps.(ks.PodTemplateFielder).PodTemplateField())
+
+	replicated, ok := ps.(ks.Replicated)
+	assert.True(t, ok)
+	assert.Equal(t, int32(5), *replicated.Replicas())
+	assert.Equal(t, map[string]string{"app": "a"}, replicated.Selector().MatchLabels)
+
+	// Pod template kinds have no schema to validate against
+	strictParser, err := New(&Config{Strict: true})
+	assert.NoError(t, err)
+	parsed, err = strictParser.ParseFiles([]ks.NamedReader{namedReader{strings.NewReader("apiVersion: apps.kruise.io/v1alpha1\nkind: CloneSet\nmetadata:\n  name: a\nspec:\n  template: {}\n  foo: bar\n"), "crds.yaml"}})
+	assert.NoError(t, err)
+	assert.False(t, parsed.Metas()[0].Strict)
+
+	_, err = parser.ParseFiles([]ks.NamedReader{namedReader{strings.NewReader(`apiVersion: apps.kruise.io/v1alpha1
+kind: CloneSet
+metadata:
+  name: a
+spec:
+  template: foo
+`), "crds.yaml"}})
+	assert.EqualError(t, err, "Failed to parse apps.kruise.io/v1alpha1, Kind=CloneSet: err=spec.template is not an object")
+}
+
+type widget struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              struct {
+		Size int `json:"size"`
+	} `json:"spec"`
+}
+
+func TestParseRegisteredKinds(t *testing.T) {
+	parser, err := New(&Config{Strict: true})
+	assert.NoError(t, err)
+	RegisterKind[widget](parser, schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"})
+	parser.RegisterDecoder(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Gadget"}, func(data []byte) (any, error) {
+		return string(data), nil
+	})
+
+	parsed, err := parser.ParseFiles([]ks.NamedReader{namedReader{strings.NewReader(`apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: a
+  namespace: ns
+spec:
+  sise: 1
+---
+apiVersion: example.com/v1
+kind: Gadget
+metadata:
+  name: b
+spec:
+  foo: bar
+`), "objects.yaml"}})
+	assert.NoError(t, err)
+	assert.Len(t, parsed.Objects(), 2)
+	assert.Len(t, parsed.Metas(), 2)
+
+	w := parsed.Objects()[0]
+	assert.Equal(t, "Widget", w.GetTypeMeta().Kind)
+	assert.Equal(t, "ns", w.GetObjectMeta().Namespace)
+	assert.Equal(t, 1, w.FileLocation().Line)
+	assert.Equal(t, "a", w.Decoded().(*widget).Name)
+
+	// Kinds that are decoded to a Go type are validated
+	assert.True(t, parsed.Metas()[0].Strict)
+	assert.Equal(t, []ks.UnknownField{{Path: "spec.sise", Suggestion: "size"}}, parsed.Metas()[0].UnknownFields)
+
+	g := parsed.Objects()[1]
+	assert.Equal(t, `{"apiVersion":"example.com/v1","kind":"Gadget","metadata":{"name":"b"},"spec":{"foo":"bar"}}`, g.Decoded())
+	assert.False(t, parsed.Metas()[1].Strict)
+}
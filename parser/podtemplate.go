@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/parser/internal"
+)
+
+// DefaultPodTemplateKinds are the custom resources with a pod template that are scored with the pod checks by default
+var DefaultPodTemplateKinds = []config.PodTemplateKind{
+	// Argo Rollouts
+	{APIVersion: "argoproj.io/v1alpha1", Kind: "Rollout", PodTemplatePath: "spec.template", ReplicasPath: "spec.replicas", SelectorPath: "spec.selector"},
+	// OpenKruise
+	{APIVersion: "apps.kruise.io/v1alpha1", Kind: "CloneSet", PodTemplatePath: "spec.template", ReplicasPath: "spec.replicas", SelectorPath: "spec.selector"},
+	{APIVersion: "apps.kruise.io/v1alpha1", Kind: "StatefulSet", PodTemplatePath: "spec.template", ReplicasPath: "spec.replicas", SelectorPath: "spec.selector"},
+	{APIVersion: "apps.kruise.io/v1beta1", Kind: "StatefulSet", PodTemplatePath: "spec.template", ReplicasPath: "spec.replicas", SelectorPath: "spec.selector"},
+	{APIVersion: "apps.kruise.io/v1alpha1", Kind: "DaemonSet", PodTemplatePath: "spec.template", SelectorPath: "spec.selector"},
+	// KEDA
+	{APIVersion: "keda.sh/v1alpha1", Kind: "ScaledJob", PodTemplatePath: "spec.jobTargetRef.template"},
+}
+
+// podTemplateKinds returns the pod template kinds by their GroupVersionKind. The kinds in kinds take precedence over
+// the default kinds.
+func podTemplateKinds(kinds []config.PodTemplateKind) map[schema.GroupVersionKind]config.PodTemplateKind {
+	res := make(map[schema.GroupVersionKind]config.PodTemplateKind)
+	for _, kind := range append(DefaultPodTemplateKinds, kinds...) {
+		res[schema.FromAPIVersionAndKind(kind.APIVersion, kind.Kind)] = kind
+	}
+	return res
+}
+
+// decodePodTemplateObject decodes the unstructured object of a pod template kind. ok is false if the object does not
+// have a pod template, such as an Argo Rollout that references the template of a Deployment, and res only has the
+// metadata of the object.
+func decodePodTemplateObject(kind config.PodTemplateKind, object map[string]interface{}, location ks.FileLocation) (res internal.PodTemplateObject, ok bool, err error) {
+	var meta metav1.PartialObjectMetadata
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object, &meta); err != nil {
+		return res, false, err
+	}
+	res = internal.PodTemplateObject{
+		TypeMeta:      meta.TypeMeta,
+		ObjectMeta:    meta.ObjectMeta,
+		TemplateField: kind.PodTemplatePath,
+		SelectorPath:  kind.SelectorPath,
+		Location:      location,
+	}
+
+	template, found, err := unstructured.NestedFieldNoCopy(object, fieldPathKeys(kind.PodTemplatePath)...)
+	if err != nil || !found {
+		return res, false, err
+	}
+	templateObject, isObject := template.(map[string]interface{})
+	if !isObject {
+		return res, false, fmt.Errorf("%s is not an object", kind.PodTemplatePath)
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(templateObject, &res.Template); err != nil {
+		return res, false, fmt.Errorf("%s: %w", kind.PodTemplatePath, err)
+	}
+
+	if kind.ReplicasPath != "" {
+		replicas, found, err := unstructured.NestedInt64(object, fieldPathKeys(kind.ReplicasPath)...)
+		if err != nil {
+			return res, false, err
+		}
+		if found {
+			r := int32(replicas)
+			res.ReplicaCount = &r
+		}
+	}
+
+	if kind.SelectorPath != "" {
+		selector, found, err := unstructured.NestedMap(object, fieldPathKeys(kind.SelectorPath)...)
+		if err != nil {
+			return res, false, err
+		}
+		if found {
+			res.LabelSelector = &metav1.LabelSelector{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(selector, res.LabelSelector); err != nil {
+				return res, false, fmt.Errorf("%s: %w", kind.SelectorPath, err)
+			}
+		}
+	}
+
+	return res, true, nil
+}
+
+// fieldPathKeys returns the keys of a field path without list indexes, such as "spec.template"
+func fieldPathKeys(path string) []string {
+	return strings.Split(path, ".")
+}
@@ -0,0 +1,196 @@
+package parser
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+// duplicateFields returns the paths of the keys that are set more than once in the same object, see ks.FieldPositions
+func duplicateFields(node *yaml.Node) []string {
+	var res []string
+	addDuplicateFields(&res, "", node)
+	return res
+}
+
+func addDuplicateFields(res *[]string, path string, node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		seen := make(map[string]int)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyPath := ks.FieldPath(path, node.Content[i].Value)
+			// Keys that are set more than twice are only reported once
+			if seen[keyPath]++; seen[keyPath] == 2 {
+				*res = append(*res, keyPath)
+			}
+			addDuplicateFields(res, keyPath, node.Content[i+1])
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			addDuplicateFields(res, ks.FieldIndexPath(path, i), item)
+		}
+	}
+}
+
+// duplicates returns the fields that are set more than once in the object in node
+func duplicates(node *yaml.Node) []ks.UnknownField {
+	var res []ks.UnknownField
+	for _, path := range duplicateFields(node) {
+		res = append(res, ks.UnknownField{Path: path, Duplicate: true})
+	}
+	return res
+}
+
+// unknownFields returns the unknown fields in the strict decoding errors of an object of type t, with a suggestion
+// of the closest valid field name if there is one. All other errors, such as the duplicate fields, are ignored.
+func unknownFields(t reflect.Type, strictErrs []error) []ks.UnknownField {
+	const prefix = "unknown field "
+	var res []ks.UnknownField
+	for _, err := range strictErrs {
+		msg := err.Error()
+		if !strings.HasPrefix(msg, prefix) {
+			continue
+		}
+		path, err := strconv.Unquote(msg[len(prefix):])
+		if err != nil {
+			continue
+		}
+		res = append(res, ks.UnknownField{
+			Path:       path,
+			Suggestion: suggestField(t, path),
+		})
+	}
+	return res
+}
+
+// suggestField returns the name of the field that is the closest to the last key in path, in the struct of type t at
+// the parent of path. The path is in the format of the strict decoding errors, such as
+// "spec.template.spec.containers[0].readinessprobe". An empty string is returned if no field is close enough.
+func suggestField(t reflect.Type, path string) string {
+	keys := strings.Split(path, ".")
+	for _, key := range keys[:len(keys)-1] {
+		name, indexes := key, 0
+		if i := strings.Index(key, "["); i >= 0 {
+			name, indexes = key[:i], strings.Count(key[i:], "[")
+		}
+		if t = fieldType(t, name); t == nil {
+			return ""
+		}
+		for ; indexes > 0; indexes-- {
+			if t = derefType(t); t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+				return ""
+			}
+			t = t.Elem()
+		}
+	}
+
+	key := keys[len(keys)-1]
+	best, bestDistance := "", len(key)/3+1
+	for _, name := range fieldNames(derefType(t)) {
+		if strings.EqualFold(name, key) {
+			return name
+		}
+		if d := editDistance(strings.ToLower(name), strings.ToLower(key)); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// fieldType returns the type of the field with the JSON name in the struct of type t, or the value type if t is a map
+func fieldType(t reflect.Type, name string) reflect.Type {
+	t = derefType(t)
+	switch t.Kind() {
+	case reflect.Map:
+		return t.Elem()
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			jsonName, inline := jsonField(f)
+			if inline {
+				if ft := fieldType(f.Type, name); ft != nil {
+					return ft
+				}
+				continue
+			}
+			if jsonName == name {
+				return f.Type
+			}
+		}
+	}
+	return nil
+}
+
+// fieldNames returns the JSON names of the fields of the struct of type t, including the fields of inlined structs
+func fieldNames(t reflect.Type) []string {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var res []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, inline := jsonField(f)
+		switch {
+		case inline:
+			res = append(res, fieldNames(derefType(f.Type))...)
+		case name != "":
+			res = append(res, name)
+		}
+	}
+	return res
+}
+
+// jsonField returns the JSON name of a struct field, and if the fields of the struct field are inlined in its parent.
+// The name is empty if the field is not a part of the JSON.
+func jsonField(f reflect.StructField) (name string, inline bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" || (!f.IsExported() && !f.Anonymous) {
+		return "", false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if f.Anonymous && name == "" || strings.Contains(","+opts+",", ",inline,") {
+		return "", true
+	}
+	if name == "" {
+		name = f.Name
+	}
+	return name, false
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// editDistance returns the number of insertions, deletions, substitutions and transpositions of two adjacent
+// characters that are needed to change a into b
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	d := make([][]int, len(ar)+1)
+	for i := range d {
+		d[i] = make([]int, len(br)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ar[i-1] == br[j-2] && ar[i-2] == br[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ar)][len(br)]
+}
@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+// newUnknownKind returns the document in node as a document of a kind that is not supported, with a suggestion of a
+// known kind if the apiVersion and kind are close to it, such as "apps/v1beat1" instead of "apps/v1beta1".
+func (p *Parser) newUnknownKind(gvk schema.GroupVersionKind, location ks.FileLocation, node *yaml.Node) ks.UnknownKind {
+	typeMeta, objectMeta := documentMeta(location, node)
+	return ks.UnknownKind{
+		TypeMeta:   typeMeta,
+		ObjectMeta: objectMeta,
+		Location:   location,
+		Suggestion: p.suggestKind(gvk),
+	}
+}
+
+// maxKindDistance is the maximum edit distance of the apiVersion and kind of a document to a known kind, for the known
+// kind to be suggested
+const maxKindDistance = 2
+
+// suggestKind returns the apiVersion and kind of the known kind that is the closest to gvk, or an empty string if no
+// known kind is close enough. The known kinds are the kinds in the scheme of the parser, the registered kinds and the
+// pod template kinds.
+func (p *Parser) suggestKind(gvk schema.GroupVersionKind) string {
+	// Known kinds that are not scored, such as ConfigMaps, are not misspelled
+	if gvk.Kind == "" || p.scheme.Recognizes(gvk) {
+		return ""
+	}
+
+	var known []schema.GroupVersionKind
+	for k := range p.scheme.AllKnownTypes() {
+		known = append(known, k)
+	}
+	for k := range p.kinds {
+		known = append(known, k)
+	}
+	for k := range p.podTemplateKinds {
+		known = append(known, k)
+	}
+
+	key := strings.ToLower(kindString(gvk))
+	best, bestDistance := "", maxKindDistance+1
+	for _, k := range known {
+		candidate := kindString(k)
+		d := editDistance(strings.ToLower(candidate), key)
+		// Ties are broken by the name, to not depend on the order of the map
+		if d < bestDistance || d == bestDistance && candidate < best {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// kindString returns the apiVersion and kind of gvk, such as "apps/v1 Deployment"
+func kindString(gvk schema.GroupVersionKind) string {
+	apiVersion, kind := gvk.ToAPIVersionAndKind()
+	return apiVersion + " " + kind
+}
@@ -27,13 +27,17 @@ func CI(scoreCard *scorecard.Scorecard) io.Reader {
 
 		for _, card := range scoredObject.Checks {
 			if len(card.Comments) == 0 {
-				if card.Skipped {
+				if card.Suppressed {
+					fmt.Fprintf(w, "[SUPPRESSED] %s\n",
+						scoredObject.HumanFriendlyRef(),
+					)
+				} else if card.Skipped {
 					fmt.Fprintf(w, "[SKIPPED] %s\n",
 						scoredObject.HumanFriendlyRef(),
 					)
 				} else {
 					fmt.Fprintf(w, "[%s] %s\n",
-						card.Grade.String(),
+						card.Label(),
 						scoredObject.HumanFriendlyRef(),
 					)
 				}
@@ -44,15 +48,23 @@ func CI(scoreCard *scorecard.Scorecard) io.Reader {
 				if comment.Path != "" {
 					message = "(" + comment.Path + ") " + comment.Summary
 				}
+				if comment.Line > 0 {
+					message += fmt.Sprintf(" (%s:%d:%d)", scoredObject.FileLocation.Name, comment.Line, comment.Column)
+				}
 
-				if card.Skipped {
+				if card.Suppressed {
+					fmt.Fprintf(w, "[SUPPRESSED] %s: %s\n",
+						scoredObject.HumanFriendlyRef(),
+						message,
+					)
+				} else if card.Skipped {
 					fmt.Fprintf(w, "[SKIPPED] %s: %s\n",
 						scoredObject.HumanFriendlyRef(),
 						message,
 					)
 				} else {
 					fmt.Fprintf(w, "[%s] %s: %s\n",
-						card.Grade.String(),
+						card.Label(),
 						scoredObject.HumanFriendlyRef(),
 						message,
 					)
@@ -109,3 +109,45 @@ func TestCiOutput(t *testing.T) {
 [SKIPPED] bar-no-namespace v1/Testing
 `, string(all))
 }
+
+func TestCiOutputSuppressed(t *testing.T) {
+	card := &scorecard.Scorecard{
+		"a": &scorecard.ScoredObject{
+			TypeMeta:   v1.TypeMeta{Kind: "Testing", APIVersion: "v1"},
+			ObjectMeta: v1.ObjectMeta{Name: "foo"},
+			Checks: []scorecard.TestScore{
+				{
+					Check:      domain.Check{Name: "test-suppressed"},
+					Grade:      scorecard.GradeCritical,
+					Suppressed: true,
+					Comments:   []scorecard.TestScoreComment{{Path: "a", Summary: "summary"}},
+				},
+			},
+		},
+	}
+
+	all, err := io.ReadAll(CI(card))
+	assert.Nil(t, err)
+	assert.Equal(t, "[SUPPRESSED] foo v1/Testing: (a) summary\n", string(all))
+}
+
+func TestCiOutputFieldPosition(t *testing.T) {
+	card := &scorecard.Scorecard{
+		"a": &scorecard.ScoredObject{
+			TypeMeta:     v1.TypeMeta{Kind: "Testing", APIVersion: "v1"},
+			ObjectMeta:   v1.ObjectMeta{Name: "foo"},
+			FileLocation: domain.FileLocation{Name: "foo.yaml", Line: 1},
+			Checks: []scorecard.TestScore{
+				{
+					Check:    domain.Check{Name: "test-field"},
+					Grade:    scorecard.GradeCritical,
+					Comments: []scorecard.TestScoreComment{{Path: "a", Summary: "summary", Field: "spec.containers[0].image", Line: 12, Column: 9}},
+				},
+			},
+		},
+	}
+
+	all, err := io.ReadAll(CI(card))
+	assert.Nil(t, err)
+	assert.Equal(t, "[CRITICAL] foo v1/Testing: (a) summary (foo.yaml:12:9)\n", string(all))
+}
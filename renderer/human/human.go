@@ -28,12 +28,24 @@ func Human(scoreCard *scorecard.Scorecard, verboseOutput int, termWidth int, use
 
 	w := bytes.NewBufferString("")
 
+	var group string
 	for _, key := range keys {
 		scoredObject := (*scoreCard)[key]
 
+		// The keys of objects in a group start with the group, so the objects in a group are printed together
+		if scoredObject.Group != group {
+			group = scoredObject.Group
+			_, _ = color.New(color.Bold).Fprintf(w, "%s\n", group)
+		}
+
 		// Headers for each object
 		var writtenHeaderChars int
-		writtenHeaderChars, _ = color.New(color.FgMagenta).Fprintf(w, "%s/%s %s", scoredObject.TypeMeta.APIVersion, scoredObject.TypeMeta.Kind, scoredObject.ObjectMeta.Name)
+		if scoredObject.TypeMeta.APIVersion == "" && scoredObject.TypeMeta.Kind == "" {
+			// Documents that could not be parsed don't have a kind
+			writtenHeaderChars, _ = color.New(color.FgMagenta).Fprint(w, scoredObject.ObjectMeta.Name)
+		} else {
+			writtenHeaderChars, _ = color.New(color.FgMagenta).Fprintf(w, "%s/%s %s", scoredObject.TypeMeta.APIVersion, scoredObject.TypeMeta.Kind, scoredObject.ObjectMeta.Name)
+		}
 		if scoredObject.ObjectMeta.Namespace != "" {
 			written2, _ := color.New(color.FgMagenta).Fprintf(w, " in %s", scoredObject.ObjectMeta.Namespace)
 			writtenHeaderChars += written2
@@ -86,6 +98,17 @@ func outputHumanStep(card scorecard.TestScore, verboseOutput int, termWidth int)
 	var col color.Attribute
 
 	switch {
+	case card.Suppressed:
+		col = color.FgHiBlack
+
+		// Suppressed items are accepted by the baseline, and are only printed in verbose mode
+		if verboseOutput == 0 {
+			return w
+		}
+
+	case card.Informational:
+		col = color.FgCyan
+
 	case card.Skipped || card.Grade >= scorecard.GradeAllOK:
 		// Higher than or equal to --threshold-ok
 		col = color.FgGreen
@@ -105,8 +128,10 @@ func outputHumanStep(card scorecard.TestScore, verboseOutput int, termWidth int)
 
 	if card.Skipped {
 		color.New(col).Fprintf(w, "    [SKIPPED] %s\n", card.Check.Name)
+	} else if card.Suppressed {
+		color.New(col).Fprintf(w, "    [%s] %s (suppressed by the baseline)\n", card.Label(), card.Check.Name)
 	} else {
-		color.New(col).Fprintf(w, "    [%s] %s\n", card.Grade.String(), card.Check.Name)
+		color.New(col).Fprintf(w, "    [%s] %s\n", card.Label(), card.Check.Name)
 	}
 
 	for _, comment := range card.Comments {
@@ -118,6 +143,10 @@ func outputHumanStep(card scorecard.TestScore, verboseOutput int, termWidth int)
 
 		fmt.Fprint(w, comment.Summary)
 
+		if comment.Line > 0 {
+			fmt.Fprintf(w, " (line %d, column %d)", comment.Line, comment.Column)
+		}
+
 		if len(comment.Description) > 0 {
 			wrapWidth := termWidth - 12
 			if wrapWidth < 40 {
@@ -428,3 +428,50 @@ func TestHumanOutputWithLongObjectNames(t *testing.T) {
             nisl venenatis, elementum augue a, porttitor libero.
 `, string(all))
 }
+
+func TestHumanOutputGroups(t *testing.T) {
+	t.Parallel()
+	card := scorecard.New()
+	card.AddGroup("values-prod.yaml", *getTestCardAllOK())
+	card.AddGroup("values-dev.yaml", *getTestCardAllOK())
+
+	r, err := Human(&card, 0, 100, false)
+	assert.Nil(t, err)
+	all, err := io.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, `values-dev.yaml
+v1/Testing bar-no-namespace                                                   ✅
+v1/Testing foo in foofoo                                                      ✅
+values-prod.yaml
+v1/Testing bar-no-namespace                                                   ✅
+v1/Testing foo in foofoo                                                      ✅
+`, string(all))
+}
+
+func TestHumanOutputFieldPosition(t *testing.T) {
+	t.Parallel()
+	card := &scorecard.Scorecard{
+		"a": &scorecard.ScoredObject{
+			TypeMeta:   v1.TypeMeta{Kind: "Testing", APIVersion: "v1"},
+			ObjectMeta: v1.ObjectMeta{Name: "foo"},
+			Checks: []scorecard.TestScore{
+				{
+					Check: domain.Check{Name: "test-field"},
+					Grade: scorecard.GradeCritical,
+					Comments: []scorecard.TestScoreComment{
+						{Path: "a", Summary: "summary", Field: "spec.containers[0].image", Line: 12, Column: 9},
+					},
+				},
+			},
+		},
+	}
+
+	r, err := Human(card, 0, 100, false)
+	assert.Nil(t, err)
+	all, err := io.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, `v1/Testing foo                                                                💥
+    [CRITICAL] test-field
+        · a -> summary (line 12, column 9)
+`, string(all))
+}
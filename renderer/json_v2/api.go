@@ -26,19 +26,33 @@ type ScoredObject struct {
 	Checks     []TestScore       `json:"checks"`
 	FileName   string            `json:"file_name"`
 	FileRow    int               `json:"file_row"`
+	Group      string            `json:"group,omitempty"`
 }
 
 type TestScore struct {
-	Check    Check              `json:"check"`
-	Grade    scorecard.Grade    `json:"grade"`
-	Skipped  bool               `json:"skipped"`
-	Comments []TestScoreComment `json:"comments"`
+	Check         Check              `json:"check"`
+	Grade         scorecard.Grade    `json:"grade"`
+	OriginalGrade scorecard.Grade    `json:"original_grade,omitempty"`
+	Skipped       bool               `json:"skipped"`
+	Suppressed    bool               `json:"suppressed,omitempty"`
+	Comments      []TestScoreComment `json:"comments"`
+	Fixes         []Fix              `json:"fixes,omitempty"`
+	Informational bool               `json:"informational,omitempty"`
+}
+
+type Fix struct {
+	Path        string                     `json:"path"`
+	Description string                     `json:"description"`
+	Patch       []scorecard.PatchOperation `json:"patch"`
 }
 
 type TestScoreComment struct {
 	Path        string `json:"path"`
 	Summary     string `json:"summary"`
 	Description string `json:"description"`
+	Field       string `json:"field,omitempty"`
+	Line        int    `json:"line,omitempty"`
+	Column      int    `json:"column,omitempty"`
 }
 
 func Output(input *scorecard.Scorecard) io.Reader {
@@ -52,6 +66,7 @@ func Output(input *scorecard.Scorecard) io.Reader {
 			Checks:     convertTestScore(v.Checks),
 			FileName:   v.FileLocation.Name,
 			FileRow:    v.FileLocation.Line,
+			Group:      v.Group,
 		})
 	}
 
@@ -65,10 +80,14 @@ func Output(input *scorecard.Scorecard) io.Reader {
 func convertTestScore(in []scorecard.TestScore) (res []TestScore) {
 	for _, v := range in {
 		res = append(res, TestScore{
-			Check:    convertCheck(v.Check),
-			Grade:    v.Grade,
-			Skipped:  v.Skipped,
-			Comments: convertComments(v.Comments),
+			Check:         convertCheck(v.Check),
+			Grade:         v.Grade,
+			OriginalGrade: v.OriginalGrade,
+			Skipped:       v.Skipped,
+			Suppressed:    v.Suppressed,
+			Comments:      convertComments(v.Comments),
+			Fixes:         convertFixes(v.Fixes),
+			Informational: v.Informational,
 		})
 	}
 	return
@@ -80,6 +99,20 @@ func convertComments(in []scorecard.TestScoreComment) (res []TestScoreComment) {
 			Path:        v.Path,
 			Summary:     v.Summary,
 			Description: v.Description,
+			Field:       v.Field,
+			Line:        v.Line,
+			Column:      v.Column,
+		})
+	}
+	return
+}
+
+func convertFixes(in []scorecard.Fix) (res []Fix) {
+	for _, v := range in {
+		res = append(res, Fix{
+			Path:        v.Path,
+			Description: v.Description,
+			Patch:       v.Patch,
 		})
 	}
 	return
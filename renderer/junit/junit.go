@@ -21,7 +21,7 @@ func JUnit(scoreCard *scorecard.Scorecard) io.Reader {
 
 		for _, testScore := range scoredObject.Checks {
 			if len(testScore.Comments) == 0 {
-				if testScore.Skipped {
+				if testScore.Skipped || testScore.Suppressed {
 					testsuite.AddTestcase(junit.Testcase{
 						Name:      testScore.Check.Name,
 						Classname: scoredObject.HumanFriendlyRef(),
@@ -48,7 +48,7 @@ func JUnit(scoreCard *scorecard.Scorecard) io.Reader {
 						message = "(" + comment.Path + ") " + comment.Summary + ": " + comment.Description
 					}
 
-					if testScore.Skipped {
+					if testScore.Skipped || testScore.Suppressed {
 						testsuite.AddTestcase(junit.Testcase{
 							Name:      testScore.Check.Name,
 							Classname: scoredObject.HumanFriendlyRef(),
@@ -57,7 +57,14 @@ func JUnit(scoreCard *scorecard.Scorecard) io.Reader {
 							},
 						})
 					} else {
-						if testScore.Grade == scorecard.GradeAlmostOK || testScore.Grade == scorecard.GradeAllOK {
+						if testScore.Informational {
+							// Informational comments pass, with the message as output
+							testsuite.AddTestcase(junit.Testcase{
+								Name:      testScore.Check.Name,
+								Classname: scoredObject.HumanFriendlyRef(),
+								SystemOut: &junit.Output{Data: message},
+							})
+						} else if testScore.Grade == scorecard.GradeAlmostOK || testScore.Grade == scorecard.GradeAllOK {
 							testsuite.AddTestcase(junit.Testcase{
 								Name:      testScore.Check.Name,
 								Classname: scoredObject.HumanFriendlyRef(),
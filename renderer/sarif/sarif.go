@@ -34,10 +34,12 @@ func Output(input *scorecard.Scorecard) io.Reader {
 			}
 
 			var level string
-			switch check.Grade {
-			case scorecard.GradeCritical:
+			switch {
+			case check.Informational:
+				level = "note"
+			case check.Grade == scorecard.GradeCritical:
 				level = "error"
-			case scorecard.GradeWarning:
+			case check.Grade == scorecard.GradeWarning:
 				level = "warning"
 			default:
 				continue
@@ -45,8 +47,20 @@ func Output(input *scorecard.Scorecard) io.Reader {
 
 			addRule(check.Check)
 
+			var suppressions []sarif.Suppression
+			if check.Suppressed {
+				suppressions = []sarif.Suppression{{Kind: "external", Justification: "Accepted by the kube-score baseline"}}
+			}
+
 			for _, comment := range check.Comments {
+				// Point at the field of the comment if its position is known, and at the object otherwise
+				region := sarif.Region{StartLine: v.FileLocation.Line}
+				if comment.Line > 0 {
+					region = sarif.Region{StartLine: comment.Line, StartColumn: comment.Column}
+				}
+
 				results = append(results, sarif.Results{
+					Suppressions: suppressions,
 					Message: sarif.Message{
 						Text: comment.Summary,
 					},
@@ -62,6 +76,7 @@ func Output(input *scorecard.Scorecard) io.Reader {
 								ArtifactLocation: sarif.ArtifactLocation{
 									URI: "file://" + v.FileLocation.Name,
 								},
+								Region: region,
 								ContextRegion: sarif.ContextRegion{
 									StartLine: v.FileLocation.Line,
 								},
@@ -60,8 +60,9 @@ type Snippet struct {
 }
 
 type Region struct {
-	Snippet   Snippet `json:"snippet,omitempty"`
-	StartLine int     `json:"startLine,omitempty"`
+	Snippet     Snippet `json:"snippet,omitempty"`
+	StartLine   int     `json:"startLine,omitempty"`
+	StartColumn int     `json:"startColumn,omitempty"`
 }
 
 type ArtifactLocation struct {
@@ -90,12 +91,18 @@ type ResultsProperties struct {
 }
 
 type Results struct {
-	Message    Message           `json:"message,omitempty"`
-	Level      string            `json:"level,omitempty"`
-	Locations  []Locations       `json:"locations,omitempty"`
-	Properties ResultsProperties `json:"properties,omitempty"`
-	RuleID     string            `json:"ruleId,omitempty"`
-	RuleIndex  int               `json:"ruleIndex,omitempty"`
+	Message      Message           `json:"message,omitempty"`
+	Level        string            `json:"level,omitempty"`
+	Locations    []Locations       `json:"locations,omitempty"`
+	Properties   ResultsProperties `json:"properties,omitempty"`
+	RuleID       string            `json:"ruleId,omitempty"`
+	RuleIndex    int               `json:"ruleIndex,omitempty"`
+	Suppressions []Suppression     `json:"suppressions,omitempty"`
+}
+
+type Suppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
 }
 
 type Run struct {
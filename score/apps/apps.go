@@ -23,6 +23,7 @@ func Register(allChecks *checks.Checks, allHPAs []ks.HpaTargeter, allServices []
 
 	allChecks.RegisterDeploymentCheck("Deployment Pod Selector labels match template metadata labels", "Ensure the StatefulSet selector labels match the template metadata labels.", deploymentSelectorLabelsMatching)
 	allChecks.RegisterStatefulSetCheck("StatefulSet Pod Selector labels match template metadata labels", "Ensure the StatefulSet selector labels match the template metadata labels.", statefulSetSelectorLabelsMatching)
+	allChecks.RegisterPodCheckFor("Pod template kind selector labels match template metadata labels", "Ensure the selector labels of ReplicaSets, ReplicationControllers and custom resources with a configured selector path match the template metadata labels.", hasSelector, podTemplateKindSelectorLabelsMatching)
 }
 
 func hpaDeploymentNoReplicas(allHPAs []ks.HpaTargeter) func(deployment appsv1.Deployment) (scorecard.TestScore, error) {
@@ -41,7 +42,7 @@ func hpaDeploymentNoReplicas(allHPAs []ks.HpaTargeter) func(deployment appsv1.De
 				}
 
 				score.Grade = scorecard.GradeCritical
-				score.AddComment("", "The deployment is targeted by a HPA, but a static replica count is configured in the DeploymentSpec", "When replicas are both statically set and managed by the HPA, the replicas will be changed to the statically configured count when the spec is applied, even if the HPA wants the replica count to be higher.")
+				score.AddCommentWithField("", "The deployment is targeted by a HPA, but a static replica count is configured in the DeploymentSpec", "When replicas are both statically set and managed by the HPA, the replicas will be changed to the statically configured count when the spec is applied, even if the HPA wants the replica count to be higher.", "spec.replicas")
 				return
 			}
 		}
@@ -65,7 +66,7 @@ func deploymentHasAntiAffinity(deployment appsv1.Deployment) (score scorecard.Te
 
 	warn := func() {
 		score.Grade = scorecard.GradeWarning
-		score.AddComment("", "Deployment does not have a host podAntiAffinity set", "It's recommended to set a podAntiAffinity that stops multiple pods from a deployment from being scheduled on the same node. This increases availability in case the node becomes unavailable.")
+		score.AddCommentWithField("", "Deployment does not have a host podAntiAffinity set", "It's recommended to set a podAntiAffinity that stops multiple pods from a deployment from being scheduled on the same node. This increases availability in case the node becomes unavailable.", "spec.template.spec.affinity.podAntiAffinity")
 	}
 
 	affinity := deployment.Spec.Template.Spec.Affinity
@@ -97,7 +98,7 @@ func statefulsetHasAntiAffinity(statefulset appsv1.StatefulSet) (score scorecard
 
 	warn := func() {
 		score.Grade = scorecard.GradeWarning
-		score.AddComment("", "StatefulSet does not have a host podAntiAffinity set", "It's recommended to set a podAntiAffinity that stops multiple pods from a statefulset from being scheduled on the same node. This increases availability in case the node becomes unavailable.")
+		score.AddCommentWithField("", "StatefulSet does not have a host podAntiAffinity set", "It's recommended to set a podAntiAffinity that stops multiple pods from a statefulset from being scheduled on the same node. This increases availability in case the node becomes unavailable.", "spec.template.spec.affinity.podAntiAffinity")
 	}
 
 	affinity := statefulset.Spec.Template.Spec.Affinity
@@ -170,7 +171,7 @@ func statefulsetHasServiceName(allServices []ks.Service) func(statefulset appsv1
 		}
 
 		score.Grade = scorecard.GradeCritical
-		score.AddComment("", "StatefulSet does not have a valid serviceName", "StatefulSets currently require a Headless Service to be responsible for the network identity of the Pods. You are responsible for creating this Service. https://kubernetes.io/docs/concepts/workloads/controllers/statefulset/#limitations")
+		score.AddCommentWithField("", "StatefulSet does not have a valid serviceName", "StatefulSets currently require a Headless Service to be responsible for the network identity of the Pods. You are responsible for creating this Service. https://kubernetes.io/docs/concepts/workloads/controllers/statefulset/#limitations", "spec.serviceName")
 		return
 	}
 }
@@ -179,7 +180,7 @@ func statefulSetSelectorLabelsMatching(statefulset appsv1.StatefulSet) (score sc
 	selector, err := metav1.LabelSelectorAsSelector(statefulset.Spec.Selector)
 	if err != nil {
 		score.Grade = scorecard.GradeCritical
-		score.AddComment("", "StatefulSet selector labels are not matching template metadata labels", fmt.Sprintf("Invalid selector: %s", err))
+		score.AddCommentWithField("", "StatefulSet selector labels are not matching template metadata labels", fmt.Sprintf("Invalid selector: %s", err), "spec.selector")
 		return
 	}
 
@@ -189,7 +190,7 @@ func statefulSetSelectorLabelsMatching(statefulset appsv1.StatefulSet) (score sc
 	}
 
 	score.Grade = scorecard.GradeCritical
-	score.AddComment("", "StatefulSet selector labels not matching template metadata labels", "StatefulSets require `.spec.selector` to match `.spec.template.metadata.labels`. https://kubernetes.io/docs/concepts/workloads/controllers/statefulset/#pod-selector")
+	score.AddCommentWithField("", "StatefulSet selector labels not matching template metadata labels", "StatefulSets require `.spec.selector` to match `.spec.template.metadata.labels`. https://kubernetes.io/docs/concepts/workloads/controllers/statefulset/#pod-selector", "spec.selector")
 	return
 }
 
@@ -197,7 +198,7 @@ func deploymentSelectorLabelsMatching(deployment appsv1.Deployment) (score score
 	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
 	if err != nil {
 		score.Grade = scorecard.GradeCritical
-		score.AddComment("", "Deployment selector labels are not matching template metadata labels", fmt.Sprintf("Invalid selector: %s", err))
+		score.AddCommentWithField("", "Deployment selector labels are not matching template metadata labels", fmt.Sprintf("Invalid selector: %s", err), "spec.selector")
 		return
 	}
 
@@ -207,6 +208,31 @@ func deploymentSelectorLabelsMatching(deployment appsv1.Deployment) (score score
 	}
 
 	score.Grade = scorecard.GradeCritical
-	score.AddComment("", "Deployment selector labels not matching template metadata labels", "Deployment require `.spec.selector` to match `.spec.template.metadata.labels`. https://kubernetes.io/docs/concepts/workloads/controllers/deployment/")
+	score.AddCommentWithField("", "Deployment selector labels not matching template metadata labels", "Deployment require `.spec.selector` to match `.spec.template.metadata.labels`. https://kubernetes.io/docs/concepts/workloads/controllers/deployment/", "spec.selector")
+	return
+}
+
+// hasSelector returns true if the object selects the pods of its template with a label selector, see ks.Replicated
+func hasSelector(ps ks.PodSpecer) bool {
+	replicated, ok := ps.(ks.Replicated)
+	return ok && replicated.Selector() != nil
+}
+
+func podTemplateKindSelectorLabelsMatching(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+	kind := ps.GetTypeMeta().Kind
+	selector, err := metav1.LabelSelectorAsSelector(ps.(ks.Replicated).Selector())
+	if err != nil {
+		score.Grade = scorecard.GradeCritical
+		score.AddCommentWithField("", fmt.Sprintf("%s selector labels are not matching template metadata labels", kind), fmt.Sprintf("Invalid selector: %s", err), internal.SelectorField(ps))
+		return score, nil
+	}
+
+	if selector.Matches(internal.MapLabels(ps.GetPodTemplateSpec().Labels)) {
+		score.Grade = scorecard.GradeAllOK
+		return
+	}
+
+	score.Grade = scorecard.GradeCritical
+	score.AddCommentWithField("", fmt.Sprintf("%s selector labels not matching template metadata labels", kind), fmt.Sprintf("The pods of the %s are only managed by it if its selector matches the labels of the pod template.", kind), internal.SelectorField(ps))
 	return
 }
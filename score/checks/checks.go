@@ -12,6 +12,9 @@ import (
 
 type Config struct {
 	IgnoredTests map[string]struct{}
+
+	// Params sets the values of check parameters, keyed by "<check-id>.<param-name>"
+	Params map[string]string
 }
 
 func New(cnf *Config) *Checks {
@@ -32,6 +35,11 @@ func New(cnf *Config) *Checks {
 		cronjobs:                 make(map[string]GenCheck[ks.CronJob]),
 		horizontalPodAutoscalers: make(map[string]GenCheck[ks.HpaTargeter]),
 		poddisruptionbudgets:     make(map[string]GenCheck[ks.PodDisruptionBudget]),
+		roles:                    make(map[string]GenCheck[ks.Role]),
+		roleBindings:             make(map[string]GenCheck[ks.RoleBinding]),
+		parseErrors:              make(map[string]GenCheck[ks.ParseError]),
+		unknownKinds:             make(map[string]GenCheck[ks.UnknownKind]),
+		objects:                  make(map[string]ObjectCheck),
 	}
 }
 
@@ -62,6 +70,15 @@ type CheckFunc[T any] func(T) (scorecard.TestScore, error)
 type GenCheck[T any] struct {
 	ks.Check
 	Fn CheckFunc[T]
+
+	// applies returns true if the check is run on the object, the check is run on all objects if nil
+	applies func(T) bool
+}
+
+// Applies returns true if the check is run on the object. Objects that the check does not apply to have no result of
+// the check.
+func (c GenCheck[T]) Applies(o T) bool {
+	return c.applies == nil || c.applies(o)
 }
 
 type Checks struct {
@@ -76,6 +93,14 @@ type Checks struct {
 	cronjobs                 map[string]GenCheck[ks.CronJob]
 	horizontalPodAutoscalers map[string]GenCheck[ks.HpaTargeter]
 	poddisruptionbudgets     map[string]GenCheck[ks.PodDisruptionBudget]
+	roles                    map[string]GenCheck[ks.Role]
+	roleBindings             map[string]GenCheck[ks.RoleBinding]
+	parseErrors              map[string]GenCheck[ks.ParseError]
+	unknownKinds             map[string]GenCheck[ks.UnknownKind]
+	objects                  map[string]ObjectCheck
+
+	params    []Param
+	paramErrs []error
 
 	cnf *Config
 }
@@ -97,14 +122,38 @@ func (c *Checks) Metas() map[string]GenCheck[ks.BothMeta] {
 	return c.metas
 }
 
+// RegisterCustomMetaCheck registers a check that runs on all objects, with a user defined ID.
+// It's used for checks that are not built in to kube-score, and where the ID can not be derived from the name.
+func (c *Checks) RegisterCustomMetaCheck(check ks.Check, fn CheckFunc[ks.BothMeta]) {
+	regCheck(c, check, fn, c.metas)
+}
+
+// RegisterCustomMetaCheckFor registers a check with a user defined ID, that only runs on the objects that applies
+// returns true for
+func (c *Checks) RegisterCustomMetaCheckFor(check ks.Check, applies func(ks.BothMeta) bool, fn CheckFunc[ks.BothMeta]) {
+	regFor(c, check, applies, fn, c.metas)
+}
+
 func reg[T any](c *Checks, targetType, name, comment string, optional bool, fn CheckFunc[T], mp map[string]GenCheck[T]) {
-	ch := NewCheck(name, targetType, comment, optional)
-	check := GenCheck[T]{Check: ch, Fn: fn}
+	regCheck(c, NewCheck(name, targetType, comment, optional), fn, mp)
+}
+
+// RegisterMetaCheckFor registers a check that only runs on the objects that applies returns true for
+func (c *Checks) RegisterMetaCheckFor(name, comment string, applies func(ks.BothMeta) bool, fn CheckFunc[ks.BothMeta]) {
+	regFor(c, NewCheck(name, "all", comment, false), applies, fn, c.metas)
+}
+
+func regCheck[T any](c *Checks, ch ks.Check, fn CheckFunc[T], mp map[string]GenCheck[T]) {
+	regFor(c, ch, nil, fn, mp)
+}
+
+func regFor[T any](c *Checks, ch ks.Check, applies func(T) bool, fn CheckFunc[T], mp map[string]GenCheck[T]) {
+	check := GenCheck[T]{Check: ch, Fn: fn, applies: applies}
 	c.all = append(c.all, check.Check)
 	if !c.isEnabled(check.Check) {
 		return
 	}
-	mp[machineFriendlyName(ch.Name)] = check
+	mp[ch.ID] = check
 }
 
 func (c *Checks) RegisterPodCheck(name, comment string, fn CheckFunc[ks.PodSpecer]) {
@@ -115,6 +164,11 @@ func (c *Checks) RegisterOptionalPodCheck(name, comment string, fn CheckFunc[ks.
 	reg(c, "Pod", name, comment, true, fn, c.pods)
 }
 
+// RegisterPodCheckFor registers a check that only runs on the pods that applies returns true for
+func (c *Checks) RegisterPodCheckFor(name, comment string, applies func(ks.PodSpecer) bool, fn CheckFunc[ks.PodSpecer]) {
+	regFor(c, NewCheck(name, "Pod", comment, false), applies, fn, c.pods)
+}
+
 func (c *Checks) Pods() map[string]GenCheck[ks.PodSpecer] {
 	return c.pods
 }
@@ -199,6 +253,24 @@ func (c *Checks) PodDisruptionBudgets() map[string]GenCheck[ks.PodDisruptionBudg
 	return c.poddisruptionbudgets
 }
 
+// RegisterRoleCheck registers a check of the Roles and ClusterRoles
+func (c *Checks) RegisterRoleCheck(name, comment string, fn CheckFunc[ks.Role]) {
+	reg(c, "Role", name, comment, false, fn, c.roles)
+}
+
+func (c *Checks) Roles() map[string]GenCheck[ks.Role] {
+	return c.roles
+}
+
+// RegisterRoleBindingCheck registers a check of the RoleBindings and ClusterRoleBindings
+func (c *Checks) RegisterRoleBindingCheck(name, comment string, fn CheckFunc[ks.RoleBinding]) {
+	reg(c, "RoleBinding", name, comment, false, fn, c.roleBindings)
+}
+
+func (c *Checks) RoleBindings() map[string]GenCheck[ks.RoleBinding] {
+	return c.roleBindings
+}
+
 func (c *Checks) RegisterServiceCheck(name, comment string, fn CheckFunc[corev1.Service]) {
 	reg(c, "Service", name, comment, false, fn, c.services)
 }
@@ -211,6 +283,64 @@ func (c *Checks) Services() map[string]GenCheck[corev1.Service] {
 	return c.services
 }
 
+// RegisterParseErrorCheck registers a check of the documents that could not be parsed
+func (c *Checks) RegisterParseErrorCheck(name, comment string, fn CheckFunc[ks.ParseError]) {
+	reg(c, "all", name, comment, false, fn, c.parseErrors)
+}
+
+func (c *Checks) ParseErrors() map[string]GenCheck[ks.ParseError] {
+	return c.parseErrors
+}
+
+// RegisterUnknownKindCheck registers a check of the documents of kinds that are not supported
+func (c *Checks) RegisterUnknownKindCheck(name, comment string, fn CheckFunc[ks.UnknownKind]) {
+	reg(c, "all", name, comment, false, fn, c.unknownKinds)
+}
+
+func (c *Checks) UnknownKinds() map[string]GenCheck[ks.UnknownKind] {
+	return c.unknownKinds
+}
+
+// ObjectCheck is a check of the objects of a kind that is registered with the parser, see RegisterObjectCheck. The
+// check applies to the objects of the same type.
+type ObjectCheck struct {
+	GenCheck[ks.Object]
+}
+
+// RegisterObjectCheck registers a check of the objects of a kind that is registered with the parser, and that are
+// decoded to T, see ks.Object. The check is only run on the objects that are decoded to T. targetType is the name of
+// the target, such as the kind of the objects.
+func RegisterObjectCheck[T any](c *Checks, targetType, name, comment string, fn CheckFunc[T]) {
+	regObjectCheck(c, NewCheck(name, targetType, comment, false), fn)
+}
+
+func RegisterOptionalObjectCheck[T any](c *Checks, targetType, name, comment string, fn CheckFunc[T]) {
+	regObjectCheck(c, NewCheck(name, targetType, comment, true), fn)
+}
+
+func regObjectCheck[T any](c *Checks, ch ks.Check, fn CheckFunc[T]) {
+	c.all = append(c.all, ch)
+	if !c.isEnabled(ch) {
+		return
+	}
+	c.objects[ch.ID] = ObjectCheck{
+		GenCheck: GenCheck[ks.Object]{
+			Check: ch,
+			Fn: func(o ks.Object) (scorecard.TestScore, error) {
+				return fn(o.Decoded().(T))
+			},
+			applies: func(o ks.Object) bool {
+				_, ok := o.Decoded().(T)
+				return ok
+			},
+		},
+	}
+}
+
+func (c *Checks) Objects() map[string]ObjectCheck {
+	return c.objects
+}
+
 func (c *Checks) All() []ks.Check {
 	return c.all
 }
@@ -0,0 +1,129 @@
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// ParamType is the set of types that can be used for check parameters
+type ParamType interface {
+	int | bool | string
+}
+
+// Param describes a tunable parameter of a check
+type Param struct {
+	CheckID     string
+	Name        string
+	Type        string
+	Default     string
+	Description string
+}
+
+// Key is the name used to set the parameter in Config.Params, on the format "<check-id>.<param-name>"
+func (p Param) Key() string {
+	return p.CheckID + "." + p.Name
+}
+
+// RegisterParam declares a parameter of the check with the given name, and returns the value to use.
+//
+// The value is read from Config.Params, and def is used if the parameter is not configured. If the configured
+// value can't be parsed, or is rejected by validate, def is used and the error is returned by ParamsErr.
+// validate can be nil.
+func RegisterParam[T ParamType](c *Checks, checkName, name string, def T, description string, validate func(T) error) T {
+	p := Param{
+		CheckID:     machineFriendlyName(checkName),
+		Name:        name,
+		Type:        paramTypeName(def),
+		Default:     fmt.Sprint(def),
+		Description: description,
+	}
+	c.params = append(c.params, p)
+
+	raw, ok := c.cnf.Params[p.Key()]
+	if !ok {
+		return def
+	}
+
+	value, err := parseParam[T](raw)
+	if err == nil && validate != nil {
+		err = validate(value)
+	}
+	if err != nil {
+		c.paramErrs = append(c.paramErrs, fmt.Errorf("invalid value %q for parameter %s: %w", raw, p.Key(), err))
+		return def
+	}
+
+	return value
+}
+
+func paramTypeName(v any) string {
+	switch v.(type) {
+	case int:
+		return "int"
+	case bool:
+		return "bool"
+	default:
+		return "string"
+	}
+}
+
+func parseParam[T ParamType](raw string) (T, error) {
+	var res T
+	switch ptr := any(&res).(type) {
+	case *int:
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return res, errors.New("expected an integer")
+		}
+		*ptr = v
+	case *bool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return res, errors.New("expected a boolean")
+		}
+		*ptr = v
+	case *string:
+		*ptr = raw
+	}
+	return res, nil
+}
+
+// AtLeast returns a validation function for RegisterParam that rejects values lower than min
+func AtLeast(min int) func(int) error {
+	return func(v int) error {
+		if v < min {
+			return fmt.Errorf("must be at least %d", min)
+		}
+		return nil
+	}
+}
+
+// Params returns all parameters that have been declared by the registered checks
+func (c *Checks) Params() []Param {
+	return c.params
+}
+
+// ParamsErr returns an error if any of the configured parameters is invalid, or is not declared by any check
+func (c *Checks) ParamsErr() error {
+	errs := append([]error{}, c.paramErrs...)
+
+	declared := make(map[string]struct{}, len(c.params))
+	for _, p := range c.params {
+		declared[p.Key()] = struct{}{}
+	}
+
+	var unknown []string
+	for key := range c.cnf.Params {
+		if _, ok := declared[key]; !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		errs = append(errs, fmt.Errorf("unknown parameter %s", key))
+	}
+
+	return errors.Join(errs...)
+}
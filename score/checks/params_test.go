@@ -0,0 +1,42 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterParam(t *testing.T) {
+	c := New(&Config{Params: map[string]string{
+		"my-check.limit":   "5",
+		"my-check.enabled": "false",
+		"my-check.name":    "foo",
+	}})
+
+	assert.Equal(t, 5, RegisterParam(c, "My Check", "limit", 10, "", nil))
+	assert.Equal(t, false, RegisterParam(c, "My Check", "enabled", true, "", nil))
+	assert.Equal(t, "foo", RegisterParam(c, "My Check", "name", "bar", "", nil))
+	assert.Equal(t, 3, RegisterParam(c, "My Check", "other", 3, "", nil))
+	assert.NoError(t, c.ParamsErr())
+
+	assert.Equal(t, []Param{
+		{CheckID: "my-check", Name: "limit", Type: "int", Default: "10"},
+		{CheckID: "my-check", Name: "enabled", Type: "bool", Default: "true"},
+		{CheckID: "my-check", Name: "name", Type: "string", Default: "bar"},
+		{CheckID: "my-check", Name: "other", Type: "int", Default: "3"},
+	}, c.Params())
+}
+
+func TestRegisterParamInvalid(t *testing.T) {
+	c := New(&Config{Params: map[string]string{
+		"my-check.limit": "five",
+		"my-check.min":   "-1",
+		"my-check.typo":  "1",
+	}})
+
+	assert.Equal(t, 10, RegisterParam(c, "My Check", "limit", 10, "", nil))
+	assert.Equal(t, 0, RegisterParam(c, "My Check", "min", 0, "", AtLeast(0)))
+	assert.EqualError(t, c.ParamsErr(), `invalid value "five" for parameter my-check.limit: expected an integer
+invalid value "-1" for parameter my-check.min: must be at least 0
+unknown parameter my-check.typo`)
+}
@@ -0,0 +1,205 @@
+package configref
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/score/internal"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// Register registers the checks of the references to ConfigMaps and Secrets. The ConfigMaps and Secrets in external are
+// provided outside of the manifests, and are not required to exist. The names are on the format [namespace/]name.
+func Register(allChecks *checks.Checks, configMaps ks.ConfigMaps, secrets ks.Secrets, external []string) {
+	allChecks.RegisterPodCheckFor("Pod ConfigMap and Secret references", `Makes sure that the ConfigMaps and Secrets that the pod references exist in the same namespace, and have the referenced keys`, hasReferences, podReferences(newObjects(configMaps.ConfigMaps(), secrets.Secrets()), external))
+}
+
+type objectKey struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+// objects are the keys of the ConfigMaps and Secrets in the manifests
+type objects map[objectKey]map[string]struct{}
+
+func newObjects(configMaps []ks.ConfigMap, secrets []ks.Secret) objects {
+	res := make(objects)
+	add := func(kind, namespace, name string, keys ...string) {
+		k := objectKey{kind: kind, namespace: namespace, name: name}
+		if _, ok := res[k]; !ok {
+			res[k] = make(map[string]struct{})
+		}
+		for _, key := range keys {
+			res[k][key] = struct{}{}
+		}
+	}
+	for _, cm := range configMaps {
+		obj := cm.ConfigMap()
+		add("ConfigMap", obj.Namespace, obj.Name)
+		for key := range obj.Data {
+			add("ConfigMap", obj.Namespace, obj.Name, key)
+		}
+		for key := range obj.BinaryData {
+			add("ConfigMap", obj.Namespace, obj.Name, key)
+		}
+	}
+	for _, secret := range secrets {
+		obj := secret.Secret()
+		add("Secret", obj.Namespace, obj.Name)
+		for key := range obj.Data {
+			add("Secret", obj.Namespace, obj.Name, key)
+		}
+		for key := range obj.StringData {
+			add("Secret", obj.Namespace, obj.Name, key)
+		}
+	}
+	return res
+}
+
+// reference is a reference from a pod to a ConfigMap or Secret, and optionally to keys in it
+type reference struct {
+	kind     string
+	name     string
+	optional bool
+	// field is the path to the name of the ConfigMap or Secret, see domain.FieldPositions
+	field string
+	keys  []keyReference
+}
+
+type keyReference struct {
+	key   string
+	field string
+}
+
+func isOptional(optional *bool) bool {
+	return optional != nil && *optional
+}
+
+// references returns all references to ConfigMaps and Secrets in the environment of the containers and in the volumes
+// of the pod
+func references(ps ks.PodSpecer) []reference {
+	spec := ps.GetPodTemplateSpec().Spec
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+	containerFields := internal.ContainerFields(ps)
+
+	var res []reference
+	for i, container := range containers {
+		for j, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			field := ks.FieldIndexPath(containerFields[i]+".env", j) + ".valueFrom"
+			if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+				res = append(res, reference{
+					kind: "ConfigMap", name: ref.Name, optional: isOptional(ref.Optional), field: field + ".configMapKeyRef.name",
+					keys: []keyReference{{key: ref.Key, field: field + ".configMapKeyRef.key"}},
+				})
+			}
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+				res = append(res, reference{
+					kind: "Secret", name: ref.Name, optional: isOptional(ref.Optional), field: field + ".secretKeyRef.name",
+					keys: []keyReference{{key: ref.Key, field: field + ".secretKeyRef.key"}},
+				})
+			}
+		}
+		for j, envFrom := range container.EnvFrom {
+			field := ks.FieldIndexPath(containerFields[i]+".envFrom", j)
+			if ref := envFrom.ConfigMapRef; ref != nil {
+				res = append(res, reference{kind: "ConfigMap", name: ref.Name, optional: isOptional(ref.Optional), field: field + ".configMapRef.name"})
+			}
+			if ref := envFrom.SecretRef; ref != nil {
+				res = append(res, reference{kind: "Secret", name: ref.Name, optional: isOptional(ref.Optional), field: field + ".secretRef.name"})
+			}
+		}
+	}
+
+	for i, volume := range spec.Volumes {
+		field := ks.FieldIndexPath(internal.PodSpecField(ps)+".volumes", i)
+		if cm := volume.ConfigMap; cm != nil {
+			res = append(res, volumeReference("ConfigMap", cm.Name, cm.Optional, cm.Items, field+".configMap", ".name"))
+		}
+		if secret := volume.Secret; secret != nil {
+			res = append(res, volumeReference("Secret", secret.SecretName, secret.Optional, secret.Items, field+".secret", ".secretName"))
+		}
+		if projected := volume.Projected; projected != nil {
+			for j, source := range projected.Sources {
+				sourceField := ks.FieldIndexPath(field+".projected.sources", j)
+				if cm := source.ConfigMap; cm != nil {
+					res = append(res, volumeReference("ConfigMap", cm.Name, cm.Optional, cm.Items, sourceField+".configMap", ".name"))
+				}
+				if secret := source.Secret; secret != nil {
+					res = append(res, volumeReference("Secret", secret.Name, secret.Optional, secret.Items, sourceField+".secret", ".name"))
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+// hasReferences returns true if the pod references any ConfigMaps or Secrets
+func hasReferences(ps ks.PodSpecer) bool {
+	return len(references(ps)) > 0
+}
+
+func volumeReference(kind, name string, optional *bool, items []corev1.KeyToPath, field, nameField string) reference {
+	ref := reference{kind: kind, name: name, optional: isOptional(optional), field: field + nameField}
+	for i, item := range items {
+		ref.keys = append(ref.keys, keyReference{key: item.Key, field: ks.FieldIndexPath(field+".items", i) + ".key"})
+	}
+	return ref
+}
+
+// isExternal returns true if the name is in external, on the format name or namespace/name
+func isExternal(external []string, namespace, name string) bool {
+	for _, e := range external {
+		if ns, n, ok := strings.Cut(e, "/"); ok {
+			if ns == namespace && n == name {
+				return true
+			}
+		} else if e == name {
+			return true
+		}
+	}
+	return false
+}
+
+func podReferences(all objects, external []string) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		namespace := ps.GetPodTemplateSpec().Namespace
+		for _, ref := range references(ps) {
+			// Optional references don't prevent the pod from starting, if the object or the key does not exist
+			if ref.optional || isExternal(external, namespace, ref.name) {
+				continue
+			}
+
+			keys, ok := all[objectKey{kind: ref.kind, namespace: namespace, name: ref.name}]
+			if !ok {
+				score.AddCommentWithField("", fmt.Sprintf("The %s %s does not exist", ref.kind, ref.name),
+					fmt.Sprintf("No %s named %s was found in the namespace %q, and the pod will fail to start. Set optional to true if the pod can run without it, or use --external-config-ref if it's created outside of the manifests.", ref.kind, ref.name, namespace),
+					ref.field)
+				continue
+			}
+
+			for _, key := range ref.keys {
+				if _, ok := keys[key.key]; !ok {
+					score.AddCommentWithField("", fmt.Sprintf("The %s %s does not have the key %s", ref.kind, ref.name, key.key),
+						"The pod will fail to start if the referenced key does not exist. Set optional to true if the pod can run without it.",
+						key.field)
+				}
+			}
+		}
+
+		if len(score.Comments) > 0 {
+			score.Grade = scorecard.GradeCritical
+		} else {
+			score.Grade = scorecard.GradeAllOK
+		}
+		return
+	}
+}
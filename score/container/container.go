@@ -6,6 +6,7 @@ import (
 
 	ks "github.com/zegl/kube-score/domain"
 	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/score/internal"
 	"github.com/zegl/kube-score/scorecard"
 	corev1 "k8s.io/api/core/v1"
 )
@@ -19,7 +20,8 @@ func Register(allChecks *checks.Checks, ignoreContainerCpuLimitRequirement, igno
 	allChecks.RegisterPodCheck("Container Image Pull Policy", `Makes sure that the pullPolicy is set to Always. This makes sure that imagePullSecrets are always validated.`, containerImagePullPolicy)
 	allChecks.RegisterPodCheck("Container Ephemeral Storage Request and Limit", "Makes sure all pods have ephemeral-storage requests and limits set", containerStorageEphemeralRequestAndLimit)
 	allChecks.RegisterOptionalPodCheck("Container Ephemeral Storage Request Equals Limit", "Make sure all pods have matching ephemeral-storage requests and limits", containerStorageEphemeralRequestEqualsLimit)
-	allChecks.RegisterOptionalPodCheck("Container Ports Check", "Container Ports Checks", containerPortsCheck)
+	maxPortNameLength := checks.RegisterParam(allChecks, "Container Ports Check", "maxPortNameLength", 15, "The maximum number of characters allowed in the name of a container port", checks.AtLeast(1))
+	allChecks.RegisterOptionalPodCheck("Container Ports Check", "Container Ports Checks", containerPortsCheck(maxPortNameLength))
 	allChecks.RegisterPodCheck("Environment Variable Key Duplication", "Makes sure that duplicated environment variable keys are not duplicated", environmentVariableKeyDuplication)
 }
 
@@ -35,22 +37,37 @@ func containerResources(requireCPULimit bool, requireMemoryLimit bool) func(ks.P
 		hasMissingLimit := false
 		hasMissingRequest := false
 
-		for _, container := range allContainers {
+		pointers := internal.ContainerPointers(ps)
+		fields := internal.ContainerFields(ps)
+		for i, container := range allContainers {
 			if container.Resources.Limits.Cpu().IsZero() && requireCPULimit {
-				score.AddComment(container.Name, "CPU limit is not set", "Resource limits are recommended to avoid resource DDOS. Set resources.limits.cpu")
+				score.AddCommentWithField(container.Name, "CPU limit is not set", "Resource limits are recommended to avoid resource DDOS. Set resources.limits.cpu", fields[i]+".resources.limits.cpu")
 				hasMissingLimit = true
 			}
 			if container.Resources.Limits.Memory().IsZero() && requireMemoryLimit {
-				score.AddComment(container.Name, "Memory limit is not set", "Resource limits are recommended to avoid resource DDOS. Set resources.limits.memory")
+				score.AddCommentWithField(container.Name, "Memory limit is not set", "Resource limits are recommended to avoid resource DDOS. Set resources.limits.memory", fields[i]+".resources.limits.memory")
 				hasMissingLimit = true
 			}
 			if container.Resources.Requests.Cpu().IsZero() {
-				score.AddComment(container.Name, "CPU request is not set", "Resource requests are recommended to make sure that the application can start and run without crashing. Set resources.requests.cpu")
+				score.AddCommentWithField(container.Name, "CPU request is not set", "Resource requests are recommended to make sure that the application can start and run without crashing. Set resources.requests.cpu", fields[i]+".resources.requests.cpu")
 				hasMissingRequest = true
+
+				// The limit is the only known good value of the request
+				if limit := container.Resources.Limits.Cpu(); !limit.IsZero() {
+					score.AddFix(container.Name, "Set the CPU request to the CPU limit", scorecard.PatchOperation{
+						Op: "add", Path: pointers[i] + "/resources/requests/cpu", Value: limit.String(),
+					})
+				}
 			}
 			if container.Resources.Requests.Memory().IsZero() {
-				score.AddComment(container.Name, "Memory request is not set", "Resource requests are recommended to make sure that the application can start and run without crashing. Set resources.requests.memory")
+				score.AddCommentWithField(container.Name, "Memory request is not set", "Resource requests are recommended to make sure that the application can start and run without crashing. Set resources.requests.memory", fields[i]+".resources.requests.memory")
 				hasMissingRequest = true
+
+				if limit := container.Resources.Limits.Memory(); !limit.IsZero() {
+					score.AddFix(container.Name, "Set the memory request to the memory limit", scorecard.PatchOperation{
+						Op: "add", Path: pointers[i] + "/resources/requests/memory", Value: limit.String(),
+					})
+				}
 			}
 		}
 
@@ -97,11 +114,12 @@ func containerCPURequestsEqualLimits(ps ks.PodSpecer) (score scorecard.TestScore
 
 	resourcesDoNotMatch := false
 
-	for _, container := range allContainers {
+	fields := internal.ContainerFields(ps)
+	for i, container := range allContainers {
 		requests := &container.Resources.Requests
 		limits := &container.Resources.Limits
 		if !requests.Cpu().Equal(*limits.Cpu()) {
-			score.AddComment(container.Name, "CPU requests does not match limits", "Having equal requests and limits is recommended to avoid resource DDOS of the node during spikes. Set resources.requests.cpu == resources.limits.cpu")
+			score.AddCommentWithField(container.Name, "CPU requests does not match limits", "Having equal requests and limits is recommended to avoid resource DDOS of the node during spikes. Set resources.requests.cpu == resources.limits.cpu", fields[i]+".resources.requests.cpu")
 			resourcesDoNotMatch = true
 		}
 	}
@@ -124,11 +142,12 @@ func containerMemoryRequestsEqualLimits(ps ks.PodSpecer) (score scorecard.TestSc
 
 	resourcesDoNotMatch := false
 
-	for _, container := range allContainers {
+	fields := internal.ContainerFields(ps)
+	for i, container := range allContainers {
 		requests := &container.Resources.Requests
 		limits := &container.Resources.Limits
 		if !requests.Memory().Equal(*limits.Memory()) {
-			score.AddComment(container.Name, "Memory requests does not match limits", "Having equal requests and limits is recommended to avoid resource DDOS of the node during spikes. Set resources.requests.memory == resources.limits.memory")
+			score.AddCommentWithField(container.Name, "Memory requests does not match limits", "Having equal requests and limits is recommended to avoid resource DDOS of the node during spikes. Set resources.requests.memory == resources.limits.memory", fields[i]+".resources.requests.memory")
 			resourcesDoNotMatch = true
 		}
 	}
@@ -151,10 +170,11 @@ func containerImageTag(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
 
 	hasTagLatest := false
 
-	for _, container := range allContainers {
+	fields := internal.ContainerFields(ps)
+	for i, container := range allContainers {
 		tag := containerTag(container.Image)
 		if tag == "" || tag == "latest" {
-			score.AddComment(container.Name, "Image with latest tag", "Using a fixed tag is recommended to avoid accidental upgrades")
+			score.AddCommentWithField(container.Name, "Image with latest tag", "Using a fixed tag is recommended to avoid accidental upgrades", fields[i]+".image")
 			hasTagLatest = true
 		}
 	}
@@ -178,7 +198,9 @@ func containerImagePullPolicy(ps ks.PodSpecer) (score scorecard.TestScore, err e
 	// Default to AllOK
 	score.Grade = scorecard.GradeAllOK
 
-	for _, container := range allContainers {
+	pointers := internal.ContainerPointers(ps)
+	fields := internal.ContainerFields(ps)
+	for i, container := range allContainers {
 		tag := containerTag(container.Image)
 
 		// If the pull policy is not set, and the tag is either empty or latest
@@ -189,8 +211,11 @@ func containerImagePullPolicy(ps ks.PodSpecer) (score scorecard.TestScore, err e
 
 		// No defined pull policy
 		if container.ImagePullPolicy != corev1.PullAlways || container.ImagePullPolicy == corev1.PullPolicy("") {
-			score.AddComment(container.Name, "ImagePullPolicy is not set to Always", "It's recommended to always set the ImagePullPolicy to Always, to make sure that the imagePullSecrets are always correct, and to always get the image you want.")
+			score.AddCommentWithField(container.Name, "ImagePullPolicy is not set to Always", "It's recommended to always set the ImagePullPolicy to Always, to make sure that the imagePullSecrets are always correct, and to always get the image you want.", fields[i]+".imagePullPolicy")
 			score.Grade = scorecard.GradeCritical
+			score.AddFix(container.Name, "Set imagePullPolicy to Always", scorecard.PatchOperation{
+				Op: "add", Path: pointers[i] + "/imagePullPolicy", Value: string(corev1.PullAlways),
+			})
 		}
 	}
 
@@ -217,15 +242,16 @@ func containerStorageEphemeralRequestAndLimit(ps ks.PodSpecer) (score scorecard.
 	hasMissingLimit := false
 	hasMissingRequest := false
 
-	for _, container := range allContainers {
+	fields := internal.ContainerFields(ps)
+	for i, container := range allContainers {
 		if container.Resources.Limits.StorageEphemeral().IsZero() {
-			score.AddComment(container.Name, "Ephemeral Storage limit is not set",
-				"Resource limits are recommended to avoid resource DDOS. Set resources.limits.ephemeral-storage")
+			score.AddCommentWithField(container.Name, "Ephemeral Storage limit is not set",
+				"Resource limits are recommended to avoid resource DDOS. Set resources.limits.ephemeral-storage", fields[i]+".resources.limits.ephemeral-storage")
 			hasMissingLimit = true
 		}
 		if container.Resources.Requests.StorageEphemeral().IsZero() {
-			score.AddComment(container.Name, "Ephemeral Storage request is not set",
-				"Resource requests are recommended to make sure the application can start and run without crashing. Set resource.requests.ephemeral-storage")
+			score.AddCommentWithField(container.Name, "Ephemeral Storage request is not set",
+				"Resource requests are recommended to make sure the application can start and run without crashing. Set resource.requests.ephemeral-storage", fields[i]+".resources.requests.ephemeral-storage")
 			hasMissingRequest = true
 		}
 	}
@@ -251,12 +277,13 @@ func containerStorageEphemeralRequestEqualsLimit(ps ks.PodSpecer) (score scoreca
 
 	score.Grade = scorecard.GradeAllOK
 
-	for _, container := range allContainers {
+	fields := internal.ContainerFields(ps)
+	for i, container := range allContainers {
 		if !container.Resources.Limits.StorageEphemeral().IsZero() && !container.Resources.Requests.StorageEphemeral().IsZero() {
 			requests := &container.Resources.Requests
 			limits := &container.Resources.Limits
 			if !requests.StorageEphemeral().Equal(*limits.StorageEphemeral()) {
-				score.AddComment(container.Name, "Ephemeral Storage request does not match limit", "Having equal requests and limits is recommended to avoid node resource DDOS during spikes")
+				score.AddCommentWithField(container.Name, "Ephemeral Storage request does not match limit", "Having equal requests and limits is recommended to avoid node resource DDOS during spikes", fields[i]+".resources.requests.ephemeral-storage")
 				score.Grade = scorecard.GradeCritical
 			}
 		}
@@ -268,37 +295,39 @@ func containerStorageEphemeralRequestEqualsLimit(ps ks.PodSpecer) (score scoreca
 // List of ports to expose from the container. This is primarily informational. Not specifying a port here
 // does not prevent it from being exposed. Specifying it does not expose the port outside the cluster; that
 // requires a Service object.
-func containerPortsCheck(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
-	const maxPortNameLength = 15
-
-	allContainers := ps.GetPodTemplateSpec().Spec.InitContainers
-	allContainers = append(allContainers, ps.GetPodTemplateSpec().Spec.Containers...)
+func containerPortsCheck(maxPortNameLength int) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		allContainers := ps.GetPodTemplateSpec().Spec.InitContainers
+		allContainers = append(allContainers, ps.GetPodTemplateSpec().Spec.Containers...)
 
-	score.Grade = scorecard.GradeAllOK
+		score.Grade = scorecard.GradeAllOK
 
-	for _, container := range allContainers {
-		names := make(map[string]bool)
-		for _, port := range container.Ports {
-			if len(port.Name) > 0 {
-				if _, ok := names[port.Name]; !ok {
-					names[port.Name] = true
-				} else {
-					score.AddComment(container.Name, "Container Port Check", "Container ports.containerPort named ports must be unique")
+		fields := internal.ContainerFields(ps)
+		for i, container := range allContainers {
+			names := make(map[string]bool)
+			for j, port := range container.Ports {
+				portField := ks.FieldIndexPath(fields[i]+".ports", j)
+				if len(port.Name) > 0 {
+					if _, ok := names[port.Name]; !ok {
+						names[port.Name] = true
+					} else {
+						score.AddCommentWithField(container.Name, "Container Port Check", "Container ports.containerPort named ports must be unique", portField+".name")
+						score.Grade = scorecard.GradeCritical
+					}
+				}
+				if len(port.Name) > maxPortNameLength {
+					score.AddCommentWithField(container.Name, "Container Port Check", "Container port.Name length exceeds maximum permitted characters", portField+".name")
+					score.Grade = scorecard.GradeCritical
+				}
+				if port.ContainerPort == 0 {
+					score.AddCommentWithField(container.Name, "Container Port Check", "Container ports.containerPort cannot be empty", portField+".containerPort")
 					score.Grade = scorecard.GradeCritical
 				}
-			}
-			if len(port.Name) > maxPortNameLength {
-				score.AddComment(container.Name, "Container Port Check", "Container port.Name length exceeds maximum permitted characters")
-				score.Grade = scorecard.GradeCritical
-			}
-			if port.ContainerPort == 0 {
-				score.AddComment(container.Name, "Container Port Check", "Container ports.containerPort cannot be empty")
-				score.Grade = scorecard.GradeCritical
 			}
 		}
-	}
 
-	return
+		return
+	}
 }
 
 // environmentVariableKeyDuplication checks that no duplicated environment variable keys.
@@ -310,12 +339,13 @@ func environmentVariableKeyDuplication(ps ks.PodSpecer) (score scorecard.TestSco
 
 	score.Grade = scorecard.GradeAllOK
 
-	for _, container := range allContainers {
+	fields := internal.ContainerFields(ps)
+	for i, container := range allContainers {
 		envs := make(map[string]struct{})
-		for _, env := range container.Env {
+		for j, env := range container.Env {
 			if _, duplicated := envs[env.Name]; duplicated {
 				msg := fmt.Sprintf("Container environment variable key '%s' is duplicated", env.Name)
-				score.AddComment(container.Name, "Environment Variable Key Duplication", msg)
+				score.AddCommentWithField(container.Name, "Environment Variable Key Duplication", msg, ks.FieldIndexPath(fields[i]+".env", j))
 				score.Grade = scorecard.GradeCritical
 				continue
 			}
@@ -1,6 +1,8 @@
 package cronjob
 
 import (
+	"fmt"
+
 	ks "github.com/zegl/kube-score/domain"
 	"github.com/zegl/kube-score/score/checks"
 	"github.com/zegl/kube-score/scorecard"
@@ -11,11 +13,17 @@ func Register(allChecks *checks.Checks) {
 	allChecks.RegisterCronJobCheck("CronJob RestartPolicy", `Makes sure CronJobs have a valid RestartPolicy`, cronJobHasRestartPolicy)
 }
 
+// defaultStartingDeadlineSeconds is the startingDeadlineSeconds that is suggested by the fix of cronJobHasDeadline
+const defaultStartingDeadlineSeconds = 200
+
 func cronJobHasDeadline(job ks.CronJob) (score scorecard.TestScore, err error) {
 	if job.StartingDeadlineSeconds() == nil {
 		score.Grade = scorecard.GradeCritical
 		score.AddComment("", "The CronJob should have startingDeadlineSeconds configured",
 			"This makes sure that jobs are automatically cancelled if they can not be scheduled")
+		score.AddFix("", fmt.Sprintf("Set startingDeadlineSeconds to %d", defaultStartingDeadlineSeconds), scorecard.PatchOperation{
+			Op: "add", Path: "/spec/startingDeadlineSeconds", Value: defaultStartingDeadlineSeconds,
+		})
 		return
 	}
 
@@ -0,0 +1,284 @@
+// Package custom implements user defined checks, where the logic of the check is written
+// as a CEL expression, and the checks are loaded from YAML files.
+package custom
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+
+	"cel.dev/cel-go/cel"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// FileVersion is the latest version of the custom checks file format
+const FileVersion = 1
+
+// File is the on-disk representation of a file with custom checks
+type File struct {
+	Version int         `yaml:"version"`
+	Checks  []yaml.Node `yaml:"checks"`
+}
+
+// Rule is a custom check
+type Rule struct {
+	// ID is the ID of the check, as used in the kube-score/ignore annotation and in the output
+	ID string `yaml:"id"`
+	// Name is the human friendly name of the check
+	Name string `yaml:"name"`
+	// Description is displayed in the list of checks
+	Description string `yaml:"description"`
+	// Kinds is the list of kinds that the check applies to. The check applies to all kinds if empty.
+	Kinds []string `yaml:"kinds"`
+	// Expression is a CEL expression, that must evaluate to true for the check to pass.
+	// The object is available as "object", and the pod template of objects with pods as "podTemplate".
+	Expression string `yaml:"expression"`
+	// Grade is the severity that is used if the check fails, "critical" or "warning"
+	Grade string `yaml:"grade"`
+	// Message is a text/template that is used as the summary if the check fails
+	Message string `yaml:"message"`
+	// Optional checks are only enabled if explicitly enabled
+	Optional bool `yaml:"optional"`
+
+	program cel.Program
+	message *template.Template
+	grade   scorecard.Grade
+}
+
+// messageData is the data that is available in the message template
+type messageData struct {
+	Kind       string
+	APIVersion string
+	Name       string
+	Namespace  string
+	Object     map[string]interface{}
+}
+
+var celEnv = func() *cel.Env {
+	env, err := cel.NewEnv(
+		cel.Variable("object", cel.DynType),
+		cel.Variable("podTemplate", cel.DynType),
+	)
+	if err != nil {
+		panic(err)
+	}
+	return env
+}()
+
+// LoadFile reads and compiles all custom checks in the file at path
+func LoadFile(path string) ([]*Rule, error) {
+	fp, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+	return ParseFile(path, fp)
+}
+
+// ParseFile reads and compiles all custom checks from r, name is used in error messages
+func ParseFile(name string, r io.Reader) ([]*Rule, error) {
+	var doc yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, &config.FileError{Path: name, Err: errors.New("the file is empty")}
+		}
+		return nil, &config.FileError{Path: name, Err: err}
+	}
+
+	var f File
+	if err := config.DecodeNode(name, doc.Content[0], &f); err != nil {
+		return nil, err
+	}
+	if f.Version != FileVersion {
+		return nil, &config.FileError{Path: name, Line: doc.Content[0].Line, Err: fmt.Errorf("unsupported version %d, the latest supported version is %d", f.Version, FileVersion)}
+	}
+
+	rules := make([]*Rule, 0, len(f.Checks))
+	for i := range f.Checks {
+		rule := &Rule{}
+		node := &f.Checks[i]
+		if err := config.DecodeNode(name, node, rule); err != nil {
+			return nil, err
+		}
+		if err := rule.compile(); err != nil {
+			return nil, &config.FileError{Path: name, Line: fieldLine(node, err.field), Err: fmt.Errorf("check %q: %w", rule.ID, err.err)}
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+type compileError struct {
+	field string
+	err   error
+}
+
+func (r *Rule) compile() *compileError {
+	switch {
+	case r.ID == "":
+		return &compileError{"id", errors.New("the id is not set")}
+	case r.Name == "":
+		return &compileError{"name", errors.New("the name is not set")}
+	case r.Expression == "":
+		return &compileError{"expression", errors.New("the expression is not set")}
+	}
+
+	switch sev, _ := config.ParseSeverity(r.Grade); sev {
+	case config.SeverityCritical, config.SeverityWarning:
+		r.grade = scorecard.SeverityGrade(sev)
+	case "":
+		if r.Grade != "" {
+			return &compileError{"grade", errors.New("the grade must be 'critical' or 'warning'")}
+		}
+		r.grade = scorecard.GradeCritical
+	default:
+		return &compileError{"grade", errors.New("the grade must be 'critical' or 'warning'")}
+	}
+
+	ast, iss := celEnv.Compile(r.Expression)
+	if iss.Err() != nil {
+		return &compileError{"expression", fmt.Errorf("failed to compile expression: %w", iss.Err())}
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return &compileError{"expression", fmt.Errorf("the expression must evaluate to a bool, got %s", ast.OutputType())}
+	}
+	program, err := celEnv.Program(ast)
+	if err != nil {
+		return &compileError{"expression", fmt.Errorf("failed to compile expression: %w", err)}
+	}
+	r.program = program
+
+	message := r.Message
+	if message == "" {
+		message = fmt.Sprintf("The %s check failed", r.Name)
+	}
+	tmpl, err := template.New(r.ID).Option("missingkey=zero").Parse(message)
+	if err != nil {
+		return &compileError{"message", fmt.Errorf("failed to parse message: %w", err)}
+	}
+	r.message = tmpl
+
+	return nil
+}
+
+func fieldLine(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1].Line
+		}
+	}
+	return node.Line
+}
+
+// Register registers all rules as checks. An error is returned if the ID of a rule is already in use.
+func Register(allChecks *checks.Checks, rules []*Rule) error {
+	registered := make(map[string]struct{})
+	for _, c := range allChecks.All() {
+		registered[c.ID] = struct{}{}
+	}
+
+	for _, rule := range rules {
+		if _, ok := registered[rule.ID]; ok {
+			return fmt.Errorf("the id %q of the custom check %q is already in use", rule.ID, rule.Name)
+		}
+		registered[rule.ID] = struct{}{}
+
+		targetType := "all"
+		if len(rule.Kinds) > 0 {
+			targetType = strings.Join(rule.Kinds, ", ")
+		}
+
+		allChecks.RegisterCustomMetaCheckFor(ks.Check{
+			Name:       rule.Name,
+			ID:         rule.ID,
+			TargetType: targetType,
+			Comment:    rule.Description,
+			Optional:   rule.Optional,
+		}, rule.appliesTo, rule.check)
+	}
+
+	return nil
+}
+
+// appliesTo returns true if the rule checks objects of the kind of meta, the rule is only run on these objects
+func (r *Rule) appliesTo(meta ks.BothMeta) bool {
+	if len(r.Kinds) == 0 {
+		return true
+	}
+	for _, k := range r.Kinds {
+		if k == meta.TypeMeta.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) check(meta ks.BothMeta) (score scorecard.TestScore, err error) {
+	podTemplate, err := podTemplateOf(meta)
+	if err != nil {
+		return score, err
+	}
+
+	out, _, err := r.program.Eval(map[string]interface{}{
+		"object":      meta.Object(),
+		"podTemplate": podTemplate,
+	})
+	if err != nil {
+		score.Grade = r.grade
+		score.AddComment("", "Failed to evaluate the expression", err.Error())
+		return score, nil
+	}
+
+	if passed, ok := out.Value().(bool); !ok {
+		score.Grade = r.grade
+		score.AddComment("", "Failed to evaluate the expression", fmt.Sprintf("The expression evaluated to %v, and not to a bool", out.Value()))
+		return score, nil
+	} else if passed {
+		score.Grade = scorecard.GradeAllOK
+		return score, nil
+	}
+
+	var summary bytes.Buffer
+	err = r.message.Execute(&summary, messageData{
+		Kind:       meta.TypeMeta.Kind,
+		APIVersion: meta.TypeMeta.APIVersion,
+		Name:       meta.ObjectMeta.Name,
+		Namespace:  meta.ObjectMeta.Namespace,
+		Object:     meta.Object(),
+	})
+	if err != nil {
+		summary.Reset()
+		summary.WriteString(fmt.Sprintf("The %s check failed", r.Name))
+	}
+
+	score.Grade = r.grade
+	score.AddComment("", summary.String(), r.Description)
+	return score, nil
+}
+
+// podTemplateOf returns the unstructured pod template of objects that have pods, or nil if the object has no pods
+func podTemplateOf(meta ks.BothMeta) (map[string]interface{}, error) {
+	var template corev1.PodTemplateSpec
+	switch o := meta.FileLocationer.(type) {
+	case ks.PodSpecer:
+		template = o.GetPodTemplateSpec()
+	case ks.Pod:
+		pod := o.Pod()
+		template = corev1.PodTemplateSpec{ObjectMeta: pod.ObjectMeta, Spec: pod.Spec}
+	default:
+		return nil, nil
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(&template)
+}
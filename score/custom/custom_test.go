@@ -0,0 +1,174 @@
+package custom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/parser"
+	"github.com/zegl/kube-score/score"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+type namedReader struct {
+	*strings.Reader
+	name string
+}
+
+func (n namedReader) Name() string {
+	return n.name
+}
+
+const rules = `version: 1
+checks:
+  - id: team-label
+    name: Team Label
+    description: All objects must have a team label
+    expression: has(object.metadata.labels) && "team" in object.metadata.labels
+    grade: warning
+    message: "{{ .Kind }} {{ .Name }} has no team label"
+  - id: deployment-max-replicas
+    name: Deployment Max Replicas
+    kinds: [Deployment]
+    expression: object.spec.replicas <= 10
+  - id: no-host-network
+    name: No Host Network
+    kinds: [Deployment, Pod]
+    expression: "!has(podTemplate.spec.hostNetwork) || !podTemplate.spec.hostNetwork"
+`
+
+const objects = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  labels:
+    team: foo
+spec:
+  replicas: 20
+  template:
+    metadata:
+      labels:
+        app: foo
+    spec:
+      hostNetwork: true
+      containers:
+      - name: foo
+        image: foo:1.0
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+spec:
+  selector:
+    app: foo
+`
+
+func testScore(t *testing.T, rules, objects string) scorecard.Scorecard {
+	t.Helper()
+
+	parsedRules, err := ParseFile("rules.yaml", strings.NewReader(rules))
+	assert.NoError(t, err)
+
+	p, err := parser.New(nil)
+	assert.NoError(t, err)
+	allObjects, err := p.ParseFiles([]ks.NamedReader{namedReader{Reader: strings.NewReader(objects), name: "objects.yaml"}})
+	assert.NoError(t, err)
+
+	allChecks := checks.New(nil)
+	assert.NoError(t, Register(allChecks, parsedRules))
+
+	card, err := score.Score(allObjects, allChecks, &config.RunConfiguration{UseIgnoreChecksAnnotation: true})
+	assert.NoError(t, err)
+	return *card
+}
+
+func findScore(t *testing.T, card scorecard.Scorecard, kind, checkID string) scorecard.TestScore {
+	t.Helper()
+	for _, o := range card {
+		if o.TypeMeta.Kind != kind {
+			continue
+		}
+		for _, c := range o.Checks {
+			if c.Check.ID == checkID {
+				return c
+			}
+		}
+	}
+	t.Fatalf("check %s was not run on %s", checkID, kind)
+	return scorecard.TestScore{}
+}
+
+func TestCustomChecks(t *testing.T) {
+	card := testScore(t, rules, objects)
+
+	assert.Equal(t, scorecard.GradeAllOK, findScore(t, card, "Deployment", "team-label").Grade)
+
+	svcTeam := findScore(t, card, "Service", "team-label")
+	assert.Equal(t, scorecard.GradeWarning, svcTeam.Grade)
+	assert.Equal(t, "Service svc has no team label", svcTeam.Comments[0].Summary)
+
+	replicas := findScore(t, card, "Deployment", "deployment-max-replicas")
+	assert.Equal(t, scorecard.GradeCritical, replicas.Grade)
+	assert.Equal(t, "The Deployment Max Replicas check failed", replicas.Comments[0].Summary)
+
+	// The rule only applies to Deployments
+	for _, o := range card {
+		for _, c := range o.Checks {
+			if o.TypeMeta.Kind == "Service" {
+				assert.NotEqual(t, "deployment-max-replicas", c.Check.ID)
+			}
+		}
+	}
+
+	assert.Equal(t, scorecard.GradeCritical, findScore(t, card, "Deployment", "no-host-network").Grade)
+}
+
+func TestCustomCheckIgnoreAnnotation(t *testing.T) {
+	card := testScore(t, rules, `apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+  annotations:
+    kube-score/ignore: team-label
+`)
+	assert.True(t, findScore(t, card, "Service", "team-label").Skipped)
+}
+
+func TestParseFileErrors(t *testing.T) {
+	tc := []struct {
+		input    string
+		expected string
+	}{
+		{"version: 2\n", "rules.yaml:1: unsupported version 2, the latest supported version is 1"},
+		{"version: 1\nchecks:\n  - id: a\n    name: A\n    expression: object.metadata.name ==\n", "rules.yaml:5: check \"a\": failed to compile expression"},
+		{"version: 1\nchecks:\n  - id: a\n    name: A\n    expression: object.metadata.name\n    grade: fatal\n", "rules.yaml:6: check \"a\": the grade must be 'critical' or 'warning'"},
+		{"version: 1\nchecks:\n  - id: a\n    name: A\n    expression: \"1 + 1\"\n", "rules.yaml:5: check \"a\": the expression must evaluate to a bool, got int"},
+		{"version: 1\nchecks:\n  - id: a\n    expresion: \"true\"\n", "rules.yaml:4: unknown field \"expresion\""},
+		{"version: 1\nchecks:\n  - name: A\n    expression: \"true\"\n", "rules.yaml:3: check \"\": the id is not set"},
+	}
+
+	for _, tc := range tc {
+		_, err := ParseFile("rules.yaml", strings.NewReader(tc.input))
+		if assert.Error(t, err, tc.input) {
+			assert.True(t, strings.HasPrefix(err.Error(), tc.expected), err.Error())
+		}
+	}
+}
+
+func TestRegisterDuplicateID(t *testing.T) {
+	parsedRules, err := ParseFile("rules.yaml", strings.NewReader(`version: 1
+checks:
+  - id: container-resources
+    name: My Resources
+    expression: "true"
+`))
+	assert.NoError(t, err)
+
+	allChecks := score.RegisterAllChecks(parser.Empty(), nil, nil)
+	assert.EqualError(t, Register(allChecks, parsedRules), `the id "container-resources" of the custom check "My Resources" is already in use`)
+}
@@ -11,7 +11,17 @@ import (
 	"k8s.io/utils/ptr"
 )
 
+// defaultMinReplicas is used if no minimum number of replicas is configured
+const defaultMinReplicas = 2
+
+// Register registers the Deployment checks. minReplicas is the default value of the
+// "deployment-replicas.minReplicas" parameter, if it's zero defaultMinReplicas is used.
 func Register(allChecks *checks.Checks, all ks.AllTypes, minReplicas int) {
+	if minReplicas == 0 {
+		minReplicas = defaultMinReplicas
+	}
+	minReplicas = checks.RegisterParam(allChecks, "Deployment Replicas", "minReplicas", minReplicas, "Minimum required number of replicas for a Deployment targeted by a Service", checks.AtLeast(1))
+
 	allChecks.RegisterDeploymentCheck("Deployment Strategy", `Makes sure that all Deployments targeted by service use RollingUpdate strategy`, deploymentRolloutStrategy(all.Services()))
 	allChecks.RegisterDeploymentCheck("Deployment Replicas", `Makes sure that Deployment has multiple replicas`, deploymentReplicas(all.Services(), all.HorizontalPodAutoscalers(), minReplicas))
 }
@@ -103,7 +113,7 @@ func deploymentReplicas(svcs []ks.Service, hpas []ks.HpaTargeter, minReplicas in
 				score.Grade = scorecard.GradeAllOK
 			} else {
 				score.Grade = scorecard.GradeWarning
-				score.AddComment("", "Deployment few replicas", fmt.Sprintf("Deployments targeted by Services are recommended to have at least %d replicas to prevent unwanted downtime.", minReplicas))
+				score.AddCommentWithField("", "Deployment few replicas", fmt.Sprintf("Deployments targeted by Services are recommended to have at least %d replicas to prevent unwanted downtime.", minReplicas), "spec.replicas")
 			}
 		}
 
@@ -15,6 +15,7 @@ import (
 func Register(allChecks *checks.Checks, budgets ks.PodDisruptionBudgets) {
 	allChecks.RegisterStatefulSetCheck("StatefulSet has PodDisruptionBudget", `Makes sure that all StatefulSets are targeted by a PDB`, statefulSetHas(budgets.PodDisruptionBudgets()))
 	allChecks.RegisterDeploymentCheck("Deployment has PodDisruptionBudget", `Makes sure that all Deployments are targeted by a PDB`, deploymentHas(budgets.PodDisruptionBudgets()))
+	allChecks.RegisterPodCheckFor("ReplicaSet has PodDisruptionBudget", `Makes sure that all ReplicaSets and ReplicationControllers are targeted by a PDB`, isReplicaSet, replicaSetHas(budgets.PodDisruptionBudgets()))
 	allChecks.RegisterPodDisruptionBudgetCheck("PodDisruptionBudget has policy", `Makes sure that PodDisruptionBudgets specify minAvailable or maxUnavailable`, hasPolicy)
 }
 
@@ -65,7 +66,7 @@ func statefulSetHas(budgets []ks.PodDisruptionBudget) func(appsv1.StatefulSet) (
 			score.Grade = scorecard.GradeAllOK
 		} else {
 			score.Grade = scorecard.GradeCritical
-			score.AddComment("", "No matching PodDisruptionBudget was found", "It's recommended to define a PodDisruptionBudget to avoid unexpected downtime during Kubernetes maintenance operations, such as when draining a node. "+comment)
+			score.AddCommentWithField("", "No matching PodDisruptionBudget was found", "It's recommended to define a PodDisruptionBudget to avoid unexpected downtime during Kubernetes maintenance operations, such as when draining a node. "+comment, "spec.template.metadata.labels")
 		}
 
 		return
@@ -90,7 +91,43 @@ func deploymentHas(budgets []ks.PodDisruptionBudget) func(appsv1.Deployment) (sc
 			score.Grade = scorecard.GradeAllOK
 		} else {
 			score.Grade = scorecard.GradeCritical
-			score.AddComment("", "No matching PodDisruptionBudget was found", "It's recommended to define a PodDisruptionBudget to avoid unexpected downtime during Kubernetes maintenance operations, such as when draining a node. "+comment)
+			score.AddCommentWithField("", "No matching PodDisruptionBudget was found", "It's recommended to define a PodDisruptionBudget to avoid unexpected downtime during Kubernetes maintenance operations, such as when draining a node. "+comment, "spec.template.metadata.labels")
+		}
+
+		return
+	}
+}
+
+// isReplicaSet returns true if the object is a ReplicaSet or a ReplicationController
+func isReplicaSet(ps ks.PodSpecer) bool {
+	kind := ps.GetTypeMeta().Kind
+	_, ok := ps.(ks.Replicated)
+	return ok && (kind == "ReplicaSet" || kind == "ReplicationController")
+}
+
+// replicaSetHas checks that ReplicaSets and ReplicationControllers are targeted by a PDB. They are scored as pods, and
+// the check only applies to them, see isReplicaSet.
+func replicaSetHas(budgets []ks.PodDisruptionBudget) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		kind := ps.GetTypeMeta().Kind
+		if replicas := ps.(ks.Replicated).Replicas(); replicas != nil && *replicas < 2 {
+			score.Skipped = true
+			score.AddComment("", fmt.Sprintf("Skipped because the %s has less than 2 replicas", kind), "")
+			return
+		}
+
+		template := ps.GetPodTemplateSpec()
+		match, comment, matchErr := hasMatching(budgets, template.Namespace, template.Labels)
+		if matchErr != nil {
+			err = matchErr
+			return
+		}
+
+		if match {
+			score.Grade = scorecard.GradeAllOK
+		} else {
+			score.Grade = scorecard.GradeCritical
+			score.AddCommentWithField("", "No matching PodDisruptionBudget was found", "It's recommended to define a PodDisruptionBudget to avoid unexpected downtime during Kubernetes maintenance operations, such as when draining a node. "+comment, internal.PodTemplateMetaField(ps)+".labels")
 		}
 
 		return
@@ -100,7 +137,7 @@ func deploymentHas(budgets []ks.PodDisruptionBudget) func(appsv1.Deployment) (sc
 func hasPolicy(pdb ks.PodDisruptionBudget) (score scorecard.TestScore, err error) {
 	spec := pdb.Spec()
 	if spec.MinAvailable == nil && spec.MaxUnavailable == nil {
-		score.AddComment("", "PodDisruptionBudget missing policy", "PodDisruptionBudget should specify minAvailable or maxUnavailable.")
+		score.AddCommentWithField("", "PodDisruptionBudget missing policy", "PodDisruptionBudget should specify minAvailable or maxUnavailable.", "spec")
 		score.Grade = scorecard.GradeCritical
 	} else {
 		score.Grade = scorecard.GradeAllOK
@@ -7,6 +7,7 @@ import (
 
 	"github.com/zegl/kube-score/config"
 	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
 )
 
 func TestFileLocationHelm(t *testing.T) {
@@ -32,3 +33,52 @@ func TestFileLocation(t *testing.T) {
 	assert.Equal(t, 2, sc["Deployment/apps/v1//foo"].FileLocation.Line)
 	assert.Equal(t, 12, sc["Deployment/apps/v1//foo2"].FileLocation.Line)
 }
+
+func TestCommentFieldLines(t *testing.T) {
+	t.Parallel()
+
+	tc := []struct {
+		file  string
+		check string
+		grade scorecard.Grade
+		field string
+		line  int
+	}{
+		{"pod-probes-missing-ready.yaml", "Pod Probes", scorecard.GradeCritical, "spec.containers", 8},
+		{"pod-probes-identical-http.yaml", "Pod Probes Identical", scorecard.GradeCritical, "spec.containers[0].livenessProbe", 15},
+		{"networkpolicy-deployment-not-matching-selector.yaml", "Pod NetworkPolicy", scorecard.GradeCritical, "spec.template.metadata.labels", 22},
+		{"networkpolicy-matching-only-ingress.yaml", "Pod NetworkPolicy", scorecard.GradeWarning, "metadata.labels", 24},
+		{"networkpolicy-targets-pod-not-matching.yaml", "NetworkPolicy targets Pod", scorecard.GradeCritical, "spec.podSelector", 7},
+		{"statefulset-poddisruptionbudget-v1beta1-no-match.yaml", "StatefulSet has PodDisruptionBudget", scorecard.GradeCritical, "spec.template.metadata.labels", 21},
+		{"deployment-poddisruptionbudget-v1-no-policy.yaml", "PodDisruptionBudget has policy", scorecard.GradeCritical, "spec", 5},
+		{"hpa-has-no-target.yaml", "HorizontalPodAutoscaler has target", scorecard.GradeCritical, "spec.scaleTargetRef", 7},
+		{"hpa-min-replicas-nok.yaml", "HorizontalPodAutoscaler Replicas", scorecard.GradeWarning, "spec.minReplicas", 11},
+		{"service-target-deployment-replica-1.yaml", "Deployment Replicas", scorecard.GradeWarning, "spec.replicas", 14},
+		{"deployment-host-antiaffinity-not-set.yaml", "Deployment has host PodAntiAffinity", scorecard.GradeWarning, "spec.template.spec.affinity.podAntiAffinity", 11},
+		{"statefulset-host-antiaffinity-not-set.yaml", "StatefulSet has host PodAntiAffinity", scorecard.GradeWarning, "spec.template.spec.affinity.podAntiAffinity", 14},
+		{"deployment-with-hpa-has-replicas.yaml", "Deployment targeted by HPA does not have replicas configured", scorecard.GradeCritical, "spec.replicas", 27},
+		{"statefulset-service-name-different-name.yaml", "StatefulSet has ServiceName", scorecard.GradeCritical, "spec.serviceName", 22},
+		{"statefulset-different-labels.yaml", "StatefulSet Pod Selector labels match template metadata labels", scorecard.GradeCritical, "spec.selector", 6},
+		{"pod-topology-spread-constraints-no-labelselector.yaml", "Pod Topology Spread Constraints", scorecard.GradeCritical, "spec.topologySpreadConstraints[0].labelSelector", 9},
+		{"pod-topology-spread-constraints-invalid-maxskew.yaml", "Pod Topology Spread Constraints", scorecard.GradeCritical, "spec.topologySpreadConstraints[0].maxSkew", 9},
+		{"pod-topology-spread-constraints-invalid-mindomains.yaml", "Pod Topology Spread Constraints", scorecard.GradeCritical, "spec.topologySpreadConstraints[0].minDomains", 10},
+		{"pod-topology-spread-constraints-no-topologykey.yaml", "Pod Topology Spread Constraints", scorecard.GradeCritical, "spec.topologySpreadConstraints[0].topologyKey", 9},
+		{"pod-topology-spread-constraints-invalid-whenunsatisfiable.yaml", "Pod Topology Spread Constraints", scorecard.GradeCritical, "spec.topologySpreadConstraints[0].whenUnsatisfiable", 11},
+		{"service-not-target-pod.yaml", "Service Targets Pod", scorecard.GradeCritical, "spec.selector", 17},
+		{"service-type-nodeport.yaml", "Service Type", scorecard.GradeWarning, "spec.type", 13},
+		{"ingress-networkingv1-targets-service-no-match.yaml", "Ingress targets Service", scorecard.GradeCritical, "spec.rules[0].http.paths[1].backend", 18},
+		{"deployment-extensions-v1beta1.yaml", "Stable version", scorecard.GradeWarning, "apiVersion", 1},
+	}
+
+	for _, c := range tc {
+		comments := testExpectedScoreWithConfig(t, []ks.NamedReader{testFile(c.file)}, nil, &config.RunConfiguration{
+			KubernetesVersion:     config.Semver{Major: 1, Minor: 18},
+			MinReplicasDeployment: 2,
+			MinReplicasHPA:        2,
+		}, c.check, c.grade)
+		if assert.NotEmpty(t, comments, c.file) {
+			assert.Equal(t, c.field, comments[0].Field, c.file)
+			assert.Equal(t, c.line, comments[0].Line, c.file)
+		}
+	}
+}
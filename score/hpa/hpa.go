@@ -8,7 +8,17 @@ import (
 	"k8s.io/utils/ptr"
 )
 
+// defaultMinReplicas is used if no minimum number of replicas is configured
+const defaultMinReplicas = 2
+
+// Register registers the HorizontalPodAutoscaler checks. minReplicas is the default value of the
+// "horizontalpodautoscaler-replicas.minReplicas" parameter, if it's zero defaultMinReplicas is used.
 func Register(allChecks *checks.Checks, allTargetableObjs []domain.BothMeta, minReplicas int) {
+	if minReplicas == 0 {
+		minReplicas = defaultMinReplicas
+	}
+	minReplicas = checks.RegisterParam(allChecks, "HorizontalPodAutoscaler Replicas", "minReplicas", minReplicas, "Minimum required number of replicas for a HorizontalPodAutoscaler", checks.AtLeast(1))
+
 	allChecks.RegisterHorizontalPodAutoscalerCheck("HorizontalPodAutoscaler has target", `Makes sure that the HPA targets a valid object`, hpaHasTarget(allTargetableObjs))
 	allChecks.RegisterHorizontalPodAutoscalerCheck("HorizontalPodAutoscaler Replicas", `Makes sure that the HPA has multiple replicas`, hpaHasMultipleReplicas(minReplicas))
 }
@@ -31,7 +41,7 @@ func hpaHasTarget(allTargetableObjs []domain.BothMeta) func(hpa domain.HpaTarget
 			score.Grade = scorecard.GradeAllOK
 		} else {
 			score.Grade = scorecard.GradeCritical
-			score.AddComment("", "The HPA target does not match anything", "")
+			score.AddCommentWithField("", "The HPA target does not match anything", "", "spec.scaleTargetRef")
 		}
 		return
 	}
@@ -43,7 +53,7 @@ func hpaHasMultipleReplicas(minReplicas int) func(hpa domain.HpaTargeter) (score
 			score.Grade = scorecard.GradeAllOK
 		} else {
 			score.Grade = scorecard.GradeWarning
-			score.AddComment("", "HPA few replicas", fmt.Sprintf("HorizontalPodAutoscalers are recommended to have at least %d replicas to prevent unwanted downtime.", minReplicas))
+			score.AddCommentWithField("", "HPA few replicas", fmt.Sprintf("HorizontalPodAutoscalers are recommended to have at least %d replicas to prevent unwanted downtime.", minReplicas), "spec.minReplicas")
 		}
 		return
 	}
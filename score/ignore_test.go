@@ -0,0 +1,93 @@
+package score
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func serviceTypeScores(t *testing.T, requireIgnoreReason bool) map[string]scorecard.TestScore {
+	t.Helper()
+	return serviceTypeScoresAt(t, requireIgnoreReason, time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC))
+}
+
+func serviceTypeScoresAt(t *testing.T, requireIgnoreReason bool, now time.Time) map[string]scorecard.TestScore {
+	t.Helper()
+	s, err := testScore([]ks.NamedReader{testFile("ignore-annotation-waivers.yaml")}, nil, &config.RunConfiguration{
+		UseIgnoreChecksAnnotation: true,
+		RequireIgnoreReason:       requireIgnoreReason,
+		CurrentTime:               now,
+	})
+	assert.NoError(t, err)
+
+	res := make(map[string]scorecard.TestScore)
+	for _, o := range s {
+		for _, c := range o.Checks {
+			if c.Check.ID == "service-type" {
+				res[o.ObjectMeta.Name] = c
+			}
+		}
+	}
+	return res
+}
+
+func TestIgnoreAnnotationWaivers(t *testing.T) {
+	t.Parallel()
+	scores := serviceTypeScores(t, false)
+
+	withReason := scores["with-reason"]
+	assert.True(t, withReason.Skipped)
+	assert.Equal(t, []scorecard.TestScoreComment{{
+		Summary:     "Skipped because service-type is ignored: JIRA-123",
+		Description: "The ignore expires on 2026-12-31",
+	}}, withReason.Comments)
+
+	expired := scores["expired"]
+	assert.False(t, expired.Skipped)
+	assert.Equal(t, scorecard.GradeWarning, expired.Grade)
+	assert.Equal(t, "The kube-score/ignore.service-type annotation expired on 2026-01-01, reason: JIRA-456", expired.Comments[len(expired.Comments)-1].Summary)
+
+	assert.True(t, scores["bare"].Skipped)
+
+	invalid := scores["invalid"]
+	assert.False(t, invalid.Skipped)
+	assert.Equal(t, "The kube-score/ignore.service-type annotation is invalid, until must be a date on the format YYYY-MM-DD", invalid.Comments[len(invalid.Comments)-1].Summary)
+
+	override := scores["override"]
+	assert.True(t, override.Skipped)
+	assert.Equal(t, "Skipped because service-type is ignored: The new annotation takes precedence", override.Comments[0].Summary)
+}
+
+func TestIgnoreAnnotationWaiverTimeZone(t *testing.T) {
+	t.Parallel()
+	zone := time.FixedZone("UTC-10", -10*60*60)
+
+	// The waiver is valid until the end of the day in the time zone of the current time, which is the next day in UTC
+	assert.True(t, serviceTypeScoresAt(t, false, time.Date(2026, 1, 1, 23, 30, 0, 0, zone))["expired"].Skipped)
+	assert.False(t, serviceTypeScoresAt(t, false, time.Date(2026, 1, 2, 0, 30, 0, 0, zone))["expired"].Skipped)
+}
+
+func TestIgnoreAnnotationRequireReason(t *testing.T) {
+	t.Parallel()
+	scores := serviceTypeScores(t, true)
+
+	assert.True(t, scores["with-reason"].Skipped)
+	assert.True(t, scores["override"].Skipped)
+
+	bare := scores["bare"]
+	assert.False(t, bare.Skipped)
+	assert.Equal(t, scorecard.GradeWarning, bare.Grade)
+	assert.Equal(t, `The check can not be ignored by kube-score/ignore without a reason, use kube-score/ignore.<check-id>: "reason=..."`, bare.Comments[len(bare.Comments)-1].Summary)
+}
+
+func TestIgnoreAnnotationImplied(t *testing.T) {
+	t.Parallel()
+	assert.True(t, wasSkipped(t, []ks.NamedReader{testFile("pod-ephemeral-storage-annotation-ignore-waiver.yaml")}, nil, &config.RunConfiguration{
+		UseIgnoreChecksAnnotation: true,
+	}, "Container Ephemeral Storage Request and Limit"))
+}
@@ -21,12 +21,13 @@ func ingressTargetsService(allServices []ks.Service) func(ks.Ingress) (scorecard
 func ingressTargetsServiceCommon(ingress ks.Ingress, allServices []ks.Service) (score scorecard.TestScore, err error) {
 	allRulesHaveMatches := true
 
-	for _, rule := range ingress.Rules() {
+	for i, rule := range ingress.Rules() {
 		if rule.IngressRuleValue.HTTP == nil {
 			continue
 		}
 
-		for _, path := range rule.IngressRuleValue.HTTP.Paths {
+		for j, path := range rule.IngressRuleValue.HTTP.Paths {
+			backendField := ks.FieldIndexPath(ks.FieldIndexPath("spec.rules", i)+".http.paths", j) + ".backend"
 
 			pathHasMatch := false
 
@@ -55,12 +56,12 @@ func ingressTargetsServiceCommon(ingress ks.Ingress, allServices []ks.Service) (
 				allRulesHaveMatches = false
 				if path.Backend.Service != nil {
 					if path.Backend.Service.Port.Number > 0 {
-						score.AddComment(path.Path, "No service match was found", fmt.Sprintf("No service with name %s and port number %d was found", path.Backend.Service.Name, path.Backend.Service.Port.Number))
+						score.AddCommentWithField(path.Path, "No service match was found", fmt.Sprintf("No service with name %s and port number %d was found", path.Backend.Service.Name, path.Backend.Service.Port.Number), backendField)
 					} else {
-						score.AddComment(path.Path, "No service match was found", fmt.Sprintf("No service with name %s and port named %s was found", path.Backend.Service.Name, path.Backend.Service.Port.Name))
+						score.AddCommentWithField(path.Path, "No service match was found", fmt.Sprintf("No service with name %s and port named %s was found", path.Backend.Service.Name, path.Backend.Service.Port.Name), backendField)
 					}
 				} else {
-					score.AddComment(path.Path, "No service match was found", "")
+					score.AddCommentWithField(path.Path, "No service match was found", "", backendField)
 				}
 			}
 		}
@@ -0,0 +1,55 @@
+package internal
+
+import (
+	"strings"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+// PodSpecField returns the field path to the pod spec of the PodSpecer, see domain.FieldPositions
+func PodSpecField(ps ks.PodSpecer) string {
+	if fielder, ok := ps.(ks.PodTemplateFielder); ok {
+		return fielder.PodTemplateField() + ".spec"
+	}
+	switch ps.GetTypeMeta().Kind {
+	case "Pod":
+		return "spec"
+	case "CronJob":
+		return "spec.jobTemplate.spec.template.spec"
+	default:
+		return "spec.template.spec"
+	}
+}
+
+// PodTemplateMetaField returns the field path to the metadata of the pod template of the PodSpecer
+func PodTemplateMetaField(ps ks.PodSpecer) string {
+	spec := PodSpecField(ps)
+	if spec == "spec" {
+		return "metadata"
+	}
+	return strings.TrimSuffix(spec, ".spec") + ".metadata"
+}
+
+// SelectorField returns the field path to the label selector of a ks.Replicated PodSpecer
+func SelectorField(ps ks.PodSpecer) string {
+	if fielder, ok := ps.(ks.SelectorFielder); ok {
+		return fielder.SelectorField()
+	}
+	return "spec.selector"
+}
+
+// ContainerFields returns the field paths to the containers of the pod, in the same order as the
+// InitContainers followed by the Containers
+func ContainerFields(ps ks.PodSpecer) []string {
+	spec := ps.GetPodTemplateSpec().Spec
+	prefix := PodSpecField(ps)
+
+	res := make([]string, 0, len(spec.InitContainers)+len(spec.Containers))
+	for i := range spec.InitContainers {
+		res = append(res, ks.FieldIndexPath(prefix+".initContainers", i))
+	}
+	for i := range spec.Containers {
+		res = append(res, ks.FieldIndexPath(prefix+".containers", i))
+	}
+	return res
+}
@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+// PodSpecPointer returns the JSON pointer to the pod spec of the PodSpecer
+func PodSpecPointer(ps ks.PodSpecer) string {
+	if fielder, ok := ps.(ks.PodTemplateFielder); ok {
+		var res string
+		for _, key := range strings.Split(fielder.PodTemplateField(), ".") {
+			res += "/" + EscapePointer(key)
+		}
+		return res + "/spec"
+	}
+	switch ps.GetTypeMeta().Kind {
+	case "Pod":
+		return "/spec"
+	case "CronJob":
+		return "/spec/jobTemplate/spec/template/spec"
+	default:
+		return "/spec/template/spec"
+	}
+}
+
+// ContainerPointers returns the JSON pointers to the containers of the pod, in the same order as the
+// InitContainers followed by the Containers
+func ContainerPointers(ps ks.PodSpecer) []string {
+	spec := ps.GetPodTemplateSpec().Spec
+	prefix := PodSpecPointer(ps)
+
+	res := make([]string, 0, len(spec.InitContainers)+len(spec.Containers))
+	for i := range spec.InitContainers {
+		res = append(res, fmt.Sprintf("%s/initContainers/%d", prefix, i))
+	}
+	for i := range spec.Containers {
+		res = append(res, fmt.Sprintf("%s/containers/%d", prefix, i))
+	}
+	return res
+}
+
+// EscapePointer escapes a key to be used as a segment of a JSON pointer
+func EscapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
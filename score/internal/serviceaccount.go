@@ -0,0 +1,48 @@
+package internal
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+)
+
+// ServiceAccountName returns the name of the ServiceAccount that the pod runs as, which defaults to "default"
+func ServiceAccountName(spec corev1.PodSpec) string {
+	if spec.ServiceAccountName != "" {
+		return spec.ServiceAccountName
+	}
+	if spec.DeprecatedServiceAccount != "" {
+		return spec.DeprecatedServiceAccount
+	}
+	return "default"
+}
+
+// BindsServiceAccount returns true if the binding grants its role to the ServiceAccount, directly or by a group of
+// ServiceAccounts. A RoleBinding only grants its role in its own namespace.
+func BindsServiceAccount(binding ks.RoleBinding, name, namespace string) bool {
+	if binding.GetTypeMeta().Kind == "RoleBinding" && binding.GetObjectMeta().Namespace != namespace {
+		return false
+	}
+	for _, subject := range binding.Subjects() {
+		switch subject.Kind {
+		case rbacv1.ServiceAccountKind:
+			if subject.Name == name && SubjectNamespace(binding, subject) == namespace {
+				return true
+			}
+		case rbacv1.GroupKind:
+			if subject.Name == "system:serviceaccounts" || subject.Name == "system:serviceaccounts:"+namespace {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SubjectNamespace returns the namespace of the subject, which defaults to the namespace of a RoleBinding
+func SubjectNamespace(binding ks.RoleBinding, subject rbacv1.Subject) string {
+	if subject.Namespace == "" && binding.GetTypeMeta().Kind == "RoleBinding" {
+		return binding.GetObjectMeta().Namespace
+	}
+	return subject.Namespace
+}
@@ -0,0 +1,33 @@
+package meta
+
+import (
+	"fmt"
+
+	"github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// isStrict returns true if the object is validated against the schema of its kind, see --strict
+func isStrict(meta domain.BothMeta) bool {
+	return meta.Strict
+}
+
+func validateUnknownFields(meta domain.BothMeta) (score scorecard.TestScore, err error) {
+	score.Grade = scorecard.GradeAllOK
+	for _, field := range meta.UnknownFields {
+		score.Grade = scorecard.GradeCritical
+		if field.Duplicate {
+			score.AddCommentWithField(field.Path, "Duplicate field",
+				"The field is set more than once, only one of the values is used", field.Path)
+			continue
+		}
+
+		summary := "Unknown field"
+		if field.Suggestion != "" {
+			summary = fmt.Sprintf("Unknown field, did you mean %s?", field.Suggestion)
+		}
+		score.AddCommentWithField(field.Path, summary,
+			fmt.Sprintf("The field is not a part of %s %s, and is ignored or rejected by Kubernetes", meta.TypeMeta.APIVersion, meta.TypeMeta.Kind), field.Path)
+	}
+	return
+}
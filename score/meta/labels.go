@@ -10,6 +10,7 @@ import (
 
 func Register(allChecks *checks.Checks) {
 	allChecks.RegisterMetaCheck("Label values", "Validates label values", validateLabelValues)
+	allChecks.RegisterMetaCheckFor("Unknown field", "Makes sure that objects have no unknown or duplicate fields, when running with --strict", isStrict, validateUnknownFields)
 }
 
 func validateLabelValues(meta domain.BothMeta) (score scorecard.TestScore, err error) {
@@ -64,18 +64,21 @@ func podHasNetworkPolicy(allNetpols []ks.NetworkPolicy) func(ks.PodSpecer) (scor
 			}
 		}
 
+		// The NetworkPolicies select the pod by the labels of its template
+		labelsField := internal.PodTemplateMetaField(ps) + ".labels"
+
 		switch {
 		case hasMatchingEgressNetpol && hasMatchingIngressNetpol:
 			score.Grade = scorecard.GradeAllOK
 		case hasMatchingEgressNetpol && !hasMatchingIngressNetpol:
 			score.Grade = scorecard.GradeWarning
-			score.AddComment("", "The pod does not have a matching ingress NetworkPolicy", "Add a ingress policy to the pods NetworkPolicy")
+			score.AddCommentWithField("", "The pod does not have a matching ingress NetworkPolicy", "Add a ingress policy to the pods NetworkPolicy", labelsField)
 		case hasMatchingIngressNetpol && !hasMatchingEgressNetpol:
 			score.Grade = scorecard.GradeWarning
-			score.AddComment("", "The pod does not have a matching egress NetworkPolicy", "Add a egress policy to the pods NetworkPolicy")
+			score.AddCommentWithField("", "The pod does not have a matching egress NetworkPolicy", "Add a egress policy to the pods NetworkPolicy", labelsField)
 		default:
 			score.Grade = scorecard.GradeCritical
-			score.AddComment("", "The pod does not have a matching NetworkPolicy", "Create a NetworkPolicy that targets this pod to control who/what can communicate with this pod. Note, this feature needs to be supported by the CNI implementation used in the Kubernetes cluster to have an effect.")
+			score.AddCommentWithField("", "The pod does not have a matching NetworkPolicy", "Create a NetworkPolicy that targets this pod to control who/what can communicate with this pod. Note, this feature needs to be supported by the CNI implementation used in the Kubernetes cluster to have an effect.", labelsField)
 		}
 
 		return
@@ -119,7 +122,7 @@ func networkPolicyTargetsPod(pods []ks.Pod, podspecers []ks.PodSpecer) func(netw
 			score.Grade = scorecard.GradeAllOK
 		} else {
 			score.Grade = scorecard.GradeCritical
-			score.AddComment("", "The NetworkPolicys selector doesn't match any pods", "")
+			score.AddCommentWithField("", "The NetworkPolicys selector doesn't match any pods", "", "spec.podSelector")
 		}
 
 		return
@@ -0,0 +1,21 @@
+package parseerror
+
+import (
+	"fmt"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func Register(allChecks *checks.Checks) {
+	allChecks.RegisterParseErrorCheck("Parse error", `Reports the documents that could not be parsed, when running with --continue-on-parse-error`, parseError)
+}
+
+// parseError fails for all documents that could not be parsed
+func parseError(p ks.ParseError) (score scorecard.TestScore, err error) {
+	location := p.FileLocation()
+	score.Grade = scorecard.GradeCritical
+	score.AddComment("", fmt.Sprintf("Failed to parse %s:%d", location.Name, location.Line), p.Err.Error())
+	return
+}
@@ -0,0 +1,372 @@
+// Package plugin implements checks that are distributed as WebAssembly modules, and that are run in a sandbox
+// by an embedded WebAssembly runtime.
+//
+// A plugin is a WASI (wasip1) reactor module, that exports the following functions:
+//
+//	kube_score_malloc(size i32) i32             Allocates a buffer of size bytes, and returns a pointer to it
+//	kube_score_free(ptr i32)                    Frees a buffer allocated by the plugin
+//	kube_score_metadata() i64                   Returns the metadata of the checks in the plugin
+//	kube_score_check(ptr i32, size i32) i64     Runs a check, the input is written to a buffer from kube_score_malloc
+//
+// The functions returning i64 return a buffer allocated by the plugin, with the pointer in the upper 32 bits, and the
+// size in the lower 32 bits. kube-score frees all buffers after use. All data is JSON encoded, see Metadata, request
+// and result for the format.
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// ABIVersion is the latest version of the plugin interface
+const ABIVersion = 1
+
+const (
+	mallocFunction   = "kube_score_malloc"
+	freeFunction     = "kube_score_free"
+	metadataFunction = "kube_score_metadata"
+	checkFunction    = "kube_score_check"
+)
+
+const (
+	// DefaultMemoryLimitMiB is the default limit of the memory of a plugin
+	DefaultMemoryLimitMiB = 128
+	// DefaultTimeout is the default limit of the time that a plugin can spend on a single call
+	DefaultTimeout = 10 * time.Second
+)
+
+// Options are the sandbox limits of a plugin
+type Options struct {
+	// MemoryLimitMiB is the maximum size of the memory of the plugin, DefaultMemoryLimitMiB is used if 0
+	MemoryLimitMiB int
+	// Timeout is the maximum duration of a single call to the plugin, DefaultTimeout is used if 0
+	Timeout time.Duration
+	// Stderr receives the stderr output of the plugin, the output is discarded if nil
+	Stderr io.Writer
+}
+
+// Metadata is returned by kube_score_metadata
+type Metadata struct {
+	Version int     `json:"version"`
+	Checks  []Check `json:"checks"`
+}
+
+// Check is the metadata of a check in a plugin
+type Check struct {
+	// ID is the ID of the check, as used in the kube-score/ignore annotation and in the output
+	ID string `json:"id"`
+	// Name is the human friendly name of the check
+	Name string `json:"name"`
+	// Description is displayed in the list of checks
+	Description string `json:"description"`
+	// Kinds is the list of kinds that the check applies to. The check applies to all kinds if empty.
+	Kinds []string `json:"kinds"`
+	// Optional checks are only enabled if explicitly enabled
+	Optional bool `json:"optional"`
+}
+
+// request is the input to kube_score_check
+type request struct {
+	Check  string                 `json:"check"`
+	Object map[string]interface{} `json:"object"`
+}
+
+// result is returned by kube_score_check
+type result struct {
+	// Grade is "critical", "warning", "info" or "ok"
+	Grade    string `json:"grade"`
+	Skipped  bool   `json:"skipped"`
+	Comments []struct {
+		Path        string `json:"path"`
+		Summary     string `json:"summary"`
+		Description string `json:"description"`
+	} `json:"comments"`
+}
+
+// Plugin is a loaded plugin
+type Plugin struct {
+	Path   string
+	Checks []Check
+
+	opts     Options
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+
+	// mu protects module, the plugin is called by one check at a time
+	mu     sync.Mutex
+	module api.Module
+}
+
+// Load compiles the plugin at path, and reads the metadata of its checks
+func Load(path string, opts Options) (*Plugin, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load plugin: %w", err)
+	}
+
+	p, err := load(path, raw, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load plugin %s: %w", path, err)
+	}
+	return p, nil
+}
+
+func load(path string, raw []byte, opts Options) (*Plugin, error) {
+	if opts.MemoryLimitMiB <= 0 {
+		opts.MemoryLimitMiB = DefaultMemoryLimitMiB
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.Stderr == nil {
+		opts.Stderr = io.Discard
+	}
+
+	ctx := context.Background()
+
+	// A WebAssembly page is 64 KiB
+	runtimeConfig := wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(uint32(opts.MemoryLimitMiB) * 16).
+		WithCloseOnContextDone(true)
+
+	p := &Plugin{
+		Path:    path,
+		opts:    opts,
+		runtime: wazero.NewRuntimeWithConfig(ctx, runtimeConfig),
+	}
+
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, p.runtime); err != nil {
+		_ = p.Close()
+		return nil, err
+	}
+
+	compiled, err := p.runtime.CompileModule(ctx, raw)
+	if err != nil {
+		_ = p.Close()
+		return nil, err
+	}
+	p.compiled = compiled
+
+	exported := compiled.ExportedFunctions()
+	for _, name := range []string{mallocFunction, freeFunction, metadataFunction, checkFunction} {
+		if _, ok := exported[name]; !ok {
+			_ = p.Close()
+			return nil, fmt.Errorf("the function %s is not exported", name)
+		}
+	}
+
+	out, err := p.call(metadataFunction, nil)
+	if err != nil {
+		_ = p.Close()
+		return nil, err
+	}
+
+	var metadata Metadata
+	if err := json.Unmarshal(out, &metadata); err != nil {
+		_ = p.Close()
+		return nil, fmt.Errorf("invalid metadata: %w", err)
+	}
+	if metadata.Version != ABIVersion {
+		_ = p.Close()
+		return nil, fmt.Errorf("unsupported version %d, the latest supported version is %d", metadata.Version, ABIVersion)
+	}
+	for _, c := range metadata.Checks {
+		if c.ID == "" || c.Name == "" {
+			_ = p.Close()
+			return nil, errors.New("invalid metadata: all checks must have an id and a name")
+		}
+	}
+	p.Checks = metadata.Checks
+
+	return p, nil
+}
+
+// Close releases all resources used by the plugin
+func (p *Plugin) Close() error {
+	return p.runtime.Close(context.Background())
+}
+
+// call calls fn, with input written to a buffer in the memory of the plugin, and returns the output of fn.
+// The plugin is instantiated on the first call, and re-instantiated after calls that failed.
+func (p *Plugin) call(fn string, input []byte) ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.opts.Timeout)
+	defer cancel()
+
+	out, err := p.callModule(ctx, fn, input)
+	if err != nil {
+		// The state of the module is unknown after a failure, start over with a new instance
+		if p.module != nil {
+			_ = p.module.Close(context.Background())
+			p.module = nil
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%s did not finish within %s", fn, p.opts.Timeout)
+		}
+		return nil, fmt.Errorf("%s failed: %w", fn, err)
+	}
+	return out, nil
+}
+
+func (p *Plugin) callModule(ctx context.Context, fn string, input []byte) ([]byte, error) {
+	if p.module == nil {
+		moduleConfig := wazero.NewModuleConfig().
+			WithName("").
+			WithStartFunctions("_initialize").
+			WithStderr(p.opts.Stderr)
+		module, err := p.runtime.InstantiateModule(ctx, p.compiled, moduleConfig)
+		if err != nil {
+			return nil, err
+		}
+		p.module = module
+	}
+
+	var params []uint64
+	if input != nil {
+		res, err := p.module.ExportedFunction(mallocFunction).Call(ctx, uint64(len(input)))
+		if err != nil {
+			return nil, err
+		}
+		ptr := uint32(res[0])
+		if !p.module.Memory().Write(ptr, input) {
+			return nil, fmt.Errorf("%s returned a buffer that is out of range", mallocFunction)
+		}
+		params = []uint64{uint64(ptr), uint64(len(input))}
+	}
+
+	res, err := p.module.ExportedFunction(fn).Call(ctx, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	if input != nil {
+		if _, err := p.module.ExportedFunction(freeFunction).Call(ctx, params[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	ptr, size := uint32(res[0]>>32), uint32(res[0])
+	buf, ok := p.module.Memory().Read(ptr, size)
+	if !ok {
+		return nil, errors.New("the output is out of range")
+	}
+	// buf is a view of the memory of the module, and must be copied before the buffer is freed
+	out := make([]byte, len(buf))
+	copy(out, buf)
+
+	if _, err := p.module.ExportedFunction(freeFunction).Call(ctx, uint64(ptr)); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Register registers the checks of all plugins. An error is returned if the ID of a check is already in use.
+func Register(allChecks *checks.Checks, plugins []*Plugin) error {
+	registered := make(map[string]struct{})
+	for _, c := range allChecks.All() {
+		registered[c.ID] = struct{}{}
+	}
+
+	for _, p := range plugins {
+		for _, c := range p.Checks {
+			if _, ok := registered[c.ID]; ok {
+				return fmt.Errorf("the id %q of the check %q in the plugin %s is already in use", c.ID, c.Name, p.Path)
+			}
+			registered[c.ID] = struct{}{}
+
+			targetType := "all"
+			if len(c.Kinds) > 0 {
+				targetType = strings.Join(c.Kinds, ", ")
+			}
+
+			allChecks.RegisterCustomMetaCheckFor(ks.Check{
+				Name:       c.Name,
+				ID:         c.ID,
+				TargetType: targetType,
+				Comment:    c.Description,
+				Optional:   c.Optional,
+			}, c.appliesTo, p.check(c))
+		}
+	}
+
+	return nil
+}
+
+// appliesTo returns true if the check is run on objects of the kind of meta
+func (c Check) appliesTo(meta ks.BothMeta) bool {
+	if len(c.Kinds) == 0 {
+		return true
+	}
+	for _, k := range c.Kinds {
+		if k == meta.TypeMeta.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Plugin) check(c Check) checks.CheckFunc[ks.BothMeta] {
+	return func(meta ks.BothMeta) (score scorecard.TestScore, err error) {
+		res, err := p.run(c, meta)
+		if err != nil {
+			score.Grade = scorecard.GradeCritical
+			score.AddComment("", "Failed to run the plugin", err.Error())
+			return score, nil
+		}
+
+		if res.Skipped {
+			score.Skipped = true
+		} else {
+			severity, err := config.ParseSeverity(res.Grade)
+			if err != nil {
+				score.Grade = scorecard.GradeCritical
+				score.AddComment("", "Failed to run the plugin", fmt.Sprintf("Invalid grade %q: %s", res.Grade, err))
+				return score, nil
+			}
+			score.Grade = scorecard.SeverityGrade(severity)
+		}
+
+		for _, comment := range res.Comments {
+			score.AddComment(comment.Path, comment.Summary, comment.Description)
+		}
+		return score, nil
+	}
+}
+
+func (p *Plugin) run(c Check, meta ks.BothMeta) (result, error) {
+	var res result
+
+	input, err := json.Marshal(request{Check: c.ID, Object: meta.Object()})
+	if err != nil {
+		return res, err
+	}
+
+	out, err := p.call(checkFunction, input)
+	if err != nil {
+		return res, err
+	}
+
+	if err := json.Unmarshal(out, &res); err != nil {
+		return res, fmt.Errorf("invalid output: %w", err)
+	}
+	return res, nil
+}
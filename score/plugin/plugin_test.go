@@ -0,0 +1,165 @@
+package plugin
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/parser"
+	"github.com/zegl/kube-score/score"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+type namedReader struct {
+	*strings.Reader
+	name string
+}
+
+func (n namedReader) Name() string {
+	return n.name
+}
+
+// buildPlugin compiles the example plugin in testdata/plugin
+func buildPlugin(t *testing.T) string {
+	t.Helper()
+
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is not installed, can not build the plugin")
+	}
+
+	out := filepath.Join(t.TempDir(), "plugin.wasm")
+	cmd := exec.Command(gobin, "build", "-buildmode=c-shared", "-o", out, ".")
+	cmd.Dir = filepath.Join("testdata", "plugin")
+	cmd.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to build plugin: %s: %s", err, output)
+	}
+	return out
+}
+
+func testScore(t *testing.T, plugins []*Plugin, objects string) scorecard.Scorecard {
+	t.Helper()
+
+	p, err := parser.New(nil)
+	assert.NoError(t, err)
+	allObjects, err := p.ParseFiles([]ks.NamedReader{namedReader{Reader: strings.NewReader(objects), name: "objects.yaml"}})
+	assert.NoError(t, err)
+
+	allChecks := checks.New(nil)
+	assert.NoError(t, Register(allChecks, plugins))
+
+	card, err := score.Score(allObjects, allChecks, &config.RunConfiguration{
+		UseIgnoreChecksAnnotation: true,
+		EnabledOptionalTests:      map[string]struct{}{"misbehaving": {}},
+	})
+	assert.NoError(t, err)
+	return *card
+}
+
+func findScore(t *testing.T, card scorecard.Scorecard, name, checkID string) scorecard.TestScore {
+	t.Helper()
+	for _, o := range card {
+		if o.ObjectMeta.Name != name {
+			continue
+		}
+		for _, c := range o.Checks {
+			if c.Check.ID == checkID {
+				return c
+			}
+		}
+	}
+	t.Fatalf("check %s was not run on %s", checkID, name)
+	return scorecard.TestScore{}
+}
+
+func deployment(name string, replicas int) string {
+	return strings.NewReplacer("NAME", name, "REPLICAS", strconv.Itoa(replicas)).Replace(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: NAME
+spec:
+  replicas: REPLICAS
+  template:
+    spec:
+      containers:
+      - name: foo
+        image: foo:1.0
+---
+`)
+}
+
+func TestPlugin(t *testing.T) {
+	p, err := Load(buildPlugin(t), Options{Timeout: time.Second, MemoryLimitMiB: 64})
+	assert.NoError(t, err)
+	defer p.Close()
+
+	assert.Equal(t, []Check{
+		{ID: "max-replicas", Name: "Max Replicas", Description: "Deployments must not have more than 10 replicas", Kinds: []string{"Deployment"}},
+		{ID: "misbehaving", Name: "Misbehaving", Optional: true},
+	}, p.Checks)
+
+	card := testScore(t, []*Plugin{p}, deployment("small", 1)+deployment("large", 11)+deployment("loop", 1)+deployment("alloc", 1)+deployment("garbage", 1)+`apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+`)
+
+	assert.Equal(t, scorecard.GradeAllOK, findScore(t, card, "small", "max-replicas").Grade)
+
+	large := findScore(t, card, "large", "max-replicas")
+	assert.Equal(t, scorecard.GradeCritical, large.Grade)
+	assert.Equal(t, []scorecard.TestScoreComment{{Path: "spec.replicas", Summary: "The deployment has 11 replicas"}}, large.Comments)
+
+	// The check only applies to Deployments
+	for _, o := range card {
+		for _, c := range o.Checks {
+			if o.ObjectMeta.Name == "svc" {
+				assert.NotEqual(t, "max-replicas", c.Check.ID)
+			}
+		}
+	}
+	assert.Equal(t, scorecard.GradeWarning, findScore(t, card, "svc", "misbehaving").Grade)
+
+	loop := findScore(t, card, "loop", "misbehaving")
+	assert.Equal(t, scorecard.GradeCritical, loop.Grade)
+	assert.Equal(t, "kube_score_check did not finish within 1s", loop.Comments[0].Description)
+
+	alloc := findScore(t, card, "alloc", "misbehaving")
+	assert.Equal(t, scorecard.GradeCritical, alloc.Grade)
+	assert.Equal(t, "Failed to run the plugin", alloc.Comments[0].Summary)
+
+	garbage := findScore(t, card, "garbage", "misbehaving")
+	assert.Equal(t, scorecard.GradeCritical, garbage.Grade)
+	assert.Contains(t, garbage.Comments[0].Description, "invalid output")
+
+	// The plugin is re-instantiated after failures
+	card = testScore(t, []*Plugin{p}, deployment("after", 20))
+	assert.Equal(t, scorecard.GradeCritical, findScore(t, card, "after", "max-replicas").Grade)
+	assert.Equal(t, scorecard.GradeWarning, findScore(t, card, "after", "misbehaving").Grade)
+}
+
+func TestLoadErrors(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing.wasm"), Options{})
+	assert.ErrorContains(t, err, "failed to load plugin")
+
+	path := filepath.Join(t.TempDir(), "invalid.wasm")
+	assert.NoError(t, os.WriteFile(path, []byte("not wasm"), 0o600))
+	_, err = Load(path, Options{})
+	assert.ErrorContains(t, err, "failed to load plugin "+path)
+
+	// A module without any exports
+	path = filepath.Join(t.TempDir(), "empty.wasm")
+	assert.NoError(t, os.WriteFile(path, []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}, 0o600))
+	_, err = Load(path, Options{})
+	assert.EqualError(t, err, "failed to load plugin "+path+": the function kube_score_malloc is not exported")
+}
@@ -0,0 +1,120 @@
+// This is an example plugin that is used by the tests, build it with:
+//
+//	GOOS=wasip1 GOARCH=wasm go build -buildmode=c-shared -o plugin.wasm .
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"unsafe"
+)
+
+// buffers keeps all buffers that are shared with kube-score alive, until they are freed
+var buffers = map[uint32][]byte{}
+
+//go:wasmexport kube_score_malloc
+func malloc(size uint32) uint32 {
+	return share(make([]byte, size))
+}
+
+//go:wasmexport kube_score_free
+func free(ptr uint32) {
+	delete(buffers, ptr)
+}
+
+func share(b []byte) uint32 {
+	if len(b) == 0 {
+		b = make([]byte, 1)[:0]
+	}
+	ptr := uint32(uintptr(unsafe.Pointer(unsafe.SliceData(b[:1]))))
+	buffers[ptr] = b
+	return ptr
+}
+
+func output(v any) uint64 {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return uint64(share(b))<<32 | uint64(len(b))
+}
+
+type checkMetadata struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Kinds       []string `json:"kinds,omitempty"`
+	Optional    bool     `json:"optional,omitempty"`
+}
+
+type comment struct {
+	Path        string `json:"path,omitempty"`
+	Summary     string `json:"summary"`
+	Description string `json:"description,omitempty"`
+}
+
+type result struct {
+	Grade    string    `json:"grade"`
+	Comments []comment `json:"comments,omitempty"`
+}
+
+//go:wasmexport kube_score_metadata
+func metadata() uint64 {
+	return output(map[string]any{
+		"version": 1,
+		"checks": []checkMetadata{
+			{ID: "max-replicas", Name: "Max Replicas", Description: "Deployments must not have more than 10 replicas", Kinds: []string{"Deployment"}},
+			{ID: "misbehaving", Name: "Misbehaving", Optional: true},
+		},
+	})
+}
+
+type object struct {
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Spec struct {
+		Replicas int `json:"replicas"`
+	} `json:"spec"`
+}
+
+var leak [][]byte
+
+//go:wasmexport kube_score_check
+func score(ptr, size uint32) uint64 {
+	var req struct {
+		Check  string `json:"check"`
+		Object object `json:"object"`
+	}
+	if err := json.Unmarshal(buffers[ptr][:size], &req); err != nil {
+		panic(err)
+	}
+
+	switch req.Check {
+	case "max-replicas":
+		if req.Object.Spec.Replicas > 10 {
+			return output(result{Grade: "critical", Comments: []comment{{
+				Path:    "spec.replicas",
+				Summary: fmt.Sprintf("The deployment has %d replicas", req.Object.Spec.Replicas),
+			}}})
+		}
+		return output(result{Grade: "ok"})
+	case "misbehaving":
+		switch req.Object.Metadata.Name {
+		case "loop":
+			for {
+			}
+		case "alloc":
+			for {
+				leak = append(leak, make([]byte, 1<<20))
+			}
+		case "garbage":
+			b := []byte("not json")
+			return uint64(share(b))<<32 | uint64(len(b))
+		}
+		return output(result{Grade: "warning", Comments: []comment{{Summary: "Warning from a plugin"}}})
+	}
+	panic("unknown check " + req.Check)
+}
+
+func main() {}
@@ -72,6 +72,9 @@ func TestDeploymentPodDisruptionBudgetV1NoMatch(t *testing.T) {
 			Path:        "",
 			Summary:     "No matching PodDisruptionBudget was found",
 			Description: "It's recommended to define a PodDisruptionBudget to avoid unexpected downtime during Kubernetes maintenance operations, such as when draining a node. ",
+			Field:       "spec.template.metadata.labels",
+			Line:        18,
+			Column:      7,
 		},
 	}
 
@@ -88,6 +91,9 @@ func TestDeploymentPodDisruptionBudgetV1NoMatchMatchInOtherNamespace(t *testing.
 			Path:        "",
 			Summary:     "No matching PodDisruptionBudget was found",
 			Description: "It's recommended to define a PodDisruptionBudget to avoid unexpected downtime during Kubernetes maintenance operations, such as when draining a node. A matching budget was found, but in a different namespace. expected='foo' got='[not-foo bar]'",
+			Field:       "spec.template.metadata.labels",
+			Line:        42,
+			Column:      7,
 		},
 	}
 
@@ -3,6 +3,7 @@ package podtopologyspreadconstraints
 import (
 	ks "github.com/zegl/kube-score/domain"
 	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/score/internal"
 	"github.com/zegl/kube-score/scorecard"
 )
 
@@ -12,46 +13,49 @@ func Register(allChecks *checks.Checks) {
 
 func podTopologySpreadConstraints(pod ks.PodSpecer) (score scorecard.TestScore, err error) {
 	spreads := pod.GetPodTemplateSpec().Spec.TopologySpreadConstraints
+	spreadsField := internal.PodSpecField(pod) + ".topologySpreadConstraints"
 
 	if spreads == nil {
 		score.Grade = scorecard.GradeAllOK
-		score.AddComment("", "Pod Topology Spread Constraints", "No Pod Topology Spread Constraints set, kube-scheduler defaults assumed")
+		score.AddCommentWithField("", "Pod Topology Spread Constraints", "No Pod Topology Spread Constraints set, kube-scheduler defaults assumed", spreadsField)
 		return
 	}
 
-	for _, spread := range spreads {
+	for i, spread := range spreads {
+		field := ks.FieldIndexPath(spreadsField, i)
+
 		if spread.LabelSelector == nil {
 			score.Grade = scorecard.GradeCritical
-			score.AddComment("", "Pod Topology Spread Constraints", "No labelSelector detected. A label selector is needed determine the number of pods in a topology domain")
+			score.AddCommentWithField("", "Pod Topology Spread Constraints", "No labelSelector detected. A label selector is needed determine the number of pods in a topology domain", field+".labelSelector")
 			return
 		}
 
 		if spread.MaxSkew == 0 {
 			score.Grade = scorecard.GradeCritical
-			score.AddComment("", "Pod Topology Spread Constraints", "MaxSkew is set to zero. This is not allowed.")
+			score.AddCommentWithField("", "Pod Topology Spread Constraints", "MaxSkew is set to zero. This is not allowed.", field+".maxSkew")
 			return
 		}
 
 		if spread.MinDomains != nil && *spread.MinDomains == 0 {
 			score.Grade = scorecard.GradeCritical
-			score.AddComment("", "Pod Topology Spread Constraints", "MaxDomain set to zero. This is not allowed. Constraint behaves if minDomains is set to 1 if nil")
+			score.AddCommentWithField("", "Pod Topology Spread Constraints", "MaxDomain set to zero. This is not allowed. Constraint behaves if minDomains is set to 1 if nil", field+".minDomains")
 			return
 		}
 
 		if spread.TopologyKey == "" {
 			score.Grade = scorecard.GradeCritical
-			score.AddComment("", "Pod Topology Spread Constraints", "TopologyKey is not set. This is the key of node labels used to bucket nodes into a domain")
+			score.AddCommentWithField("", "Pod Topology Spread Constraints", "TopologyKey is not set. This is the key of node labels used to bucket nodes into a domain", field+".topologyKey")
 			return
 		}
 
 		if spread.WhenUnsatisfiable != "DoNotSchedule" && spread.WhenUnsatisfiable != "ScheduleAnyway" {
 			score.Grade = scorecard.GradeCritical
-			score.AddComment("", "Pod Topology Spread Constraints", "Invalid WhenUnsatisfiable setting detected")
+			score.AddCommentWithField("", "Pod Topology Spread Constraints", "Invalid WhenUnsatisfiable setting detected", field+".whenUnsatisfiable")
 			return
 		}
 	}
 
 	score.Grade = scorecard.GradeAllOK
-	score.AddComment("", "Pod Topology Spread Constraints", "Pod Topology Spread Constraints")
+	score.AddCommentWithField("", "Pod Topology Spread Constraints", "Pod Topology Spread Constraints", spreadsField)
 	return
 }
@@ -0,0 +1,290 @@
+// Package policy implements checks written as Rego policies, that are evaluated with an embedded OPA evaluator.
+//
+// Every Rego package that defines a "deny" or a "warn" rule becomes a check. The object is available as "input",
+// and the rules return a set of messages, either as strings or as objects with a "msg" field, in the same way as
+// policies written for conftest. Messages from "deny" are critical, and messages from "warn" are warnings.
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/rego"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+const (
+	denyRule = "deny"
+	warnRule = "warn"
+)
+
+// Policy is a Rego package that is registered as a check
+type Policy struct {
+	// ID is the ID of the check. It's set with the "id" custom annotation of the package, or derived from
+	// the package path, "data.kubernetes.security" becomes "kubernetes-security".
+	ID string
+	// Name is the title annotation of the package, or the package path
+	Name string
+	// Description is the description annotation of the package
+	Description string
+	// Optional is set with the "optional" custom annotation of the package
+	Optional bool
+
+	deny *rego.PreparedEvalQuery
+	warn *rego.PreparedEvalQuery
+}
+
+// Load parses and compiles all Rego files in dirs and their subdirectories.
+// Files ending with _test.rego are ignored. Packages without "deny" or "warn" rules can be used as
+// libraries by the other packages, but do not become checks.
+func Load(dirs ...string) ([]*Policy, error) {
+	modules := make(map[string]*ast.Module)
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || filepath.Ext(path) != ".rego" || strings.HasSuffix(path, "_test.rego") {
+				return nil
+			}
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			module, err := parseModule(path, string(raw))
+			if err != nil {
+				return err
+			}
+			modules[path] = module
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load policies from %s: %w", dir, err)
+		}
+	}
+	return compile(modules)
+}
+
+// parseModule parses a Rego v1 module. Modules that are not valid Rego v1 are parsed as Rego v0, which most policies
+// written for conftest before OPA 1.0 use. The error is the one from Rego v1 if the module is not valid in either version.
+func parseModule(path, raw string) (*ast.Module, error) {
+	module, err := ast.ParseModuleWithOpts(path, raw, ast.ParserOptions{ProcessAnnotation: true})
+	if err == nil {
+		return module, nil
+	}
+	if v0, v0Err := ast.ParseModuleWithOpts(path, raw, ast.ParserOptions{ProcessAnnotation: true, RegoVersion: ast.RegoV0}); v0Err == nil {
+		return v0, nil
+	}
+	return nil, err
+}
+
+func compile(modules map[string]*ast.Module) ([]*Policy, error) {
+	compiler := ast.NewCompiler()
+	compiler.Compile(modules)
+	if compiler.Failed() {
+		return nil, fmt.Errorf("failed to compile policies: %w", compiler.Errors)
+	}
+
+	// Multiple files can contribute to the same package
+	packages := make(map[string][]*ast.Module)
+	var paths []string
+	for _, module := range modules {
+		path := module.Package.Path.String()
+		if _, ok := packages[path]; !ok {
+			paths = append(paths, path)
+		}
+		packages[path] = append(packages[path], module)
+	}
+	sort.Strings(paths)
+
+	var policies []*Policy
+	for _, path := range paths {
+		pkg := packages[path][0].Package.Path
+
+		deny, err := prepare(compiler, pkg, denyRule)
+		if err != nil {
+			return nil, err
+		}
+		warn, err := prepare(compiler, pkg, warnRule)
+		if err != nil {
+			return nil, err
+		}
+		if deny == nil && warn == nil {
+			continue
+		}
+
+		policy := &Policy{
+			ID:   packageID(pkg),
+			Name: strings.TrimPrefix(path, "data."),
+			deny: deny,
+			warn: warn,
+		}
+		for _, module := range packages[path] {
+			if err := policy.annotate(module); err != nil {
+				return nil, err
+			}
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+// prepare returns a query for the rule in the package, or nil if the package doesn't define the rule
+func prepare(compiler *ast.Compiler, pkg ast.Ref, rule string) (*rego.PreparedEvalQuery, error) {
+	ref := pkg.Append(ast.StringTerm(rule))
+	if len(compiler.GetRulesExact(ref)) == 0 {
+		return nil, nil
+	}
+	query, err := rego.New(
+		rego.Query(ref.String()),
+		rego.Compiler(compiler),
+	).PrepareForEval(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare %s: %w", ref, err)
+	}
+	return &query, nil
+}
+
+func packageID(pkg ast.Ref) string {
+	parts := make([]string, 0, len(pkg)-1)
+	for _, term := range pkg[1:] {
+		if s, ok := term.Value.(ast.String); ok {
+			parts = append(parts, string(s))
+		} else {
+			parts = append(parts, term.Value.String())
+		}
+	}
+	return strings.ToLower(strings.Join(parts, "-"))
+}
+
+func (p *Policy) annotate(module *ast.Module) error {
+	for _, a := range module.Annotations {
+		if a.Scope != "package" {
+			continue
+		}
+		if a.Title != "" {
+			p.Name = a.Title
+		}
+		if a.Description != "" {
+			p.Description = a.Description
+		}
+		if id, ok := a.Custom["id"]; ok {
+			s, ok := id.(string)
+			if !ok || s == "" {
+				return fmt.Errorf("%s: the id annotation must be a non-empty string", a.Location)
+			}
+			p.ID = s
+		}
+		if optional, ok := a.Custom["optional"]; ok {
+			b, ok := optional.(bool)
+			if !ok {
+				return fmt.Errorf("%s: the optional annotation must be a bool", a.Location)
+			}
+			p.Optional = b
+		}
+	}
+	return nil
+}
+
+// Register registers all policies as checks. An error is returned if the ID of a policy is already in use.
+func Register(allChecks *checks.Checks, policies []*Policy) error {
+	registered := make(map[string]struct{})
+	for _, c := range allChecks.All() {
+		registered[c.ID] = struct{}{}
+	}
+
+	for _, policy := range policies {
+		if _, ok := registered[policy.ID]; ok {
+			return fmt.Errorf("the id %q of the policy %q is already in use", policy.ID, policy.Name)
+		}
+		registered[policy.ID] = struct{}{}
+
+		allChecks.RegisterCustomMetaCheck(ks.Check{
+			Name:       policy.Name,
+			ID:         policy.ID,
+			TargetType: "all",
+			Comment:    policy.Description,
+			Optional:   policy.Optional,
+		}, policy.check)
+	}
+
+	return nil
+}
+
+func (p *Policy) check(meta ks.BothMeta) (score scorecard.TestScore, err error) {
+	denied, err := evaluate(p.deny, meta.Object())
+	if err != nil {
+		score.Grade = scorecard.GradeCritical
+		score.AddComment("", "Failed to evaluate the policy", err.Error())
+		return score, nil
+	}
+	warned, err := evaluate(p.warn, meta.Object())
+	if err != nil {
+		score.Grade = scorecard.GradeCritical
+		score.AddComment("", "Failed to evaluate the policy", err.Error())
+		return score, nil
+	}
+
+	switch {
+	case len(denied) > 0:
+		score.Grade = scorecard.GradeCritical
+	case len(warned) > 0:
+		score.Grade = scorecard.GradeWarning
+	default:
+		score.Grade = scorecard.GradeAllOK
+	}
+
+	for _, msg := range append(denied, warned...) {
+		score.AddComment("", msg, p.Description)
+	}
+
+	return score, nil
+}
+
+// evaluate runs the query with the object as input, and returns the messages of the result
+func evaluate(query *rego.PreparedEvalQuery, object map[string]interface{}) ([]string, error) {
+	if query == nil {
+		return nil, nil
+	}
+
+	rs, err := query.Eval(context.Background(), rego.EvalInput(object))
+	if err != nil {
+		return nil, err
+	}
+	if len(rs) == 0 || len(rs[0].Expressions) == 0 {
+		return nil, nil
+	}
+
+	values, ok := rs[0].Expressions[0].Value.([]interface{})
+	if !ok {
+		return nil, errors.New("the rule must be a set of messages")
+	}
+
+	messages := make([]string, 0, len(values))
+	for _, v := range values {
+		switch v := v.(type) {
+		case string:
+			messages = append(messages, v)
+		case map[string]interface{}:
+			msg, ok := v["msg"].(string)
+			if !ok {
+				return nil, errors.New("the result objects must have a \"msg\" field")
+			}
+			messages = append(messages, msg)
+		default:
+			return nil, fmt.Errorf("unexpected result %v, must be a string or an object with a \"msg\" field", v)
+		}
+	}
+	return messages, nil
+}
@@ -0,0 +1,236 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/parser"
+	"github.com/zegl/kube-score/score"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+type namedReader struct {
+	*strings.Reader
+	name string
+}
+
+func (n namedReader) Name() string {
+	return n.name
+}
+
+const securityPolicy = `# METADATA
+# title: No Host Network
+# description: Pods must not use the host network
+# custom:
+#   id: no-host-network
+package kubernetes.security
+
+import data.lib.kubernetes
+
+deny contains msg if {
+	kubernetes.pod_spec.hostNetwork
+	msg := sprintf("%s %s uses the host network", [input.kind, input.metadata.name])
+}
+
+warn contains {"msg": "The deployment has more than 10 replicas"} if {
+	input.kind == "Deployment"
+	input.spec.replicas > 10
+}
+`
+
+const labelsPolicy = `package labels
+
+warn contains "The team label is not set" if {
+	not input.metadata.labels.team
+}
+`
+
+const libPolicy = `package lib.kubernetes
+
+pod_spec := input.spec.template.spec if input.spec.template.spec
+pod_spec := input.spec if input.kind == "Pod"
+`
+
+const objects = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  labels:
+    team: foo
+spec:
+  replicas: 20
+  template:
+    metadata:
+      labels:
+        app: foo
+    spec:
+      hostNetwork: true
+      containers:
+      - name: foo
+        image: foo:1.0
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+spec:
+  selector:
+    app: foo
+`
+
+func writePolicies(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	return dir
+}
+
+func testScore(t *testing.T, policies []*Policy, objects string) scorecard.Scorecard {
+	t.Helper()
+
+	p, err := parser.New(nil)
+	assert.NoError(t, err)
+	allObjects, err := p.ParseFiles([]ks.NamedReader{namedReader{Reader: strings.NewReader(objects), name: "objects.yaml"}})
+	assert.NoError(t, err)
+
+	allChecks := checks.New(nil)
+	assert.NoError(t, Register(allChecks, policies))
+
+	card, err := score.Score(allObjects, allChecks, &config.RunConfiguration{UseIgnoreChecksAnnotation: true})
+	assert.NoError(t, err)
+	return *card
+}
+
+func findScore(t *testing.T, card scorecard.Scorecard, kind, checkID string) scorecard.TestScore {
+	t.Helper()
+	for _, o := range card {
+		if o.TypeMeta.Kind != kind {
+			continue
+		}
+		for _, c := range o.Checks {
+			if c.Check.ID == checkID {
+				return c
+			}
+		}
+	}
+	t.Fatalf("check %s was not run on %s", checkID, kind)
+	return scorecard.TestScore{}
+}
+
+func TestPolicies(t *testing.T) {
+	dir := writePolicies(t, map[string]string{
+		"security.rego":          securityPolicy,
+		"security_test.rego":     "package kubernetes.security_test\n\nthis is not valid rego",
+		"labels/labels.rego":     labelsPolicy,
+		"lib/kubernetes.rego":    libPolicy,
+		"lib/README.md":          "not a policy",
+		"labels/data/other.json": "{}",
+	})
+
+	policies, err := Load(dir)
+	assert.NoError(t, err)
+	if assert.Len(t, policies, 2) {
+		assert.Equal(t, "no-host-network", policies[0].ID)
+		assert.Equal(t, "No Host Network", policies[0].Name)
+		assert.Equal(t, "Pods must not use the host network", policies[0].Description)
+		assert.Equal(t, "labels", policies[1].ID)
+		assert.Equal(t, "labels", policies[1].Name)
+	}
+
+	card := testScore(t, policies, objects)
+
+	hostNetwork := findScore(t, card, "Deployment", "no-host-network")
+	assert.Equal(t, scorecard.GradeCritical, hostNetwork.Grade)
+	if assert.Len(t, hostNetwork.Comments, 2) {
+		assert.Equal(t, "Deployment app uses the host network", hostNetwork.Comments[0].Summary)
+		assert.Equal(t, "The deployment has more than 10 replicas", hostNetwork.Comments[1].Summary)
+	}
+	assert.Equal(t, scorecard.GradeAllOK, findScore(t, card, "Service", "no-host-network").Grade)
+
+	assert.Equal(t, scorecard.GradeAllOK, findScore(t, card, "Deployment", "labels").Grade)
+	svcLabels := findScore(t, card, "Service", "labels")
+	assert.Equal(t, scorecard.GradeWarning, svcLabels.Grade)
+	assert.Equal(t, "The team label is not set", svcLabels.Comments[0].Summary)
+}
+
+func TestPolicyIgnoreAnnotation(t *testing.T) {
+	policies, err := Load(writePolicies(t, map[string]string{"labels.rego": labelsPolicy}))
+	assert.NoError(t, err)
+
+	card := testScore(t, policies, `apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+  annotations:
+    kube-score/ignore: labels
+`)
+	assert.True(t, findScore(t, card, "Service", "labels").Skipped)
+}
+
+func TestRegoV0Policies(t *testing.T) {
+	dir := writePolicies(t, map[string]string{
+		"security.rego": securityPolicy,
+		"lib/kubernetes.rego": `package lib.kubernetes
+
+pod_spec = input.spec.template.spec {
+	input.spec.template.spec
+}
+`,
+		"replicas.rego": `package replicas
+
+deny[msg] {
+	input.spec.replicas > 10
+	msg := sprintf("%s %s has more than 10 replicas", [input.kind, input.metadata.name])
+}
+`,
+	})
+
+	policies, err := Load(dir)
+	assert.NoError(t, err)
+	assert.Len(t, policies, 2)
+
+	card := testScore(t, policies, objects)
+	assert.Equal(t, scorecard.GradeCritical, findScore(t, card, "Deployment", "no-host-network").Grade)
+	replicas := findScore(t, card, "Deployment", "replicas")
+	assert.Equal(t, scorecard.GradeCritical, replicas.Grade)
+	if assert.Len(t, replicas.Comments, 1) {
+		assert.Equal(t, "Deployment app has more than 10 replicas", replicas.Comments[0].Summary)
+	}
+	assert.Equal(t, scorecard.GradeAllOK, findScore(t, card, "Service", "replicas").Grade)
+}
+
+func TestLoadErrors(t *testing.T) {
+	_, err := Load(writePolicies(t, map[string]string{"a.rego": "package a\n\ndeny contains msg if {\n"}))
+	assert.ErrorContains(t, err, "a.rego:4: rego_parse_error")
+
+	_, err = Load(writePolicies(t, map[string]string{"a.rego": "package a\n\ndeny contains msg if {\n\tmsg := data.b.missing(1)\n}\n"}))
+	assert.ErrorContains(t, err, "failed to compile policies")
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorContains(t, err, "failed to load policies from")
+}
+
+func TestRegisterDuplicateID(t *testing.T) {
+	policies, err := Load(writePolicies(t, map[string]string{"a.rego": `# METADATA
+# custom:
+#   id: container-resources
+package a
+
+deny contains "foo" if false
+`}))
+	assert.NoError(t, err)
+
+	allChecks := score.RegisterAllChecks(parser.Empty(), nil, nil)
+	assert.EqualError(t, Register(allChecks, policies), `the id "container-resources" of the policy "a" is already in use`)
+}
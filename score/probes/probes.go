@@ -51,10 +51,11 @@ func containerProbes(allServices []ks.Service) func(ks.PodSpecer) (scorecard.Tes
 		// Evaluate probe checks
 		if !hasReadinessProbe {
 			score.Grade = scorecard.GradeCritical
-			score.AddCommentWithURL("", "Container is missing a readinessProbe",
+			score.AddCommentWithFieldAndURL("", "Container is missing a readinessProbe",
 				"A readinessProbe should be used to indicate when the service is ready to receive traffic. "+
 					"Without it, the Pod is risking to receive traffic before it has booted. "+
 					"It's also used during rollouts, and can prevent downtime if a new version of the application is failing.",
+				internal.PodSpecField(ps)+".containers",
 				"https://github.com/zegl/kube-score/blob/master/README_PROBES.md",
 			)
 			return score, nil
@@ -62,9 +63,10 @@ func containerProbes(allServices []ks.Service) func(ks.PodSpecer) (scorecard.Tes
 
 		if !hasLivenessProbe {
 			score.Grade = scorecard.GradeAlmostOK
-			score.AddCommentWithURL("", "Container is missing a livenessProbe",
+			score.AddCommentWithFieldAndURL("", "Container is missing a livenessProbe",
 				"A livenessProbe can be used to restart the container if it's deadlocked or has crashed without exiting. "+
 					"It's only recommended to setup a livenessProbe if you really need one.",
+				internal.PodSpecField(ps)+".containers",
 				"https://github.com/zegl/kube-score/blob/master/README_PROBES.md",
 			)
 			return score, nil
@@ -88,22 +90,24 @@ func containerProbesIdentical(allServices []ks.Service) func(ks.PodSpecer) (scor
 		allContainers := podTemplate.Spec.InitContainers
 		allContainers = append(allContainers, podTemplate.Spec.Containers...)
 
-		probesAreIdentical := false
-		for _, container := range allContainers {
+		containerFields := internal.ContainerFields(ps)
+		identicalField := ""
+		for i, container := range allContainers {
 			if container.ReadinessProbe != nil && container.LivenessProbe != nil {
 				if areProbesIdentical(container.ReadinessProbe, container.LivenessProbe) {
-					probesAreIdentical = true
+					identicalField = containerFields[i] + ".livenessProbe"
 					break
 				}
 			}
 		}
 
 		// If probes are identical, mark it as a critical issue
-		if probesAreIdentical {
+		if identicalField != "" {
 			score.Grade = scorecard.GradeCritical
-			score.AddCommentWithURL(
+			score.AddCommentWithFieldAndURL(
 				"", "Container has the same readiness and liveness probe",
 				"Using the same probe for liveness and readiness is very likely dangerous. It's generally better to avoid re-using the same probe.",
+				identicalField,
 				"https://github.com/zegl/kube-score/blob/master/README_PROBES.md",
 			)
 			return score, nil
@@ -0,0 +1,259 @@
+package rbac
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/score/internal"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func Register(allChecks *checks.Checks, roles ks.Roles, bindings ks.RoleBindings, serviceAccounts ks.ServiceAccounts) {
+	allChecks.RegisterRoleCheck("Role wildcard", `Makes sure that Roles and ClusterRoles don't grant all verbs or all resources with a wildcard`, roleWildcard)
+	allChecks.RegisterRoleCheck("Role escalation verbs", `Makes sure that Roles and ClusterRoles don't grant the escalate, bind or impersonate verbs`, roleEscalationVerbs)
+	allChecks.RegisterRoleCheck("Role Secrets access", `Makes sure that Roles and ClusterRoles don't grant read access to all Secrets`, roleSecretsAccess)
+	allChecks.RegisterRoleBindingCheck("RoleBinding cluster-admin", `Makes sure that RoleBindings and ClusterRoleBindings don't bind the cluster-admin ClusterRole`, bindingClusterAdmin)
+	allChecks.RegisterRoleBindingCheck("RoleBinding targets Role", `Makes sure that RoleBindings and ClusterRoleBindings reference a Role or ClusterRole that exists`, bindingTargetsRole(roles.Roles()))
+	allChecks.RegisterRoleBindingCheck("RoleBinding targets ServiceAccount", `Makes sure that the ServiceAccounts that RoleBindings and ClusterRoleBindings bind to exist`, bindingTargetsServiceAccount(serviceAccounts.ServiceAccounts()))
+	allChecks.RegisterPodCheck("Pod ServiceAccount cluster-wide write access", `Makes sure that the ServiceAccount of the pod is not granted write access in all namespaces by a ClusterRoleBinding`, podServiceAccountClusterWrite(roles.Roles(), bindings.RoleBindings()))
+}
+
+// builtinClusterRoles are the user-facing ClusterRoles that exist in all clusters. The ClusterRoles with the "system:"
+// prefix also exist.
+var builtinClusterRoles = map[string]struct {
+	// write is true if the ClusterRole grants write access
+	write bool
+}{
+	"cluster-admin": {write: true},
+	"admin":         {write: true},
+	"edit":          {write: true},
+	"view":          {write: false},
+}
+
+// writeVerbs are the verbs that change objects, or that grant more access
+var writeVerbs = []string{"create", "update", "patch", "delete", "deletecollection", "escalate", "bind", "impersonate", rbacv1.VerbAll}
+
+func rulesField(i int) string {
+	return ks.FieldIndexPath("rules", i)
+}
+
+func roleWildcard(role ks.Role) (score scorecard.TestScore, err error) {
+	for i, rule := range role.Rules() {
+		if slices.Contains(rule.Verbs, rbacv1.VerbAll) {
+			score.AddCommentWithField("", "The rule grants all verbs",
+				"A wildcard grants all current and future verbs, including escalate, bind and impersonate. List the verbs that are needed instead.",
+				rulesField(i)+".verbs")
+		}
+		if slices.Contains(rule.Resources, rbacv1.ResourceAll) {
+			score.AddCommentWithField("", "The rule grants all resources",
+				"A wildcard grants access to all current and future resources, including Secrets and the RBAC resources. List the resources that are needed instead.",
+				rulesField(i)+".resources")
+		}
+	}
+
+	if len(score.Comments) > 0 {
+		score.Grade = scorecard.GradeCritical
+	} else {
+		score.Grade = scorecard.GradeAllOK
+	}
+	return
+}
+
+func roleEscalationVerbs(role ks.Role) (score scorecard.TestScore, err error) {
+	descriptions := map[string]string{
+		"escalate":    "The escalate verb allows creating and updating roles with permissions that the user does not have.",
+		"bind":        "The bind verb allows binding roles with permissions that the user does not have.",
+		"impersonate": "The impersonate verb allows acting as other users, groups and ServiceAccounts, with all of their permissions.",
+	}
+
+	for i, rule := range role.Rules() {
+		for _, verb := range rule.Verbs {
+			if description, ok := descriptions[verb]; ok {
+				score.AddCommentWithField("", fmt.Sprintf("The rule grants the %s verb", verb), description, rulesField(i)+".verbs")
+			}
+		}
+	}
+
+	if len(score.Comments) > 0 {
+		score.Grade = scorecard.GradeCritical
+	} else {
+		score.Grade = scorecard.GradeAllOK
+	}
+	return
+}
+
+func roleSecretsAccess(role ks.Role) (score scorecard.TestScore, err error) {
+	score.Grade = scorecard.GradeAllOK
+
+	for i, rule := range role.Rules() {
+		grantsSecrets := matches(rule.APIGroups, "") && matches(rule.Resources, "secrets")
+		// Read access to named Secrets is the least privilege
+		if !grantsSecrets || len(rule.ResourceNames) > 0 {
+			continue
+		}
+		if matches(rule.Verbs, "get") || matches(rule.Verbs, "list") || matches(rule.Verbs, "watch") {
+			score.Grade = scorecard.GradeWarning
+			score.AddCommentWithField("", "The rule grants read access to all Secrets",
+				"Secrets often contain credentials. Limit the access to the Secrets that are needed with resourceNames.",
+				rulesField(i))
+		}
+	}
+	return
+}
+
+func bindingClusterAdmin(binding ks.RoleBinding) (score scorecard.TestScore, err error) {
+	roleRef := binding.RoleRef()
+	if roleRef.Kind == "ClusterRole" && roleRef.Name == "cluster-admin" {
+		score.Grade = scorecard.GradeCritical
+		score.AddCommentWithField("", "The binding grants cluster-admin",
+			"cluster-admin grants full access to all resources. Bind a role with only the permissions that are needed instead.",
+			"roleRef.name")
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+	return
+}
+
+func bindingTargetsRole(roles []ks.Role) func(ks.RoleBinding) (scorecard.TestScore, error) {
+	return func(binding ks.RoleBinding) (score scorecard.TestScore, err error) {
+		roleRef := binding.RoleRef()
+		if roleRef.Kind == "ClusterRole" && isBuiltinClusterRole(roleRef.Name) {
+			score.Grade = scorecard.GradeAllOK
+			return
+		}
+
+		if _, ok := findRole(roles, roleRef.Kind, roleRef.Name, binding.GetObjectMeta().Namespace); ok {
+			score.Grade = scorecard.GradeAllOK
+			return
+		}
+
+		score.Grade = scorecard.GradeCritical
+		score.AddCommentWithField("", fmt.Sprintf("The %s %s does not exist", roleRef.Kind, roleRef.Name),
+			"The binding does not grant any access if the role that it references does not exist.",
+			"roleRef.name")
+		return
+	}
+}
+
+func bindingTargetsServiceAccount(serviceAccounts []ks.ServiceAccount) func(ks.RoleBinding) (scorecard.TestScore, error) {
+	return func(binding ks.RoleBinding) (score scorecard.TestScore, err error) {
+		var hasServiceAccounts bool
+		for i, subject := range binding.Subjects() {
+			if subject.Kind != rbacv1.ServiceAccountKind {
+				continue
+			}
+			hasServiceAccounts = true
+
+			namespace := internal.SubjectNamespace(binding, subject)
+			if subject.Name == "default" || hasServiceAccount(serviceAccounts, subject.Name, namespace) {
+				continue
+			}
+			score.AddCommentWithField("", fmt.Sprintf("The ServiceAccount %s does not exist", subject.Name),
+				fmt.Sprintf("No ServiceAccount named %s was found in the namespace %q.", subject.Name, namespace),
+				ks.FieldIndexPath("subjects", i)+".name")
+		}
+
+		if !hasServiceAccounts {
+			score.Skipped = true
+			score.AddComment("", "Skipped because the binding has no ServiceAccount subjects", "")
+			return
+		}
+
+		if len(score.Comments) > 0 {
+			score.Grade = scorecard.GradeCritical
+		} else {
+			score.Grade = scorecard.GradeAllOK
+		}
+		return
+	}
+}
+
+func podServiceAccountClusterWrite(roles []ks.Role, bindings []ks.RoleBinding) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		template := ps.GetPodTemplateSpec()
+		name := template.Spec.ServiceAccountName
+		if name == "" {
+			name = template.Spec.DeprecatedServiceAccount
+		}
+		if name == "" {
+			name = "default"
+		}
+
+		score.Grade = scorecard.GradeAllOK
+		for _, binding := range bindings {
+			if binding.GetTypeMeta().Kind != "ClusterRoleBinding" || !internal.BindsServiceAccount(binding, name, template.Namespace) {
+				continue
+			}
+			if !clusterRoleGrantsWrite(roles, binding.RoleRef().Name) {
+				continue
+			}
+			score.Grade = scorecard.GradeCritical
+			score.AddComment("", fmt.Sprintf("The ServiceAccount %s has write access in all namespaces", name),
+				fmt.Sprintf("The ClusterRoleBinding %s grants the ClusterRole %s to the ServiceAccount. Grant the access with a RoleBinding in the namespaces where it's needed instead.", binding.GetObjectMeta().Name, binding.RoleRef().Name))
+		}
+		return
+	}
+}
+
+// clusterRoleGrantsWrite returns true if the ClusterRole grants write access to any resource. ClusterRoles that are
+// not built-in and not in the input are unknown, and false is returned.
+func clusterRoleGrantsWrite(roles []ks.Role, name string) bool {
+	if builtin, ok := builtinClusterRoles[name]; ok {
+		return builtin.write
+	}
+	role, ok := findRole(roles, "ClusterRole", name, "")
+	if !ok {
+		return false
+	}
+	for _, rule := range role.Rules() {
+		if len(rule.Resources) == 0 {
+			continue
+		}
+		for _, verb := range writeVerbs {
+			if slices.Contains(rule.Verbs, verb) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// findRole returns the Role in the namespace, or the ClusterRole, with the name
+func findRole(roles []ks.Role, kind, name, namespace string) (ks.Role, bool) {
+	for _, role := range roles {
+		meta := role.GetObjectMeta()
+		if role.GetTypeMeta().Kind != kind || meta.Name != name {
+			continue
+		}
+		if kind == "Role" && meta.Namespace != namespace {
+			continue
+		}
+		return role, true
+	}
+	return nil, false
+}
+
+func hasServiceAccount(serviceAccounts []ks.ServiceAccount, name, namespace string) bool {
+	for _, sa := range serviceAccounts {
+		if sa.ServiceAccount().Name == name && sa.ServiceAccount().Namespace == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+func isBuiltinClusterRole(name string) bool {
+	_, ok := builtinClusterRoles[name]
+	return ok || strings.HasPrefix(name, "system:")
+}
+
+// matches returns true if the value is in the list, or if the list has a wildcard
+func matches(list []string, value string) bool {
+	return slices.Contains(list, value) || slices.Contains(list, "*")
+}
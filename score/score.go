@@ -7,6 +7,7 @@ import (
 	ks "github.com/zegl/kube-score/domain"
 	"github.com/zegl/kube-score/score/apps"
 	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/score/configref"
 	"github.com/zegl/kube-score/score/container"
 	"github.com/zegl/kube-score/score/cronjob"
 	"github.com/zegl/kube-score/score/deployment"
@@ -15,11 +16,15 @@ import (
 	"github.com/zegl/kube-score/score/ingress"
 	"github.com/zegl/kube-score/score/meta"
 	"github.com/zegl/kube-score/score/networkpolicy"
+	"github.com/zegl/kube-score/score/parseerror"
 	"github.com/zegl/kube-score/score/podtopologyspreadconstraints"
 	"github.com/zegl/kube-score/score/probes"
+	"github.com/zegl/kube-score/score/rbac"
 	"github.com/zegl/kube-score/score/security"
 	"github.com/zegl/kube-score/score/service"
+	"github.com/zegl/kube-score/score/serviceaccount"
 	"github.com/zegl/kube-score/score/stable"
+	"github.com/zegl/kube-score/score/unknownkind"
 	"github.com/zegl/kube-score/scorecard"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -46,6 +51,11 @@ func RegisterAllChecks(allObjects ks.AllTypes, checksConfig *checks.Config, runC
 	meta.Register(allChecks)
 	hpa.Register(allChecks, allObjects.Metas(), runConfig.MinReplicasHPA)
 	podtopologyspreadconstraints.Register(allChecks)
+	rbac.Register(allChecks, allObjects, allObjects, allObjects)
+	serviceaccount.Register(allChecks, allObjects, allObjects, allObjects, allObjects)
+	configref.Register(allChecks, allObjects, allObjects, runConfig.ExternalConfigRefs)
+	parseerror.Register(allChecks)
+	unknownkind.Register(allChecks, runConfig.FailOnUnknownKind)
 
 	return allChecks
 }
@@ -103,6 +113,9 @@ func Score(allObjects ks.AllTypes, allChecks *checks.Checks, cnf *config.RunConf
 	for _, meta := range allObjects.Metas() {
 		o := newObject(meta.TypeMeta, meta.ObjectMeta)
 		for _, test := range allChecks.Metas() {
+			if !test.Applies(meta) {
+				continue
+			}
 			fn, err := test.Fn(meta)
 			if err != nil {
 				return nil, err
@@ -120,11 +133,15 @@ func Score(allObjects ks.AllTypes, allChecks *checks.Checks, cnf *config.RunConf
 				Spec:       pod.Pod().Spec,
 			}
 
-			score, _ := test.Fn(&podSpeccer{
+			ps := &podSpeccer{
 				typeMeta:   pod.Pod().TypeMeta,
 				objectMeta: pod.Pod().ObjectMeta,
 				spec:       podTemplateSpec,
-			})
+			}
+			if !test.Applies(ps) {
+				continue
+			}
+			score, _ := test.Fn(ps)
 			o.Add(score, test.Check, pod, pod.Pod().ObjectMeta.Annotations)
 		}
 	}
@@ -132,6 +149,9 @@ func Score(allObjects ks.AllTypes, allChecks *checks.Checks, cnf *config.RunConf
 	for _, podspecer := range allObjects.PodSpeccers() {
 		o := newObject(podspecer.GetTypeMeta(), podspecer.GetObjectMeta())
 		for _, test := range allChecks.Pods() {
+			if !test.Applies(podspecer) {
+				continue
+			}
 			score, _ := test.Fn(podspecer)
 			o.Add(score, test.Check, podspecer,
 				podspecer.GetObjectMeta().Annotations,
@@ -195,6 +215,20 @@ func Score(allObjects ks.AllTypes, allChecks *checks.Checks, cnf *config.RunConf
 		}
 	}
 
+	for _, object := range allObjects.Objects() {
+		o := newObject(object.GetTypeMeta(), object.GetObjectMeta())
+		for _, test := range allChecks.Objects() {
+			if !test.Applies(object) {
+				continue
+			}
+			fn, err := test.Fn(object)
+			if err != nil {
+				return nil, err
+			}
+			o.Add(fn, test.Check, object, object.GetObjectMeta().Annotations)
+		}
+	}
+
 	for _, hpa := range allObjects.HorizontalPodAutoscalers() {
 		o := newObject(hpa.GetTypeMeta(), hpa.GetObjectMeta())
 		for _, test := range allChecks.HorizontalPodAutoscalers() {
@@ -217,5 +251,49 @@ func Score(allObjects ks.AllTypes, allChecks *checks.Checks, cnf *config.RunConf
 		}
 	}
 
+	for _, role := range allObjects.Roles() {
+		o := newObject(role.GetTypeMeta(), role.GetObjectMeta())
+		for _, test := range allChecks.Roles() {
+			fn, err := test.Fn(role)
+			if err != nil {
+				return nil, err
+			}
+			o.Add(fn, test.Check, role, role.GetObjectMeta().Annotations)
+		}
+	}
+
+	for _, binding := range allObjects.RoleBindings() {
+		o := newObject(binding.GetTypeMeta(), binding.GetObjectMeta())
+		for _, test := range allChecks.RoleBindings() {
+			fn, err := test.Fn(binding)
+			if err != nil {
+				return nil, err
+			}
+			o.Add(fn, test.Check, binding, binding.GetObjectMeta().Annotations)
+		}
+	}
+
+	for _, parseErr := range allObjects.ParseErrors() {
+		o := newObject(parseErr.TypeMeta, parseErr.ObjectMeta)
+		for _, test := range allChecks.ParseErrors() {
+			fn, err := test.Fn(parseErr)
+			if err != nil {
+				return nil, err
+			}
+			o.Add(fn, test.Check, parseErr)
+		}
+	}
+
+	for _, unknown := range allObjects.UnknownKinds() {
+		o := newObject(unknown.TypeMeta, unknown.ObjectMeta)
+		for _, test := range allChecks.UnknownKinds() {
+			fn, err := test.Fn(unknown)
+			if err != nil {
+				return nil, err
+			}
+			o.Add(fn, test.Check, unknown, unknown.ObjectMeta.Annotations)
+		}
+	}
+
 	return &scoreCard, nil
 }
@@ -1,6 +1,7 @@
 package score
 
 import (
+	"fmt"
 	"os"
 	"testing"
 
@@ -11,6 +12,8 @@ import (
 	"github.com/zegl/kube-score/parser"
 	"github.com/zegl/kube-score/score/checks"
 	"github.com/zegl/kube-score/scorecard"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func testFile(name string) *os.File {
@@ -517,11 +520,17 @@ func TestPodEnvDuplicated(t *testing.T) {
 			Path:        "foobar",
 			Summary:     "Environment Variable Key Duplication",
 			Description: "Container environment variable key 'bar' is duplicated",
+			Field:       "spec.containers[0].env[2]",
+			Line:        14,
+			Column:      7,
 		},
 		{
 			Path:        "foobar",
 			Summary:     "Environment Variable Key Duplication",
 			Description: "Container environment variable key 'baz' is duplicated",
+			Field:       "spec.containers[0].env[4]",
+			Line:        18,
+			Column:      7,
 		},
 	}
 	diff := cmp.Diff(expected, actual)
@@ -558,3 +567,397 @@ func TestMultipleIgnoreAnnotations(t *testing.T) {
 	assert.True(t, tested)
 	assert.True(t, skipped)
 }
+
+func TestContinueOnParseError(t *testing.T) {
+	t.Parallel()
+	p, err := parser.New(&parser.Config{ContinueOnParseError: true})
+	assert.NoError(t, err)
+	parsed, err := p.ParseFiles([]ks.NamedReader{testFile("parse-error.yaml")})
+	assert.NoError(t, err)
+
+	allChecks := RegisterAllChecks(parsed, nil, nil)
+	sc, err := Score(parsed, allChecks, &config.RunConfiguration{})
+	assert.NoError(t, err)
+
+	type parseError struct {
+		summary string
+		line    int
+	}
+	parseErrors := make(map[string]parseError)
+	for _, o := range *sc {
+		for _, c := range o.Checks {
+			if c.Check.ID != "parse-error" {
+				continue
+			}
+			assert.Equal(t, scorecard.GradeCritical, c.Grade)
+			assert.Len(t, c.Comments, 1)
+			parseErrors[o.ObjectMeta.Name] = parseError{summary: c.Comments[0].Summary, line: o.FileLocation.Line}
+		}
+	}
+
+	// The valid Service is scored as usual
+	_, ok := (*sc)["Service/v1//valid"]
+	assert.True(t, ok)
+	assert.Equal(t, map[string]parseError{
+		"wrong-type":                   {summary: "Failed to parse testdata/parse-error.yaml:9", line: 9},
+		"testdata/parse-error.yaml:22": {summary: "Failed to parse testdata/parse-error.yaml:22", line: 22},
+	}, parseErrors)
+}
+
+func TestStrictUnknownFields(t *testing.T) {
+	t.Parallel()
+	p, err := parser.New(&parser.Config{Strict: true})
+	assert.NoError(t, err)
+	parsed, err := p.ParseFiles([]ks.NamedReader{testFile("strict-unknown-fields.yaml")})
+	assert.NoError(t, err)
+
+	allChecks := RegisterAllChecks(parsed, nil, nil)
+	sc, err := Score(parsed, allChecks, &config.RunConfiguration{})
+	assert.NoError(t, err)
+
+	type comment struct {
+		summary string
+		line    int
+		column  int
+	}
+	grades := make(map[string]scorecard.Grade)
+	var comments []comment
+	for key, o := range *sc {
+		for _, c := range o.Checks {
+			if c.Check.ID != "unknown-field" {
+				continue
+			}
+			grades[key] = c.Grade
+			for _, cm := range c.Comments {
+				comments = append(comments, comment{summary: cm.Path + ": " + cm.Summary, line: cm.Line, column: cm.Column})
+			}
+		}
+	}
+
+	assert.Equal(t, map[string]scorecard.Grade{
+		"Deployment/apps/v1//app": scorecard.GradeCritical,
+		"Service/v1//app":         scorecard.GradeAllOK,
+	}, grades)
+	assert.Equal(t, []comment{
+		{summary: "spec.replica: Unknown field, did you mean replicas?", line: 9, column: 3},
+		{summary: "spec.template.spec.containers[0].readinessprobe: Unknown field, did you mean readinessProbe?", line: 21, column: 9},
+		{summary: "spec.template.spec.containers[0].resources.limit: Unknown field, did you mean limits?", line: 26, column: 11},
+		{summary: "metadata.labels.app: Duplicate field", line: 7, column: 5},
+	}, comments)
+}
+
+func TestStrictUnknownFieldsDisabled(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore([]ks.NamedReader{testFile("strict-unknown-fields.yaml")}, nil, nil)
+	assert.NoError(t, err)
+	// The check has no result for objects that are not validated
+	for _, o := range sc {
+		for _, c := range o.Checks {
+			assert.NotEqual(t, "unknown-field", c.Check.ID)
+		}
+	}
+}
+
+func TestPodTemplateKinds(t *testing.T) {
+	t.Parallel()
+	p, err := parser.New(&parser.Config{PodTemplateKinds: []config.PodTemplateKind{
+		{APIVersion: "example.com/v1", Kind: "Worker", PodTemplatePath: "spec.pod"},
+	}})
+	assert.NoError(t, err)
+	parsed, err := p.ParseFiles([]ks.NamedReader{testFile("pod-template-kinds.yaml")})
+	assert.NoError(t, err)
+
+	// The Rollout with a workloadRef does not have a pod template
+	assert.Len(t, parsed.PodSpeccers(), 3)
+
+	allChecks := RegisterAllChecks(parsed, nil, nil)
+	sc, err := Score(parsed, allChecks, &config.RunConfiguration{})
+	assert.NoError(t, err)
+
+	tag := scoredCheck(t, *sc, "Rollout/argoproj.io/v1alpha1//rollout", "container-image-tag")
+	assert.Equal(t, scorecard.GradeCritical, tag.Grade)
+	if assert.Len(t, tag.Comments, 1) {
+		assert.Equal(t, "spec.template.spec.containers[0].image", tag.Comments[0].Field)
+		assert.Equal(t, 17, tag.Comments[0].Line)
+	}
+	selector := scoredCheck(t, *sc, "Rollout/argoproj.io/v1alpha1//rollout", "pod-template-kind-selector-labels-match-template-metadata-labels")
+	assert.Equal(t, scorecard.GradeCritical, selector.Grade)
+
+	resources := scoredCheck(t, *sc, "ScaledJob/keda.sh/v1alpha1//scaledjob", "container-resources")
+	assert.Equal(t, scorecard.GradeCritical, resources.Grade)
+	if assert.NotEmpty(t, resources.Comments) {
+		assert.Equal(t, "spec.jobTargetRef.template.spec.containers[0].resources.limits.cpu", resources.Comments[0].Field)
+		assert.Equal(t, 28, resources.Comments[0].Line)
+	}
+	for _, c := range (*sc)["ScaledJob/keda.sh/v1alpha1//scaledjob"].Checks {
+		assert.NotEqual(t, "pod-template-kind-selector-labels-match-template-metadata-labels", c.Check.ID)
+	}
+
+	assert.Equal(t, scorecard.GradeAllOK, scoredCheck(t, *sc, "Worker/example.com/v1//worker", "container-image-tag").Grade)
+}
+
+func TestReplicaSetKinds(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore([]ks.NamedReader{testFile("replicaset-kinds.yaml")}, nil, &config.RunConfiguration{
+		KubernetesVersion: config.Semver{Major: 1, Minor: 18},
+	})
+	assert.NoError(t, err)
+
+	tag := scoredCheck(t, sc, "ReplicaSet/apps/v1//replicaset", "container-image-tag")
+	assert.Equal(t, scorecard.GradeCritical, tag.Grade)
+	if assert.Len(t, tag.Comments, 1) {
+		assert.Equal(t, "spec.template.spec.containers[0].image", tag.Comments[0].Field)
+	}
+	assert.Equal(t, scorecard.GradeAllOK, scoredCheck(t, sc, "ReplicaSet/apps/v1//replicaset", "replicaset-has-poddisruptionbudget").Grade)
+	assert.Equal(t, scorecard.GradeAllOK, scoredCheck(t, sc, "ReplicaSet/apps/v1//replicaset", "pod-template-kind-selector-labels-match-template-metadata-labels").Grade)
+	assert.Equal(t, scorecard.GradeAllOK, scoredCheck(t, sc, "ReplicaSet/apps/v1//replicaset", "stable-version").Grade)
+
+	pdb := scoredCheck(t, sc, "ReplicationController/v1//rc", "replicaset-has-poddisruptionbudget")
+	assert.Equal(t, scorecard.GradeCritical, pdb.Grade)
+	if assert.Len(t, pdb.Comments, 1) {
+		assert.Equal(t, "spec.template.metadata.labels", pdb.Comments[0].Field)
+		assert.Equal(t, 29, pdb.Comments[0].Line)
+	}
+	selector := scoredCheck(t, sc, "ReplicationController/v1//rc", "pod-template-kind-selector-labels-match-template-metadata-labels")
+	assert.Equal(t, scorecard.GradeCritical, selector.Grade)
+	if assert.Len(t, selector.Comments, 1) {
+		assert.Equal(t, "spec.selector", selector.Comments[0].Field)
+		assert.Equal(t, 25, selector.Comments[0].Line)
+	}
+	assert.Equal(t, scorecard.GradeWarning, scoredCheck(t, sc, "ReplicationController/v1//rc", "stable-version").Grade)
+	assert.Equal(t, scorecard.GradeAllOK, scoredCheck(t, sc, "Service/v1//rc", "service-targets-pod").Grade)
+	assert.Equal(t, scorecard.GradeAllOK, scoredCheck(t, sc, "HorizontalPodAutoscaler/autoscaling/v1//rc", "horizontalpodautoscaler-has-target").Grade)
+
+	tag = scoredCheck(t, sc, "PodTemplate/v1//podtemplate", "container-image-tag")
+	assert.Equal(t, scorecard.GradeCritical, tag.Grade)
+	if assert.Len(t, tag.Comments, 1) {
+		assert.Equal(t, "template.spec.containers[0].image", tag.Comments[0].Field)
+		assert.Equal(t, 47, tag.Comments[0].Line)
+	}
+	for _, c := range sc["PodTemplate/v1//podtemplate"].Checks {
+		assert.NotEqual(t, "replicaset-has-poddisruptionbudget", c.Check.ID)
+	}
+	assert.Equal(t, scorecard.GradeAllOK, scoredCheck(t, sc, "NetworkPolicy/networking.k8s.io/v1//podtemplate", "networkpolicy-targets-pod").Grade)
+}
+
+func TestUnsupportedKind(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore([]ks.NamedReader{testFile("unsupported-kind.yaml")}, nil, nil)
+	assert.NoError(t, err)
+	score := scoredCheck(t, sc, "Deployment/apps/v1beat1//typo", "unsupported-kind")
+	assert.Equal(t, scorecard.GradeAlmostOK, score.Grade)
+	assert.True(t, score.Informational)
+	assert.Equal(t, "INFO", score.Label())
+	comments := score.Comments
+	if assert.Len(t, comments, 1) {
+		assert.Equal(t, "The kind apps/v1beat1 Deployment is not supported, did you mean apps/v1beta1 Deployment?", comments[0].Summary)
+	}
+}
+
+func TestUnsupportedKindFail(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, []ks.NamedReader{testFile("unsupported-kind.yaml")}, nil, &config.RunConfiguration{
+		FailOnUnknownKind: true,
+	}, "Unsupported kind", scorecard.GradeCritical)
+}
+
+// scoredCheck returns the result of the check with the id for the object with the key
+func scoredCheck(t *testing.T, sc scorecard.Scorecard, key, id string) scorecard.TestScore {
+	o, ok := sc[key]
+	if !assert.True(t, ok, key) {
+		return scorecard.TestScore{}
+	}
+	for _, c := range o.Checks {
+		if c.Check.ID == id {
+			return c
+		}
+	}
+	assert.Fail(t, "check was not run", id)
+	return scorecard.TestScore{}
+}
+
+func TestRBAC(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore([]ks.NamedReader{testFile("rbac.yaml")}, nil, &config.RunConfiguration{
+		KubernetesVersion: config.Semver{Major: 1, Minor: 18},
+	})
+	assert.NoError(t, err)
+
+	for _, id := range []string{"role-wildcard", "role-escalation-verbs", "role-secrets-access"} {
+		assert.Equal(t, scorecard.GradeAllOK, scoredCheck(t, sc, "Role/rbac.authorization.k8s.io/v1/app/least-privilege", id).Grade, id)
+	}
+
+	wildcard := scoredCheck(t, sc, "ClusterRole/rbac.authorization.k8s.io/v1//too-much", "role-wildcard")
+	assert.Equal(t, scorecard.GradeCritical, wildcard.Grade)
+	if assert.Len(t, wildcard.Comments, 2) {
+		assert.Equal(t, "rules[0].verbs", wildcard.Comments[0].Field)
+		assert.Equal(t, 22, wildcard.Comments[0].Line)
+		assert.Equal(t, "rules[0].resources", wildcard.Comments[1].Field)
+	}
+	escalation := scoredCheck(t, sc, "ClusterRole/rbac.authorization.k8s.io/v1//too-much", "role-escalation-verbs")
+	assert.Equal(t, scorecard.GradeCritical, escalation.Grade)
+	assert.Len(t, escalation.Comments, 3)
+	secrets := scoredCheck(t, sc, "ClusterRole/rbac.authorization.k8s.io/v1//too-much", "role-secrets-access")
+	assert.Equal(t, scorecard.GradeWarning, secrets.Grade)
+	if assert.Len(t, secrets.Comments, 2) {
+		assert.Equal(t, "rules[0]", secrets.Comments[0].Field)
+		assert.Equal(t, "rules[1]", secrets.Comments[1].Field)
+	}
+
+	assert.Equal(t, scorecard.GradeAllOK, scoredCheck(t, sc, "RoleBinding/rbac.authorization.k8s.io/v1/app/app", "rolebinding-targets-role").Grade)
+	assert.Equal(t, scorecard.GradeAllOK, scoredCheck(t, sc, "RoleBinding/rbac.authorization.k8s.io/v1/app/app", "rolebinding-targets-serviceaccount").Grade)
+	assert.Equal(t, scorecard.GradeCritical, scoredCheck(t, sc, "RoleBinding/rbac.authorization.k8s.io/v1/app/missing", "rolebinding-targets-role").Grade)
+	assert.Equal(t, scorecard.GradeCritical, scoredCheck(t, sc, "RoleBinding/rbac.authorization.k8s.io/v1/app/missing", "rolebinding-targets-serviceaccount").Grade)
+
+	assert.Equal(t, scorecard.GradeCritical, scoredCheck(t, sc, "ClusterRoleBinding/rbac.authorization.k8s.io/v1//admin", "rolebinding-cluster-admin").Grade)
+	assert.Equal(t, scorecard.GradeAllOK, scoredCheck(t, sc, "ClusterRoleBinding/rbac.authorization.k8s.io/v1//admin", "rolebinding-targets-role").Grade)
+
+	assert.Equal(t, scorecard.GradeCritical, scoredCheck(t, sc, "Pod/v1/app/app", "pod-serviceaccount-cluster-wide-write-access").Grade)
+	assert.Equal(t, scorecard.GradeAllOK, scoredCheck(t, sc, "Pod/v1/app/other", "pod-serviceaccount-cluster-wide-write-access").Grade)
+}
+
+func TestServiceAccount(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore([]ks.NamedReader{testFile("serviceaccount.yaml")}, nil, &config.RunConfiguration{
+		KubernetesVersion: config.Semver{Major: 1, Minor: 18},
+		EnabledOptionalTests: map[string]struct{}{
+			"pod-serviceaccount":                 {},
+			"pod-serviceaccount-token-automount": {},
+			"pod-serviceaccount-shared":          {},
+		},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, scorecard.GradeAllOK, scoredCheck(t, sc, "Deployment/apps/v1/shop/web", "pod-serviceaccount").Grade)
+	assert.Equal(t, scorecard.GradeWarning, scoredCheck(t, sc, "Pod/v1/shop/default", "pod-serviceaccount").Grade)
+	missing := scoredCheck(t, sc, "Pod/v1/shop/missing", "pod-serviceaccount")
+	assert.Equal(t, scorecard.GradeCritical, missing.Grade)
+	if assert.Len(t, missing.Comments, 1) {
+		assert.Equal(t, "The ServiceAccount missing does not exist", missing.Comments[0].Summary)
+		assert.Equal(t, 149, missing.Comments[0].Line)
+	}
+
+	assert.Equal(t, scorecard.GradeWarning, scoredCheck(t, sc, "Deployment/apps/v1/shop/web", "pod-serviceaccount-token-automount").Grade)
+	assert.Equal(t, scorecard.GradeAllOK, scoredCheck(t, sc, "Pod/v1/shop/worker", "pod-serviceaccount-token-automount").Grade)
+	assert.True(t, scoredCheck(t, sc, "Pod/v1/shop/reader", "pod-serviceaccount-token-automount").Skipped)
+	// The default ServiceAccount is only reported by pod-serviceaccount
+	assert.True(t, scoredCheck(t, sc, "Pod/v1/shop/default", "pod-serviceaccount-token-automount").Skipped)
+	token := scoredCheck(t, sc, "Pod/v1/shop/token", "pod-serviceaccount-token-automount")
+	assert.Equal(t, scorecard.GradeWarning, token.Grade)
+	if assert.Len(t, token.Comments, 1) {
+		assert.Equal(t, 117, token.Comments[0].Line)
+	}
+
+	shared := scoredCheck(t, sc, "Deployment/apps/v1/shop/web", "pod-serviceaccount-shared")
+	assert.Equal(t, scorecard.GradeWarning, shared.Grade)
+	if assert.Len(t, shared.Comments, 1) {
+		assert.Contains(t, shared.Comments[0].Description, "The ServiceAccount is also used by: CronJob report.")
+	}
+	assert.Equal(t, scorecard.GradeAllOK, scoredCheck(t, sc, "Pod/v1/shop/worker", "pod-serviceaccount-shared").Grade)
+	assert.True(t, scoredCheck(t, sc, "Pod/v1/shop/default", "pod-serviceaccount-shared").Skipped)
+
+	// The checks are optional
+	assert.True(t, wasSkipped(t, []ks.NamedReader{testFile("serviceaccount.yaml")}, nil, nil, "Pod ServiceAccount"))
+}
+
+func TestConfigRef(t *testing.T) {
+	t.Parallel()
+	run := func(external []string) scorecard.Scorecard {
+		sc, err := testScore([]ks.NamedReader{testFile("configref.yaml")}, nil, &config.RunConfiguration{
+			KubernetesVersion:  config.Semver{Major: 1, Minor: 18},
+			ExternalConfigRefs: external,
+		})
+		assert.NoError(t, err)
+		return sc
+	}
+
+	sc := run([]string{"shop/provided-by-operator"})
+	assert.Equal(t, scorecard.GradeAllOK, scoredCheck(t, sc, "Pod/v1/shop/valid", "pod-configmap-and-secret-references").Grade)
+	// Pods without references have no result of the check
+	for _, c := range sc["Pod/v1/shop/none"].Checks {
+		assert.NotEqual(t, "pod-configmap-and-secret-references", c.Check.ID)
+	}
+
+	invalid := scoredCheck(t, sc, "Deployment/apps/v1/shop/invalid", "pod-configmap-and-secret-references")
+	assert.Equal(t, scorecard.GradeCritical, invalid.Grade)
+	var got []string
+	for _, c := range invalid.Comments {
+		got = append(got, fmt.Sprintf("%d %s %s", c.Line, c.Field, c.Summary))
+	}
+	assert.Equal(t, []string{
+		"101 spec.template.spec.initContainers[0].envFrom[0].configMapRef.name The ConfigMap other-namespace does not exist",
+		"110 spec.template.spec.containers[0].env[0].valueFrom.configMapKeyRef.key The ConfigMap settings does not have the key missing",
+		"114 spec.template.spec.volumes[0].secret.secretName The Secret missing does not exist",
+		"123 spec.template.spec.volumes[1].projected.sources[0].configMap.items[1].key The ConfigMap settings does not have the key missing",
+	}, got)
+
+	// Names without a namespace are external in all namespaces
+	for _, external := range [][]string{nil, {"other/provided-by-operator"}} {
+		valid := scoredCheck(t, run(external), "Pod/v1/shop/valid", "pod-configmap-and-secret-references")
+		assert.Equal(t, scorecard.GradeCritical, valid.Grade)
+		if assert.Len(t, valid.Comments, 1) {
+			assert.Equal(t, "The Secret provided-by-operator does not exist", valid.Comments[0].Summary)
+		}
+	}
+	assert.Equal(t, scorecard.GradeAllOK, scoredCheck(t, run([]string{"provided-by-operator"}), "Pod/v1/shop/valid", "pod-configmap-and-secret-references").Grade)
+}
+
+type widget struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              struct {
+		Size int `json:"size"`
+	} `json:"spec"`
+}
+
+func TestRegisteredKind(t *testing.T) {
+	t.Parallel()
+	p, err := parser.New(nil)
+	assert.NoError(t, err)
+	parser.RegisterKind[widget](p, schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"})
+	parsed, err := p.ParseFiles([]ks.NamedReader{testFile("registered-kind.yaml")})
+	assert.NoError(t, err)
+	assert.Len(t, parsed.Objects(), 2)
+
+	allChecks := RegisterAllChecks(parsed, nil, nil)
+	checks.RegisterObjectCheck(allChecks, "Widget", "Widget size", "Makes sure that widgets are not too large", func(w *widget) (score scorecard.TestScore, err error) {
+		score.Grade = scorecard.GradeAllOK
+		if w.Spec.Size > 10 {
+			score.Grade = scorecard.GradeCritical
+			score.AddCommentWithField("", "The widget is too large", "", "spec.size")
+		}
+		return
+	})
+	// Checks of other types are not run on widgets
+	checks.RegisterObjectCheck(allChecks, "Gadget", "Gadget", "", func(g *struct{}) (scorecard.TestScore, error) {
+		return scorecard.TestScore{Grade: scorecard.GradeCritical}, nil
+	})
+
+	sc, err := Score(parsed, allChecks, &config.RunConfiguration{})
+	assert.NoError(t, err)
+
+	grades := make(map[string]scorecard.Grade)
+	for key, o := range *sc {
+		for _, c := range o.Checks {
+			assert.NotEqual(t, "gadget", c.Check.ID)
+			if c.Check.ID == "widget-size" {
+				grades[key] = c.Grade
+				if c.Grade == scorecard.GradeCritical {
+					assert.Equal(t, 13, c.Comments[0].Line)
+				}
+			}
+		}
+	}
+	assert.Equal(t, map[string]scorecard.Grade{
+		"Widget/example.com/v1//small": scorecard.GradeAllOK,
+		"Widget/example.com/v1//large": scorecard.GradeCritical,
+	}, grades)
+
+	// The meta checks are run on the objects of registered kinds as well
+	o := (*sc)["Widget/example.com/v1//large"]
+	var hasMetaCheck bool
+	for _, c := range o.Checks {
+		hasMetaCheck = hasMetaCheck || c.Check.ID == "label-values"
+	}
+	assert.True(t, hasMetaCheck)
+}
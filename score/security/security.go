@@ -1,14 +1,20 @@
 package security
 
 import (
+	"fmt"
+	"strconv"
+
 	ks "github.com/zegl/kube-score/domain"
 	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/score/internal"
 	"github.com/zegl/kube-score/scorecard"
 	corev1 "k8s.io/api/core/v1"
 )
 
 func Register(allChecks *checks.Checks) {
-	allChecks.RegisterPodCheck("Container Security Context User Group ID", `Makes sure that all pods have a security context with valid UID and GID set `, containerSecurityContextUserGroupID)
+	minUID := checks.RegisterParam(allChecks, "Container Security Context User Group ID", "minUID", 10000, "The lowest user ID that containers are allowed to run as", checks.AtLeast(0))
+	minGID := checks.RegisterParam(allChecks, "Container Security Context User Group ID", "minGID", 10000, "The lowest group ID that containers are allowed to run as", checks.AtLeast(0))
+	allChecks.RegisterPodCheck("Container Security Context User Group ID", `Makes sure that all pods have a security context with valid UID and GID set `, containerSecurityContextUserGroupID(int64(minUID), int64(minGID)))
 	allChecks.RegisterPodCheck("Container Security Context Privileged", "Makes sure that all pods have a unprivileged security context set", containerSecurityContextPrivileged)
 	allChecks.RegisterPodCheck("Container Security Context ReadOnlyRootFilesystem", "Makes sure that all pods have a security context with read only filesystem set", containerSecurityContextReadOnlyRootFilesystem)
 
@@ -23,16 +29,22 @@ func containerSecurityContextReadOnlyRootFilesystem(ps ks.PodSpecer) (score scor
 	noContextSet := false
 	hasWritableRootFS := false
 
-	for _, container := range allContainers {
+	pointers := internal.ContainerPointers(ps)
+	fields := internal.ContainerFields(ps)
+	for i, container := range allContainers {
+		fix := scorecard.PatchOperation{Op: "add", Path: pointers[i] + "/securityContext/readOnlyRootFilesystem", Value: true}
+
 		if container.SecurityContext == nil {
 			noContextSet = true
-			score.AddComment(container.Name, "Container has no configured security context", "Set securityContext to run the container in a more secure context.")
+			score.AddCommentWithField(container.Name, "Container has no configured security context", "Set securityContext to run the container in a more secure context.", fields[i]+".securityContext")
+			score.AddFix(container.Name, "Set securityContext.readOnlyRootFilesystem to true", fix)
 			continue
 		}
 		sec := container.SecurityContext
 		if sec.ReadOnlyRootFilesystem == nil || !*sec.ReadOnlyRootFilesystem {
 			hasWritableRootFS = true
-			score.AddComment(container.Name, "The pod has a container with a writable root filesystem", "Set securityContext.readOnlyRootFilesystem to true")
+			score.AddCommentWithField(container.Name, "The pod has a container with a writable root filesystem", "Set securityContext.readOnlyRootFilesystem to true", fields[i]+".securityContext.readOnlyRootFilesystem")
+			score.AddFix(container.Name, "Set securityContext.readOnlyRootFilesystem to true", fix)
 		}
 	}
 
@@ -50,10 +62,11 @@ func containerSecurityContextPrivileged(ps ks.PodSpecer) (score scorecard.TestSc
 	allContainers := ps.GetPodTemplateSpec().Spec.InitContainers
 	allContainers = append(allContainers, ps.GetPodTemplateSpec().Spec.Containers...)
 	hasPrivileged := false
-	for _, container := range allContainers {
+	fields := internal.ContainerFields(ps)
+	for i, container := range allContainers {
 		if container.SecurityContext != nil && container.SecurityContext.Privileged != nil && *container.SecurityContext.Privileged {
 			hasPrivileged = true
-			score.AddComment(container.Name, "The container is privileged", "Set securityContext.privileged to false. Privileged containers can access all devices on the host, and grants almost the same access as non-containerized processes on the host.")
+			score.AddCommentWithField(container.Name, "The container is privileged", "Set securityContext.privileged to false. Privileged containers can access all devices on the host, and grants almost the same access as non-containerized processes on the host.", fields[i]+".securityContext.privileged")
 		}
 	}
 	if hasPrivileged {
@@ -64,49 +77,61 @@ func containerSecurityContextPrivileged(ps ks.PodSpecer) (score scorecard.TestSc
 	return
 }
 
-// containerSecurityContextUserGroupID checks that the user and group are valid ( > 10000) in the security context
-func containerSecurityContextUserGroupID(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
-	allContainers := ps.GetPodTemplateSpec().Spec.InitContainers
-	allContainers = append(allContainers, ps.GetPodTemplateSpec().Spec.Containers...)
-	podSecurityContext := ps.GetPodTemplateSpec().Spec.SecurityContext
-	noContextSet := false
-	hasLowUserID := false
-	hasLowGroupID := false
-	for _, container := range allContainers {
-		if container.SecurityContext == nil && podSecurityContext == nil {
-			noContextSet = true
-			score.AddComment(container.Name, "Container has no configured security context", "Set securityContext to run the container in a more secure context.")
-			continue
-		}
-		sec := container.SecurityContext
-		if sec == nil {
-			sec = &corev1.SecurityContext{}
-		}
-		// Forward values from PodSecurityContext to the (container level) SecurityContext if not set
-		if podSecurityContext != nil {
-			if sec.RunAsGroup == nil {
-				sec.RunAsGroup = podSecurityContext.RunAsGroup
+// containerSecurityContextUserGroupID checks that the user and group are valid (>= minUID and >= minGID) in the security context
+func containerSecurityContextUserGroupID(minUID, minGID int64) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		allContainers := ps.GetPodTemplateSpec().Spec.InitContainers
+		allContainers = append(allContainers, ps.GetPodTemplateSpec().Spec.Containers...)
+		podSecurityContext := ps.GetPodTemplateSpec().Spec.SecurityContext
+		noContextSet := false
+		hasLowUserID := false
+		hasLowGroupID := false
+		fields := internal.ContainerFields(ps)
+		for i, container := range allContainers {
+			if container.SecurityContext == nil && podSecurityContext == nil {
+				noContextSet = true
+				score.AddCommentWithField(container.Name, "Container has no configured security context", "Set securityContext to run the container in a more secure context.", fields[i]+".securityContext")
+				continue
 			}
-			if sec.RunAsUser == nil {
-				sec.RunAsUser = podSecurityContext.RunAsUser
+			sec := container.SecurityContext
+			if sec == nil {
+				sec = &corev1.SecurityContext{}
+			}
+			// Forward values from PodSecurityContext to the (container level) SecurityContext if not set
+			if podSecurityContext != nil {
+				if sec.RunAsGroup == nil {
+					sec.RunAsGroup = podSecurityContext.RunAsGroup
+				}
+				if sec.RunAsUser == nil {
+					sec.RunAsUser = podSecurityContext.RunAsUser
+				}
+			}
+			if sec.RunAsUser == nil || *sec.RunAsUser < minUID {
+				hasLowUserID = true
+				score.AddCommentWithField(container.Name, "The container is running with a low user ID", fmt.Sprintf("A userid above %s is recommended to avoid conflicts with the host. Set securityContext.runAsUser to a value > %d", groupThousands(minUID), minUID), fields[i]+".securityContext.runAsUser")
 			}
-		}
-		if sec.RunAsUser == nil || *sec.RunAsUser < 10000 {
-			hasLowUserID = true
-			score.AddComment(container.Name, "The container is running with a low user ID", "A userid above 10 000 is recommended to avoid conflicts with the host. Set securityContext.runAsUser to a value > 10000")
-		}
 
-		if sec.RunAsGroup == nil || *sec.RunAsGroup < 10000 {
-			hasLowGroupID = true
-			score.AddComment(container.Name, "The container running with a low group ID", "A groupid above 10 000 is recommended to avoid conflicts with the host. Set securityContext.runAsGroup to a value > 10000")
+			if sec.RunAsGroup == nil || *sec.RunAsGroup < minGID {
+				hasLowGroupID = true
+				score.AddCommentWithField(container.Name, "The container running with a low group ID", fmt.Sprintf("A groupid above %s is recommended to avoid conflicts with the host. Set securityContext.runAsGroup to a value > %d", groupThousands(minGID), minGID), fields[i]+".securityContext.runAsGroup")
+			}
+		}
+		if noContextSet || hasLowUserID || hasLowGroupID {
+			score.Grade = scorecard.GradeCritical
+		} else {
+			score.Grade = scorecard.GradeAllOK
 		}
+		return
 	}
-	if noContextSet || hasLowUserID || hasLowGroupID {
-		score.Grade = scorecard.GradeCritical
-	} else {
-		score.Grade = scorecard.GradeAllOK
+}
+
+// groupThousands formats n with a space between each group of thousands, 10000 is formatted as "10 000"
+func groupThousands(n int64) string {
+	s := strconv.FormatInt(n, 10)
+	for i := len(s) - 3; i > 0 && s[i-1] != '-'; i -= 3 {
+		s = s[:i] + " " + s[i:]
 	}
-	return
+	return s
 }
 
 // podSeccompProfile checks that a Seccommp profile is configured. The
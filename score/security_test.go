@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/zegl/kube-score/config"
 	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/score/checks"
 	"github.com/zegl/kube-score/scorecard"
 )
 
@@ -113,6 +114,9 @@ func TestContainerSecurityContextUserGroupIDLowGroup(t *testing.T) {
 		Path:        "foobar",
 		Summary:     "The container running with a low group ID",
 		Description: "A groupid above 10 000 is recommended to avoid conflicts with the host. Set securityContext.runAsGroup to a value > 10000",
+		Field:       "spec.containers[0].securityContext.runAsGroup",
+		Line:        11,
+		Column:      7,
 	})
 }
 
@@ -127,6 +131,9 @@ func TestContainerSecurityContextUserGroupIDLowUser(t *testing.T) {
 		Path:        "foobar",
 		Summary:     "The container is running with a low user ID",
 		Description: "A userid above 10 000 is recommended to avoid conflicts with the host. Set securityContext.runAsUser to a value > 10000",
+		Field:       "spec.containers[0].securityContext.runAsUser",
+		Line:        10,
+		Column:      7,
 	})
 }
 
@@ -142,6 +149,9 @@ func TestContainerSecurityContextUserGroupIDNoSecurityContext(t *testing.T) {
 		Path:        "foobar",
 		Summary:     "Container has no configured security context",
 		Description: "Set securityContext to run the container in a more secure context.",
+		Field:       "spec.containers[0].securityContext",
+		Line:        7,
+		Column:      5,
 	})
 }
 
@@ -166,6 +176,9 @@ func TestContainerSecurityContextPrivilegedPrivileged(t *testing.T) {
 		Path:        "foobar",
 		Summary:     "The container is privileged",
 		Description: "Set securityContext.privileged to false. Privileged containers can access all devices on the host, and grants almost the same access as non-containerized processes on the host.",
+		Field:       "spec.containers[0].securityContext.privileged",
+		Line:        10,
+		Column:      7,
 	})
 }
 
@@ -190,6 +203,9 @@ func TestContainerSecurityContextReadOnlyRootFilesystemWriteable(t *testing.T) {
 		Path:        "foobar",
 		Summary:     "The pod has a container with a writable root filesystem",
 		Description: "Set securityContext.readOnlyRootFilesystem to true",
+		Field:       "spec.containers[0].securityContext.readOnlyRootFilesystem",
+		Line:        9,
+		Column:      5,
 	})
 }
 
@@ -204,5 +220,34 @@ func TestContainerSecurityContextReadOnlyRootFilesystemNoSecurityContext(t *test
 		Path:        "foobar",
 		Summary:     "Container has no configured security context",
 		Description: "Set securityContext to run the container in a more secure context.",
+		Field:       "spec.containers[0].securityContext",
+		Line:        7,
+		Column:      5,
 	})
 }
+
+func TestContainerSecurityContextUserGroupIDParams(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, []ks.NamedReader{testFile("pod-security-context-low-group-id.yaml")}, &checks.Config{
+		Params: map[string]string{
+			"container-security-context-user-group-id.minGID": "50000",
+		},
+	}, nil, "Container Security Context User Group ID", scorecard.GradeCritical)
+	assert.Contains(t, comments, scorecard.TestScoreComment{
+		Path:        "foobar",
+		Summary:     "The container running with a low group ID",
+		Description: "A groupid above 50 000 is recommended to avoid conflicts with the host. Set securityContext.runAsGroup to a value > 50000",
+		Field:       "spec.containers[0].securityContext.runAsGroup",
+		Line:        11,
+		Column:      7,
+	})
+}
+
+func TestContainerSecurityContextUserGroupIDParamsLowered(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, []ks.NamedReader{testFile("pod-security-context-low-group-id.yaml")}, &checks.Config{
+		Params: map[string]string{
+			"container-security-context-user-group-id.minGID": "0",
+		},
+	}, nil, "Container Security Context User Group ID", scorecard.GradeAllOK)
+}
@@ -0,0 +1,181 @@
+package serviceaccount
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/score/internal"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func Register(allChecks *checks.Checks, serviceAccounts ks.ServiceAccounts, bindings ks.RoleBindings, pods ks.Pods, podSpeccers ks.PodSpeccers) {
+	allChecks.RegisterOptionalPodCheck("Pod ServiceAccount", `Makes sure that the pod runs as a ServiceAccount that is in the manifests, and not as the default ServiceAccount`, podServiceAccount(serviceAccounts.ServiceAccounts()))
+	allChecks.RegisterOptionalPodCheck("Pod ServiceAccount token automount", `Makes sure that the ServiceAccount token is not mounted in the pod if the ServiceAccount is not bound to any role`, podTokenAutomount(serviceAccounts.ServiceAccounts(), bindings.RoleBindings()))
+	allChecks.RegisterOptionalPodCheck("Pod ServiceAccount shared", `Makes sure that the ServiceAccount of the pod is not shared with unrelated workloads`, podServiceAccountShared(workloads(pods.Pods(), podSpeccers.PodSpeccers())))
+}
+
+// relatedLabels are the labels that group workloads into an application. Workloads that have the same value of any of
+// these labels are related.
+var relatedLabels = []string{"app.kubernetes.io/part-of", "app.kubernetes.io/name", "app"}
+
+type workload struct {
+	kind     string
+	meta     metav1.ObjectMeta
+	template corev1.PodTemplateSpec
+}
+
+func workloads(pods []ks.Pod, podSpeccers []ks.PodSpecer) []workload {
+	res := make([]workload, 0, len(pods)+len(podSpeccers))
+	for _, pod := range pods {
+		p := pod.Pod()
+		res = append(res, workload{
+			kind:     p.Kind,
+			meta:     p.ObjectMeta,
+			template: corev1.PodTemplateSpec{ObjectMeta: p.ObjectMeta, Spec: p.Spec},
+		})
+	}
+	for _, ps := range podSpeccers {
+		res = append(res, workload{
+			kind:     ps.GetTypeMeta().Kind,
+			meta:     ps.GetObjectMeta(),
+			template: ps.GetPodTemplateSpec(),
+		})
+	}
+	return res
+}
+
+// related returns true if the workloads are a part of the same application, see relatedLabels
+func (w workload) related(other workload) bool {
+	for _, label := range relatedLabels {
+		for _, a := range []map[string]string{w.meta.Labels, w.template.Labels} {
+			for _, b := range []map[string]string{other.meta.Labels, other.template.Labels} {
+				if value, ok := a[label]; ok && value != "" && b[label] == value {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func podServiceAccount(serviceAccounts []ks.ServiceAccount) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		template := ps.GetPodTemplateSpec()
+		name := internal.ServiceAccountName(template.Spec)
+
+		if name == "default" {
+			score.Grade = scorecard.GradeWarning
+			score.AddComment("", "The pod runs as the default ServiceAccount",
+				"All pods in the namespace that don't set a serviceAccountName share the default ServiceAccount, and the access that is granted to it. Create a ServiceAccount for the workload and set serviceAccountName.")
+			return
+		}
+
+		if _, ok := findServiceAccount(serviceAccounts, name, template.Namespace); !ok {
+			score.Grade = scorecard.GradeCritical
+			score.AddCommentWithField("", fmt.Sprintf("The ServiceAccount %s does not exist", name),
+				fmt.Sprintf("No ServiceAccount named %s was found in the namespace %q. The pod can not be created if the ServiceAccount does not exist.", name, template.Namespace),
+				internal.PodSpecField(ps)+".serviceAccountName")
+			return
+		}
+
+		score.Grade = scorecard.GradeAllOK
+		return
+	}
+}
+
+func podTokenAutomount(serviceAccounts []ks.ServiceAccount, bindings []ks.RoleBinding) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		template := ps.GetPodTemplateSpec()
+		name := internal.ServiceAccountName(template.Spec)
+
+		// The default ServiceAccount is reported by the "Pod ServiceAccount" check
+		if name == "default" {
+			score.Skipped = true
+			score.AddComment("", "Skipped because the pod runs as the default ServiceAccount", "")
+			return
+		}
+
+		for _, binding := range bindings {
+			if internal.BindsServiceAccount(binding, name, template.Namespace) {
+				score.Skipped = true
+				score.AddComment("", fmt.Sprintf("Skipped because the ServiceAccount %s is bound by the %s %s", name, binding.GetTypeMeta().Kind, binding.GetObjectMeta().Name), "")
+				return
+			}
+		}
+
+		// The automountServiceAccountToken of the pod has precedence over the one of the ServiceAccount
+		automount := template.Spec.AutomountServiceAccountToken
+		if automount == nil {
+			if sa, ok := findServiceAccount(serviceAccounts, name, template.Namespace); ok {
+				automount = sa.ServiceAccount().AutomountServiceAccountToken
+			}
+		}
+
+		if automount != nil && !*automount {
+			score.Grade = scorecard.GradeAllOK
+			return
+		}
+
+		score.Grade = scorecard.GradeWarning
+		description := fmt.Sprintf("The ServiceAccount %s is not bound to any role, and the pod does not need its token. Set automountServiceAccountToken to false on the pod or on the ServiceAccount.", name)
+		if template.Spec.AutomountServiceAccountToken != nil {
+			score.AddCommentWithField("", "The ServiceAccount token is mounted in the pod", description,
+				internal.PodSpecField(ps)+".automountServiceAccountToken")
+		} else {
+			score.AddComment("", "The ServiceAccount token is mounted in the pod", description)
+		}
+		return
+	}
+}
+
+func podServiceAccountShared(all []workload) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		self := workload{kind: ps.GetTypeMeta().Kind, meta: ps.GetObjectMeta(), template: ps.GetPodTemplateSpec()}
+		name := internal.ServiceAccountName(self.template.Spec)
+
+		// The default ServiceAccount is reported by the "Pod ServiceAccount" check
+		if name == "default" {
+			score.Skipped = true
+			score.AddComment("", "Skipped because the pod runs as the default ServiceAccount", "")
+			return
+		}
+
+		var unrelated []string
+		for _, other := range all {
+			if other.kind == self.kind && other.meta.Name == self.meta.Name && other.meta.Namespace == self.meta.Namespace {
+				continue
+			}
+			if other.meta.Namespace != self.meta.Namespace || internal.ServiceAccountName(other.template.Spec) != name {
+				continue
+			}
+			if self.related(other) {
+				continue
+			}
+			unrelated = append(unrelated, other.kind+" "+other.meta.Name)
+		}
+
+		if len(unrelated) > 0 {
+			score.Grade = scorecard.GradeWarning
+			score.AddComment("", fmt.Sprintf("The ServiceAccount %s is shared with unrelated workloads", name),
+				fmt.Sprintf("The ServiceAccount is also used by: %s. Workloads that share a ServiceAccount have the access that is granted for all of them. Use a ServiceAccount per workload, or set the app.kubernetes.io/part-of label if the workloads are a part of the same application.", strings.Join(unrelated, ", ")))
+			return
+		}
+
+		score.Grade = scorecard.GradeAllOK
+		return
+	}
+}
+
+func findServiceAccount(serviceAccounts []ks.ServiceAccount, name, namespace string) (ks.ServiceAccount, bool) {
+	for _, sa := range serviceAccounts {
+		if sa.ServiceAccount().Name == name && sa.ServiceAccount().Namespace == namespace {
+			return sa, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,67 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zegl/kube-score/config"
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+func testSeverityOverride(t *testing.T, filename, testcase string, overrides map[string]config.Severity) scorecard.TestScore {
+	sc, err := testScore([]ks.NamedReader{testFile(filename)}, nil, &config.RunConfiguration{
+		SeverityOverrides: overrides,
+	})
+	assert.NoError(t, err)
+
+	for _, objectScore := range sc {
+		for _, s := range objectScore.Checks {
+			if s.Check.Name == testcase {
+				return s
+			}
+		}
+	}
+
+	t.Error("Was not tested")
+	return scorecard.TestScore{}
+}
+
+func TestSeverityOverrideDowngrade(t *testing.T) {
+	t.Parallel()
+	s := testSeverityOverride(t, "pod-image-pullpolicy-never.yaml", "Container Image Pull Policy", map[string]config.Severity{
+		"container-image-pull-policy": config.SeverityInfo,
+	})
+	assert.Equal(t, scorecard.GradeAlmostOK, s.Grade)
+	assert.Equal(t, scorecard.GradeCritical, s.OriginalGrade)
+	assert.True(t, s.Informational)
+	assert.Equal(t, "INFO", s.Label())
+}
+
+func TestSeverityOverrideUpgrade(t *testing.T) {
+	t.Parallel()
+	s := testSeverityOverride(t, "pod-test-resources-only-limits.yaml", "Container Resources", map[string]config.Severity{
+		"container-resources": config.SeverityCritical,
+	})
+	assert.Equal(t, scorecard.GradeCritical, s.Grade)
+	assert.Equal(t, scorecard.GradeWarning, s.OriginalGrade)
+	assert.False(t, s.Informational)
+}
+
+func TestSeverityOverrideDoesNotAffectPassingChecks(t *testing.T) {
+	t.Parallel()
+	s := testSeverityOverride(t, "pod-image-pullpolicy-always.yaml", "Container Image Pull Policy", map[string]config.Severity{
+		"container-image-pull-policy": config.SeverityCritical,
+	})
+	assert.Equal(t, scorecard.GradeAllOK, s.Grade)
+	assert.Equal(t, scorecard.Grade(0), s.OriginalGrade)
+}
+
+func TestSeverityOverrideOtherCheck(t *testing.T) {
+	t.Parallel()
+	s := testSeverityOverride(t, "pod-image-pullpolicy-never.yaml", "Container Image Pull Policy", map[string]config.Severity{
+		"container-resources": config.SeverityOK,
+	})
+	assert.Equal(t, scorecard.GradeCritical, s.Grade)
+	assert.Equal(t, scorecard.Grade(0), s.OriginalGrade)
+}
@@ -21,12 +21,25 @@ func metaStableAvailable(kubernetsVersion config.Semver) func(meta domain.BothMe
 			availableSince config.Semver
 		}
 
+		type recommendedKind struct {
+			newKind string
+			recommendedApi
+		}
+
+		// Kinds that are superseded by another kind
+		supersededBy := map[string]map[string]recommendedKind{
+			"v1": {
+				"ReplicationController": recommendedKind{"Deployment", recommendedApi{"apps/v1", config.Semver{Major: 1, Minor: 9}}},
+			},
+		}
+
 		withStable := map[string]map[string]recommendedApi{
 			"extensions/v1beta1": {
 				"Deployment":   recommendedApi{"apps/v1", config.Semver{Major: 1, Minor: 9}},
 				"DaemonSet":    recommendedApi{"apps/v1", config.Semver{Major: 1, Minor: 9}},
 				"Ingress":      recommendedApi{"networking.k8s.io/v1", config.Semver{Major: 1, Minor: 19}},
 				"IngressClass": recommendedApi{"networking.k8s.io/v1", config.Semver{Major: 1, Minor: 19}},
+				"ReplicaSet":   recommendedApi{"apps/v1", config.Semver{Major: 1, Minor: 9}},
 			},
 			"apps/v1beta1": {
 				"Deployment":  recommendedApi{"apps/v1", config.Semver{Major: 1, Minor: 9}},
@@ -36,6 +49,7 @@ func metaStableAvailable(kubernetsVersion config.Semver) func(meta domain.BothMe
 				"Deployment":  recommendedApi{"apps/v1", config.Semver{Major: 1, Minor: 9}},
 				"StatefulSet": recommendedApi{"apps/v1", config.Semver{Major: 1, Minor: 9}},
 				"DaemonSet":   recommendedApi{"apps/v1", config.Semver{Major: 1, Minor: 9}},
+				"ReplicaSet":  recommendedApi{"apps/v1", config.Semver{Major: 1, Minor: 9}},
 			},
 			"batch/v1beta1": {
 				"CronJob": recommendedApi{"batch/v1", config.Semver{Major: 1, Minor: 21}},
@@ -47,6 +61,12 @@ func metaStableAvailable(kubernetsVersion config.Semver) func(meta domain.BothMe
 				"Ingress":      recommendedApi{"networking.k8s.io/v1", config.Semver{Major: 1, Minor: 19}},
 				"IngressClass": recommendedApi{"networking.k8s.io/v1", config.Semver{Major: 1, Minor: 19}},
 			},
+			"rbac.authorization.k8s.io/v1beta1": {
+				"Role":               recommendedApi{"rbac.authorization.k8s.io/v1", config.Semver{Major: 1, Minor: 8}},
+				"ClusterRole":        recommendedApi{"rbac.authorization.k8s.io/v1", config.Semver{Major: 1, Minor: 8}},
+				"RoleBinding":        recommendedApi{"rbac.authorization.k8s.io/v1", config.Semver{Major: 1, Minor: 8}},
+				"ClusterRoleBinding": recommendedApi{"rbac.authorization.k8s.io/v1", config.Semver{Major: 1, Minor: 8}},
+			},
 			"autoscaling/v2beta1": {
 				"HorizontalPodAutoscaler": recommendedApi{"autoscaling/v2", config.Semver{Major: 1, Minor: 23}},
 			},
@@ -57,6 +77,22 @@ func metaStableAvailable(kubernetsVersion config.Semver) func(meta domain.BothMe
 
 		score.Grade = scorecard.GradeAllOK
 
+		if inVersion, ok := supersededBy[meta.TypeMeta.APIVersion]; ok {
+			if recKind, ok := inVersion[meta.TypeMeta.Kind]; ok {
+				if kubernetsVersion.LessThan(recKind.availableSince) {
+					return
+				}
+
+				score.Grade = scorecard.GradeWarning
+				score.AddCommentWithField("",
+					fmt.Sprintf("The kind %s is superseded by %s", meta.TypeMeta.Kind, recKind.newKind),
+					fmt.Sprintf("It's recommended to migrate to a %s of %s instead which has been available since Kubernetes %s", recKind.newKind, recKind.newAPI, recKind.availableSince.String()),
+					"kind",
+				)
+				return
+			}
+		}
+
 		if inVersion, ok := withStable[meta.TypeMeta.APIVersion]; ok {
 			if recAPI, ok := inVersion[meta.TypeMeta.Kind]; ok {
 
@@ -67,9 +103,10 @@ func metaStableAvailable(kubernetsVersion config.Semver) func(meta domain.BothMe
 				}
 
 				score.Grade = scorecard.GradeWarning
-				score.AddComment("",
+				score.AddCommentWithField("",
 					fmt.Sprintf("The apiVersion and kind %s/%s is deprecated", meta.TypeMeta.APIVersion, meta.TypeMeta.Kind),
 					fmt.Sprintf("It's recommended to use %s instead which has been available since Kubernetes %s", recAPI.newAPI, recAPI.availableSince.String()),
+					"apiVersion",
 				)
 				return
 			}
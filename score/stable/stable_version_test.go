@@ -22,40 +22,54 @@ func TestStableVersionNewKubernetesVersion(t *testing.T) {
 	newKubernetes := metaStableAvailable(config.Semver{Major: 1, Minor: 18})
 	scoreNew, _ := newKubernetes(ks.BothMeta{TypeMeta: v1.TypeMeta{Kind: "Deployment", APIVersion: "extensions/v1beta1"}})
 	assert.Equal(t, scorecard.GradeWarning, scoreNew.Grade)
-	assert.Equal(t, []scorecard.TestScoreComment{{Path: "", Summary: "The apiVersion and kind extensions/v1beta1/Deployment is deprecated", Description: "It's recommended to use apps/v1 instead which has been available since Kubernetes v1.9", DocumentationURL: ""}}, scoreNew.Comments)
+	assert.Equal(t, []scorecard.TestScoreComment{{Path: "", Summary: "The apiVersion and kind extensions/v1beta1/Deployment is deprecated", Description: "It's recommended to use apps/v1 instead which has been available since Kubernetes v1.9", DocumentationURL: "", Field: "apiVersion"}}, scoreNew.Comments)
 }
 
 func TestStableVersionIngress(t *testing.T) {
 	newKubernetes := metaStableAvailable(config.Semver{Major: 1, Minor: 20})
 	scoreNew, _ := newKubernetes(ks.BothMeta{TypeMeta: v1.TypeMeta{Kind: "Ingress", APIVersion: "extensions/v1beta1"}})
 	assert.Equal(t, scorecard.GradeWarning, scoreNew.Grade)
-	assert.Equal(t, []scorecard.TestScoreComment{{Path: "", Summary: "The apiVersion and kind extensions/v1beta1/Ingress is deprecated", Description: "It's recommended to use networking.k8s.io/v1 instead which has been available since Kubernetes v1.19", DocumentationURL: ""}}, scoreNew.Comments)
+	assert.Equal(t, []scorecard.TestScoreComment{{Path: "", Summary: "The apiVersion and kind extensions/v1beta1/Ingress is deprecated", Description: "It's recommended to use networking.k8s.io/v1 instead which has been available since Kubernetes v1.19", DocumentationURL: "", Field: "apiVersion"}}, scoreNew.Comments)
 }
 
 func TestStableVersionPodDisruptionBudget(t *testing.T) {
 	newKubernetes := metaStableAvailable(config.Semver{Major: 1, Minor: 21})
 	scoreNew, _ := newKubernetes(ks.BothMeta{TypeMeta: v1.TypeMeta{Kind: "PodDisruptionBudget", APIVersion: "policy/v1beta1"}})
 	assert.Equal(t, scorecard.GradeWarning, scoreNew.Grade)
-	assert.Equal(t, []scorecard.TestScoreComment{{Path: "", Summary: "The apiVersion and kind policy/v1beta1/PodDisruptionBudget is deprecated", Description: "It's recommended to use policy/v1 instead which has been available since Kubernetes v1.21", DocumentationURL: ""}}, scoreNew.Comments)
+	assert.Equal(t, []scorecard.TestScoreComment{{Path: "", Summary: "The apiVersion and kind policy/v1beta1/PodDisruptionBudget is deprecated", Description: "It's recommended to use policy/v1 instead which has been available since Kubernetes v1.21", DocumentationURL: "", Field: "apiVersion"}}, scoreNew.Comments)
 }
 
 func TestStableHorizontalPodAutoscalerV2beta1(t *testing.T) {
 	newKubernetes := metaStableAvailable(config.Semver{Major: 1, Minor: 26})
 	scoreNew, _ := newKubernetes(ks.BothMeta{TypeMeta: v1.TypeMeta{Kind: "HorizontalPodAutoscaler", APIVersion: "autoscaling/v2beta1"}})
 	assert.Equal(t, scorecard.GradeWarning, scoreNew.Grade)
-	assert.Equal(t, []scorecard.TestScoreComment{{Path: "", Summary: "The apiVersion and kind autoscaling/v2beta1/HorizontalPodAutoscaler is deprecated", Description: "It's recommended to use autoscaling/v2 instead which has been available since Kubernetes v1.23", DocumentationURL: ""}}, scoreNew.Comments)
+	assert.Equal(t, []scorecard.TestScoreComment{{Path: "", Summary: "The apiVersion and kind autoscaling/v2beta1/HorizontalPodAutoscaler is deprecated", Description: "It's recommended to use autoscaling/v2 instead which has been available since Kubernetes v1.23", DocumentationURL: "", Field: "apiVersion"}}, scoreNew.Comments)
 }
 
 func TestStableHorizontalPodAutoscalerV2beta2(t *testing.T) {
 	newKubernetes := metaStableAvailable(config.Semver{Major: 1, Minor: 26})
 	scoreNew, _ := newKubernetes(ks.BothMeta{TypeMeta: v1.TypeMeta{Kind: "HorizontalPodAutoscaler", APIVersion: "autoscaling/v2beta2"}})
 	assert.Equal(t, scorecard.GradeWarning, scoreNew.Grade)
-	assert.Equal(t, []scorecard.TestScoreComment{{Path: "", Summary: "The apiVersion and kind autoscaling/v2beta2/HorizontalPodAutoscaler is deprecated", Description: "It's recommended to use autoscaling/v2 instead which has been available since Kubernetes v1.23", DocumentationURL: ""}}, scoreNew.Comments)
+	assert.Equal(t, []scorecard.TestScoreComment{{Path: "", Summary: "The apiVersion and kind autoscaling/v2beta2/HorizontalPodAutoscaler is deprecated", Description: "It's recommended to use autoscaling/v2 instead which has been available since Kubernetes v1.23", DocumentationURL: "", Field: "apiVersion"}}, scoreNew.Comments)
 }
 
 func TestStableNetworkingIngress(t *testing.T) {
 	newKubernetes := metaStableAvailable(config.Semver{Major: 1, Minor: 21})
 	scoreNew, _ := newKubernetes(ks.BothMeta{TypeMeta: v1.TypeMeta{Kind: "Ingress", APIVersion: "networking.k8s.io/v1beta1"}})
 	assert.Equal(t, scorecard.GradeWarning, scoreNew.Grade)
-	assert.Equal(t, []scorecard.TestScoreComment{{Path: "", Summary: "The apiVersion and kind networking.k8s.io/v1beta1/Ingress is deprecated", Description: "It's recommended to use networking.k8s.io/v1 instead which has been available since Kubernetes v1.19", DocumentationURL: ""}}, scoreNew.Comments)
+	assert.Equal(t, []scorecard.TestScoreComment{{Path: "", Summary: "The apiVersion and kind networking.k8s.io/v1beta1/Ingress is deprecated", Description: "It's recommended to use networking.k8s.io/v1 instead which has been available since Kubernetes v1.19", DocumentationURL: "", Field: "apiVersion"}}, scoreNew.Comments)
+}
+
+func TestStableVersionReplicationController(t *testing.T) {
+	newKubernetes := metaStableAvailable(config.Semver{Major: 1, Minor: 18})
+	scoreNew, _ := newKubernetes(ks.BothMeta{TypeMeta: v1.TypeMeta{Kind: "ReplicationController", APIVersion: "v1"}})
+	assert.Equal(t, scorecard.GradeWarning, scoreNew.Grade)
+	assert.Equal(t, []scorecard.TestScoreComment{{Path: "", Summary: "The kind ReplicationController is superseded by Deployment", Description: "It's recommended to migrate to a Deployment of apps/v1 instead which has been available since Kubernetes v1.9", DocumentationURL: "", Field: "kind"}}, scoreNew.Comments)
+}
+
+func TestStableVersionClusterRoleBinding(t *testing.T) {
+	newKubernetes := metaStableAvailable(config.Semver{Major: 1, Minor: 18})
+	scoreNew, _ := newKubernetes(ks.BothMeta{TypeMeta: v1.TypeMeta{Kind: "ClusterRoleBinding", APIVersion: "rbac.authorization.k8s.io/v1beta1"}})
+	assert.Equal(t, scorecard.GradeWarning, scoreNew.Grade)
+	assert.Equal(t, []scorecard.TestScoreComment{{Path: "", Summary: "The apiVersion and kind rbac.authorization.k8s.io/v1beta1/ClusterRoleBinding is deprecated", Description: "It's recommended to use rbac.authorization.k8s.io/v1 instead which has been available since Kubernetes v1.8", DocumentationURL: "", Field: "apiVersion"}}, scoreNew.Comments)
 }
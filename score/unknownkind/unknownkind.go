@@ -0,0 +1,39 @@
+package unknownkind
+
+import (
+	"fmt"
+
+	ks "github.com/zegl/kube-score/domain"
+	"github.com/zegl/kube-score/score/checks"
+	"github.com/zegl/kube-score/scorecard"
+)
+
+// Register registers the "Unsupported kind" check. The documents of unsupported kinds are informational, unless
+// failOnUnknownKind is set.
+func Register(allChecks *checks.Checks, failOnUnknownKind bool) {
+	allChecks.RegisterUnknownKindCheck("Unsupported kind", `Reports the documents of kinds that are not supported by kube-score, and are not scored`, unsupportedKind(failOnUnknownKind))
+}
+
+// unsupportedKind reports that a document was not scored because kube-score does not support its kind
+func unsupportedKind(failOnUnknownKind bool) func(ks.UnknownKind) (scorecard.TestScore, error) {
+	return func(u ks.UnknownKind) (score scorecard.TestScore, err error) {
+		score.Grade = scorecard.GradeAlmostOK
+		score.Informational = true
+		if failOnUnknownKind {
+			score.Grade = scorecard.GradeCritical
+			score.Informational = false
+		}
+
+		if u.TypeMeta.APIVersion == "" || u.TypeMeta.Kind == "" {
+			score.AddComment("", "The document does not have an apiVersion and kind", "Documents without an apiVersion and kind are not Kubernetes objects, and are not scored")
+			return
+		}
+
+		summary := fmt.Sprintf("The kind %s %s is not supported", u.TypeMeta.APIVersion, u.TypeMeta.Kind)
+		if u.Suggestion != "" {
+			summary += fmt.Sprintf(", did you mean %s?", u.Suggestion)
+		}
+		score.AddComment("", summary, "The objects of this kind are not scored by kube-score")
+		return
+	}
+}
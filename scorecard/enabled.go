@@ -1,52 +1,201 @@
 package scorecard
 
 import (
+	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	ks "github.com/zegl/kube-score/domain"
 )
 
-func (so *ScoredObject) isEnabled(check ks.Check, annotations, childAnnotations map[string]string) bool {
+// ignoredCheckAnnotationPrefix is the prefix of the annotations that ignore a single check, with a justification,
+// "kube-score/ignore.<check-id>: until=2026-12-31; reason=JIRA-123"
+const ignoredCheckAnnotationPrefix = ignoredChecksAnnotation + "."
+
+// waiver is an ignore annotation that matches a check
+type waiver struct {
+	// annotation is the name of the annotation that the waiver was read from
+	annotation string
+	reason     string
+	// until is the last day that the waiver is valid, zero if the waiver doesn't expire
+	until time.Time
+	// problem is set if the waiver should not be honored
+	problem string
+}
+
+// checkState is the result of isEnabled
+type checkState struct {
+	enabled bool
+	// waiver is the ignore annotation that disabled the check, if any
+	waiver *waiver
+	// rejected are the ignore annotations that matched the check, but were not honored
+	rejected []*waiver
+}
+
+func (so *ScoredObject) isEnabled(check ks.Check, annotations, childAnnotations map[string]string) checkState {
+	var state checkState
+
 	isIn := func(csv string, key string) bool {
 		for _, v := range strings.Split(csv, ",") {
 			v = strings.TrimSpace(v)
-			if v == key {
+			if isOrImplies(v, key) {
 				return true
 			}
-			if vals, ok := impliedIgnoreAnnotations[v]; ok {
-				for i := range vals {
-					if vals[i] == key {
-						return true
-					}
-				}
-			}
 		}
 		return false
 	}
 
-	if childAnnotations != nil && so.useIgnoreChecksAnnotation && isIn(childAnnotations[ignoredChecksAnnotation], check.ID) {
-		return false
-	}
-	if childAnnotations != nil && so.useOptionalChecksAnnotation && isIn(childAnnotations[optionalChecksAnnotation], check.ID) {
+	// ignoredBy returns true if the check is ignored by a valid waiver in annotations
+	ignoredBy := func(annotations map[string]string) bool {
+		w := so.findWaiver(annotations, check.ID)
+		if w == nil {
+			return false
+		}
+		if w.problem != "" {
+			state.rejected = append(state.rejected, w)
+			return false
+		}
+		state.waiver = w
 		return true
 	}
-	if so.useIgnoreChecksAnnotation && isIn(annotations[ignoredChecksAnnotation], check.ID) {
-		return false
-	}
-	if so.useOptionalChecksAnnotation && isIn(annotations[optionalChecksAnnotation], check.ID) {
+
+	enabled := func() bool {
+		if childAnnotations != nil && so.useIgnoreChecksAnnotation && ignoredBy(childAnnotations) {
+			return false
+		}
+		if childAnnotations != nil && so.useOptionalChecksAnnotation && isIn(childAnnotations[optionalChecksAnnotation], check.ID) {
+			return true
+		}
+		if so.useIgnoreChecksAnnotation && ignoredBy(annotations) {
+			return false
+		}
+		if so.useOptionalChecksAnnotation && isIn(annotations[optionalChecksAnnotation], check.ID) {
+			return true
+		}
+
+		// Enabled optional test from command line arguments
+		if _, ok := so.enabledOptionalTests[check.ID]; ok {
+			return true
+		}
+
+		// Optional checks are disabled unless explicitly allowed above
+		if check.Optional {
+			return false
+		}
+
+		// Enabled by default
 		return true
 	}
 
-	// Enabled optional test from command line arguments
-	if _, ok := so.enabledOptionalTests[check.ID]; ok {
+	state.enabled = enabled()
+	return state
+}
+
+// isOrImplies returns true if ignoring the check ignoredID also ignores the check checkID
+func isOrImplies(ignoredID, checkID string) bool {
+	if ignoredID == checkID {
 		return true
 	}
+	for _, v := range impliedIgnoreAnnotations[ignoredID] {
+		if v == checkID {
+			return true
+		}
+	}
+	return false
+}
+
+// findWaiver returns the ignore annotation that matches the check, or nil if the check is not ignored.
+// The annotations for a single check takes precedence over the kube-score/ignore annotation.
+func (so *ScoredObject) findWaiver(annotations map[string]string, checkID string) *waiver {
+	if value, ok := annotations[ignoredCheckAnnotationPrefix+checkID]; ok {
+		return so.parseWaiver(ignoredCheckAnnotationPrefix+checkID, value)
+	}
 
-	// Optional checks are disabled unless explicitly allowed above
-	if check.Optional {
-		return false
+	// Sorted to be deterministic if the check is implied by multiple annotations
+	var names []string
+	for name := range annotations {
+		if strings.HasPrefix(name, ignoredCheckAnnotationPrefix) && isOrImplies(strings.TrimPrefix(name, ignoredCheckAnnotationPrefix), checkID) {
+			names = append(names, name)
+		}
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		return so.parseWaiver(names[0], annotations[names[0]])
+	}
+
+	for _, v := range strings.Split(annotations[ignoredChecksAnnotation], ",") {
+		if isOrImplies(strings.TrimSpace(v), checkID) {
+			return so.parseWaiver(ignoredChecksAnnotation, "")
+		}
+	}
+
+	return nil
+}
+
+// parseWaiver parses the value of an ignore annotation, on the format "until=2026-12-31; reason=JIRA-123".
+// Both keys are optional.
+func (so *ScoredObject) parseWaiver(annotation, value string) *waiver {
+	w := &waiver{annotation: annotation}
+
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		key, val = strings.TrimSpace(key), strings.TrimSpace(val)
+		switch {
+		case !ok:
+			w.problem = fmt.Sprintf("The %s annotation is invalid, expected key=value but got %q", annotation, part)
+			return w
+		case key == "reason":
+			w.reason = val
+		case key == "until":
+			// The date is in the time zone of the current time, such as the local time zone
+			until, err := time.ParseInLocation(time.DateOnly, val, so.now().Location())
+			if err != nil {
+				w.problem = fmt.Sprintf("The %s annotation is invalid, until must be a date on the format YYYY-MM-DD", annotation)
+				return w
+			}
+			w.until = until
+		default:
+			w.problem = fmt.Sprintf("The %s annotation is invalid, unknown key %q", annotation, key)
+			return w
+		}
+	}
+
+	// The waiver is valid until the end of the until date
+	if !w.until.IsZero() && !so.now().Before(w.until.AddDate(0, 0, 1)) {
+		w.problem = fmt.Sprintf("The %s annotation expired on %s", annotation, w.until.Format(time.DateOnly))
+		if w.reason != "" {
+			w.problem += ", reason: " + w.reason
+		}
+		return w
+	}
+
+	if w.reason == "" && so.requireIgnoreReason {
+		w.problem = fmt.Sprintf("The check can not be ignored by %s without a reason, use %s<check-id>: \"reason=...\"", annotation, ignoredCheckAnnotationPrefix)
 	}
 
-	// Enabled by default
-	return true
+	return w
+}
+
+func (so *ScoredObject) now() time.Time {
+	if so.currentTime.IsZero() {
+		return time.Now()
+	}
+	return so.currentTime
+}
+
+// skippedComment describes why the check was skipped
+func (w *waiver) skippedComment(checkID string) TestScoreComment {
+	c := TestScoreComment{Summary: fmt.Sprintf("Skipped because %s is ignored", checkID)}
+	if w.reason != "" {
+		c.Summary += ": " + w.reason
+	}
+	if !w.until.IsZero() {
+		c.Description = fmt.Sprintf("The ignore expires on %s", w.until.Format(time.DateOnly))
+	}
+	return c
 }
@@ -2,6 +2,7 @@ package scorecard
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/zegl/kube-score/config"
 	ks "github.com/zegl/kube-score/domain"
@@ -38,17 +39,29 @@ func (s Scorecard) NewObject(typeMeta metav1.TypeMeta, objectMeta metav1.ObjectM
 		useIgnoreChecksAnnotation:   cnf.UseIgnoreChecksAnnotation,
 		useOptionalChecksAnnotation: cnf.UseOptionalChecksAnnotation,
 		enabledOptionalTests:        cnf.EnabledOptionalTests,
+		severityOverrides:           cnf.SeverityOverrides,
+		requireIgnoreReason:         cnf.RequireIgnoreReason,
+		currentTime:                 cnf.CurrentTime,
 	}
 
 	// If this object already exists, return the previous version
-	if object, ok := s[o.resourceRefKey()]; ok {
+	if object, ok := s[o.ResourceRefKey()]; ok {
 		return object
 	}
 
-	s[o.resourceRefKey()] = o
+	s[o.ResourceRefKey()] = o
 	return o
 }
 
+// AddGroup adds the objects of other to the scorecard as objects in the group. Groups are scored independently,
+// such as a Helm chart that is rendered with different values, and can have objects with the same name.
+func (s Scorecard) AddGroup(group string, other Scorecard) {
+	for _, o := range other {
+		o.Group = group
+		s[o.ResourceRefKey()] = o
+	}
+}
+
 func (s Scorecard) AnyBelowOrEqualToGrade(threshold Grade) bool {
 	for _, o := range s {
 		if o.AnyBelowOrEqualToGrade(threshold) {
@@ -63,23 +76,33 @@ type ScoredObject struct {
 	ObjectMeta   metav1.ObjectMeta
 	FileLocation ks.FileLocation
 	Checks       []TestScore
+	// Group is the group that the object was scored in, see Scorecard.AddGroup
+	Group string `json:",omitempty"`
 
 	useIgnoreChecksAnnotation   bool
 	useOptionalChecksAnnotation bool
 	enabledOptionalTests        map[string]struct{}
+	severityOverrides           map[string]config.Severity
+	requireIgnoreReason         bool
+	currentTime                 time.Time
 }
 
 func (so *ScoredObject) AnyBelowOrEqualToGrade(threshold Grade) bool {
 	for _, o := range so.Checks {
-		if !o.Skipped && o.Grade <= threshold {
+		if !o.Skipped && !o.Suppressed && o.Grade <= threshold {
 			return true
 		}
 	}
 	return false
 }
 
-func (so *ScoredObject) resourceRefKey() string {
-	return so.TypeMeta.Kind + "/" + so.TypeMeta.APIVersion + "/" + so.ObjectMeta.Namespace + "/" + so.ObjectMeta.Name
+// ResourceRefKey uniquely identifies the object, and is used as the key in the Scorecard
+func (so *ScoredObject) ResourceRefKey() string {
+	key := so.TypeMeta.Kind + "/" + so.TypeMeta.APIVersion + "/" + so.ObjectMeta.Namespace + "/" + so.ObjectMeta.Name
+	if so.Group != "" {
+		key = so.Group + ":" + key
+	}
+	return key
 }
 
 func (so *ScoredObject) HumanFriendlyRef() string {
@@ -88,6 +111,9 @@ func (so *ScoredObject) HumanFriendlyRef() string {
 		s += "/" + so.ObjectMeta.Namespace
 	}
 	s += " " + so.TypeMeta.APIVersion + "/" + so.TypeMeta.Kind
+	if so.Group != "" {
+		s += " [" + so.Group + "]"
+	}
 	return s
 }
 
@@ -95,20 +121,51 @@ func (so *ScoredObject) Add(ts TestScore, check ks.Check, locationer ks.FileLoca
 	ts.Check = check
 	so.FileLocation = locationer.FileLocation()
 
-	var skip bool
+	// Resolve the fields of the comments to their position in the file
+	for i, c := range ts.Comments {
+		if c.Field == "" {
+			continue
+		}
+		if pos, ok := so.FileLocation.Fields.Find(c.Field); ok {
+			ts.Comments[i].Line = pos.Line
+			ts.Comments[i].Column = pos.Column
+		}
+	}
+
+	state := checkState{enabled: true}
 	if annotations != nil {
-		if len(annotations) == 1 && !so.isEnabled(check, annotations[0], nil) {
-			skip = true
+		if len(annotations) == 1 {
+			state = so.isEnabled(check, annotations[0], nil)
 		}
-		if len(annotations) == 2 && !so.isEnabled(check, annotations[0], annotations[1]) {
-			skip = true
+		if len(annotations) == 2 {
+			state = so.isEnabled(check, annotations[0], annotations[1])
 		}
 	}
 
 	// This test is ignored (via annotations), don't save the score
-	if skip {
+	if !state.enabled {
 		ts.Skipped = true
-		ts.Comments = []TestScoreComment{{Summary: fmt.Sprintf("Skipped because %s is ignored", check.ID)}}
+		if state.waiver != nil {
+			ts.Comments = []TestScoreComment{state.waiver.skippedComment(check.ID)}
+		} else {
+			ts.Comments = []TestScoreComment{{Summary: fmt.Sprintf("Skipped because %s is ignored", check.ID)}}
+		}
+	}
+
+	// Explain why the ignore annotations did not apply to the failing check
+	if !ts.Skipped && ts.Grade <= GradeWarning {
+		for _, w := range state.rejected {
+			ts.AddComment("", w.problem, "")
+		}
+	}
+
+	// Override the grade of failing checks, passing checks are never changed
+	if severity, ok := so.severityOverrides[check.ID]; ok && !ts.Skipped && ts.Grade <= GradeWarning {
+		if grade := SeverityGrade(severity); grade != ts.Grade {
+			ts.OriginalGrade = ts.Grade
+			ts.Grade = grade
+		}
+		ts.Informational = severity == config.SeverityInfo
 	}
 
 	so.Checks = append(so.Checks, ts)
@@ -119,6 +176,30 @@ type TestScore struct {
 	Grade    Grade
 	Skipped  bool
 	Comments []TestScoreComment
+
+	// OriginalGrade is the grade returned by the check, if Grade has been overridden by
+	// the configured severity overrides. It's zero if the grade has not been overridden.
+	OriginalGrade Grade
+
+	// Suppressed is true if the check failed, but all of its findings are accepted by the baseline.
+	// Suppressed checks are reported with their grade, but do not affect the exit code.
+	Suppressed bool
+
+	// Fixes are suggested changes to the object that resolve the failing check, and that are applied by
+	// "kube-score fix"
+	Fixes []Fix
+
+	// Informational is true if the check passed, but its comments are information that is worth showing. It's
+	// rendered as INFO instead of OK.
+	Informational bool
+}
+
+// Label is the label of the result in the output, such as CRITICAL or INFO
+func (ts TestScore) Label() string {
+	if ts.Informational {
+		return "INFO"
+	}
+	return ts.Grade.String()
 }
 
 type Grade int
@@ -143,11 +224,48 @@ func (g Grade) String() string {
 	}
 }
 
+// SeverityGrade returns the Grade that a Severity corresponds to
+func SeverityGrade(s config.Severity) Grade {
+	switch s {
+	case config.SeverityCritical:
+		return GradeCritical
+	case config.SeverityWarning:
+		return GradeWarning
+	case config.SeverityInfo:
+		return GradeAlmostOK
+	default:
+		return GradeAllOK
+	}
+}
+
+// Fix is a change to the object that resolves (a part of) a failing check
+type Fix struct {
+	// Path is the same as the Path of the comment that the fix resolves
+	Path        string
+	Description string
+	Patch       []PatchOperation
+}
+
+// PatchOperation is a JSON patch (RFC 6902) operation, with the path relative to the root of the object.
+// Unlike RFC 6902, "add" creates the missing parent objects of the path.
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
 type TestScoreComment struct {
 	Path             string
 	Summary          string
 	Description      string
 	DocumentationURL string
+
+	// Field is the path of the field in the object that the comment is about, see domain.FieldPositions.
+	// Line and Column are the position of the field in the file, or of its closest parent if the field is not set.
+	// They are zero if the position is not known, such as for objects that are rendered from templates.
+	Field  string `json:",omitempty"`
+	Line   int    `json:",omitempty"`
+	Column int    `json:",omitempty"`
 }
 
 func (ts *TestScore) AddComment(path, summary, description string) {
@@ -166,3 +284,33 @@ func (ts *TestScore) AddCommentWithURL(path, summary, description, documentation
 		DocumentationURL: documentationURL,
 	})
 }
+
+// AddCommentWithField adds a comment about the field at the field path in the object
+func (ts *TestScore) AddCommentWithField(path, summary, description, field string) {
+	ts.Comments = append(ts.Comments, TestScoreComment{
+		Path:        path,
+		Summary:     summary,
+		Description: description,
+		Field:       field,
+	})
+}
+
+// AddCommentWithFieldAndURL adds a comment about the field at the field path in the object, with a link to documentation
+func (ts *TestScore) AddCommentWithFieldAndURL(path, summary, description, field, documentationURL string) {
+	ts.Comments = append(ts.Comments, TestScoreComment{
+		Path:             path,
+		Summary:          summary,
+		Description:      description,
+		DocumentationURL: documentationURL,
+		Field:            field,
+	})
+}
+
+// AddFix adds a suggested fix to the score
+func (ts *TestScore) AddFix(path, description string, patch ...PatchOperation) {
+	ts.Fixes = append(ts.Fixes, Fix{
+		Path:        path,
+		Description: description,
+		Patch:       patch,
+	})
+}